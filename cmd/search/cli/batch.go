@@ -0,0 +1,337 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mule-ai/search/internal/config"
+	"github.com/mule-ai/search/internal/formatter"
+	searxnglib "github.com/mule-ai/search/internal/searxng"
+	"github.com/mule-ai/search/internal/ui"
+	"github.com/mule-ai/search/internal/validation"
+)
+
+// batchEntry is the outcome of one query from --query-file: the results
+// or the error that stopped it.
+type batchEntry struct {
+	Query   string
+	Results *searxnglib.SearchResponse
+	Err     error
+}
+
+// runQueryFile searches for every query in --query-file and writes the
+// results grouped by query. A failed query is recorded in its entry and
+// the rest continue; the run only fails when every query does.
+func runQueryFile(cmd *cobra.Command, cfgFlags *ConfigFlags) error {
+	queries, err := readQueryFile(cfgFlags.QueryFile)
+	if err != nil {
+		return err
+	}
+	if len(queries) == 0 {
+		return fmt.Errorf("no queries in %s", cfgFlags.QueryFile)
+	}
+
+	if err := validation.ValidateResultCount(cfgFlags.Results); err != nil {
+		return err
+	}
+	if err := validation.ValidateTimeout(cfgFlags.Timeout); err != nil {
+		return err
+	}
+	if err := validation.ValidateFormat(cfgFlags.Format); err != nil {
+		return err
+	}
+	if err := validation.ValidateCategory(cfgFlags.Category); err != nil {
+		return err
+	}
+	if err := validation.ValidateSafeSearch(cfgFlags.SafeSearch); err != nil {
+		return err
+	}
+	if err := validation.ValidateLanguage(cfgFlags.Language); err != nil {
+		return err
+	}
+	if err := validation.ValidatePageNumber(cfgFlags.Page); err != nil {
+		return err
+	}
+	if err := validation.ValidateTimeRange(cfgFlags.TimeRange); err != nil {
+		return err
+	}
+	if err := validation.ValidateGroupBy(cfgFlags.GroupBy); err != nil {
+		return err
+	}
+	if _, err := dateFilter(cfgFlags); err != nil {
+		return err
+	}
+	if err := validation.ValidateConcurrency(cfgFlags.Concurrency); err != nil {
+		return err
+	}
+	if err := validation.ValidateRateLimit(cfgFlags.RateLimit); err != nil {
+		return err
+	}
+	if err := validation.ValidateResultsPerEngine(cfgFlags.ResultsPerEngine); err != nil {
+		return err
+	}
+	if err := validation.ValidateMinResults(cfgFlags.MinResults); err != nil {
+		return err
+	}
+	if err := validation.ValidateOffset(cfgFlags.Offset, cfgFlags.Page); err != nil {
+		return err
+	}
+	if err := validation.ValidateOnlyLanguage(cfgFlags.OnlyLanguage); err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig(configOverride(cmd, cfgFlags))
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	warnInsecure(cfg)
+	resolveStripHTML(cmd, cfgFlags, cfg.Format)
+	if cfgFlags.Preset != "" {
+		if err := validatePreset(cfg); err != nil {
+			return err
+		}
+	}
+	if err := validation.ValidateInstanceURL(cfg.Instance); err != nil {
+		return err
+	}
+	if cfgFlags.Compact {
+		if err := validation.ValidateCompactFormat(cfg.Format); err != nil {
+			return err
+		}
+	}
+	templateFormatter, err := loadTemplate(cfgFlags.Template)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// One client for the whole batch shares the cache, the concurrency
+	// limit and the rate limit between queries. Its breaker fails the
+	// remaining queries fast once the instance keeps failing
+	client, _ := newSearchClient(cfg, cfgFlags, searxnglib.NewCircuitBreaker(0, 0))
+	entries := searchBatch(ctx, client, cfg, cfgFlags, queries)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if err := writeBatch(os.Stdout, entries, cfg, cfgFlags, templateFormatter); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("all %d queries failed: %w", len(entries), entries[0].Err)
+}
+
+// searchBatch searches for each query at once, leaving the client to hold
+// the number of requests in flight to --concurrency. Entries are returned
+// in the order of queries.
+func searchBatch(ctx context.Context, client searcher, cfg *config.Config, cfgFlags *ConfigFlags, queries []string) []batchEntry {
+	count, _ := shownCount(cfgFlags)
+	limit, minimum := fetchBounds(cfg, cfgFlags)
+	entries := make([]batchEntry, len(queries))
+	var wg sync.WaitGroup
+	for i, typed := range queries {
+		// Each query gets the operators and filters a single search would
+		query := ui.SanitizeInput(searxnglib.BuildQuery(typed, searxnglib.QueryOpts{
+			Site:     cfgFlags.Site,
+			FileType: cfgFlags.FileType,
+			InTitle:  cfgFlags.InTitle,
+		}))
+		if cfg.AppendQuery != "" {
+			query = appendQuery(query, cfg.AppendQuery)
+		}
+		entries[i].Query = query
+		if err := validation.ValidateQuery(query); err != nil {
+			entries[i].Err = err
+			continue
+		}
+
+		wg.Add(1)
+		go func(entry *batchEntry, typed string) {
+			defer wg.Done()
+			entry.Results, entry.Err = fetchResults(func(page int) (*searxnglib.SearchResponse, error) {
+				return client.SearchWithConfigContext(ctx, entry.Query, cfg.Results, cfg.Format,
+					cfg.Categories[0], cfg.Timeout, cfg.Language, cfg.SafeSearch, page, cfg.TimeRange)
+			}, max(cfgFlags.Page, 1), limit, minimum, count, nil)
+			if entry.Err == nil {
+				entry.Results.Query = entry.Query
+				if entry.Query != typed {
+					entry.Results.OriginalQuery = typed
+				}
+				entry.Err = shapeResults(entry.Results, cfg, cfgFlags)
+			}
+			if entry.Err != nil && cfg.Verbose {
+				fmt.Fprintf(os.Stderr, "Query %q failed: %v\n", entry.Query, entry.Err)
+			}
+		}(&entries[i], ui.SanitizeInput(typed))
+	}
+	wg.Wait()
+	return entries
+}
+
+// batchError is the JSON layout of a failed query from --query-file.
+type batchError struct {
+	Error string `json:"error"`
+	Query string `json:"query"`
+}
+
+// batchErrorRecord is a failed query in json-lines-with-meta output,
+// tagged like the meta and result records around it.
+type batchErrorRecord struct {
+	Type string `json:"type"`
+	batchError
+}
+
+// writeBatch writes the entries of a --query-file run. JSON output is an
+// array with each query's usual JSON object, or {"error", "query"} for a
+// failed one; ndjson puts one such object on each line. With
+// json-lines-with-meta each query's meta record starts its records, and a
+// failed query is a {"type":"error"} record. Other formats print each
+// query's output in turn, under a heading for the query.
+func writeBatch(w io.Writer, entries []batchEntry, cfg *config.Config, cfgFlags *ConfigFlags, templateFormatter *formatter.TemplateFormatter) error {
+	format := strings.ToLower(cfg.Format)
+	if templateFormatter == nil && format == "json-lines-with-meta" {
+		outputFormatter, err := newOutputFormatter(cfg.Format, cfg, cfgFlags, 0, nil)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if entry.Err != nil {
+				data, err := json.Marshal(batchErrorRecord{Type: "error", batchError: batchError{Error: firstLine(entry.Err), Query: entry.Query}})
+				if err != nil {
+					return fmt.Errorf("failed to marshal JSON: %w", err)
+				}
+				if _, err := fmt.Fprintln(w, string(data)); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := formatter.WriteTo(w, outputFormatter, entry.Results); err != nil {
+				return fmt.Errorf("failed to format results: %w", err)
+			}
+		}
+		return nil
+	}
+	if templateFormatter != nil || (format != "json" && format != "ndjson") {
+		start := startIndex(cfgFlags.Page, cfg.Results) + cfgFlags.Offset
+		outputFormatter, err := newOutputFormatter(cfg.Format, cfg, cfgFlags, start, templateFormatter)
+		if err != nil {
+			return err
+		}
+		for i, entry := range entries {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintf(w, "=== %s ===\n", entry.Query)
+			if entry.Err != nil {
+				fmt.Fprintf(w, "Error: %s\n", firstLine(entry.Err))
+				continue
+			}
+			if err := formatter.WriteTo(w, outputFormatter, entry.Results); err != nil {
+				return fmt.Errorf("failed to format results: %w", err)
+			}
+		}
+		return nil
+	}
+
+	// Entries are formatted compactly and indented together afterwards
+	jsonFormatter, err := newOutputFormatter("json", cfg, &ConfigFlags{
+		Compact:     true,
+		GroupBy:     cfgFlags.GroupBy,
+		Raw:         cfgFlags.Raw,
+		EngineStats: cfgFlags.EngineStats,
+	}, 0, nil)
+	if err != nil {
+		return err
+	}
+	values := make([]json.RawMessage, len(entries))
+	for i, entry := range entries {
+		if entry.Err != nil {
+			data, err := json.Marshal(batchError{Error: firstLine(entry.Err), Query: entry.Query})
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON: %w", err)
+			}
+			values[i] = data
+			continue
+		}
+		out, err := jsonFormatter.Format(entry.Results)
+		if err != nil {
+			return fmt.Errorf("failed to format results: %w", err)
+		}
+		values[i] = json.RawMessage(out)
+	}
+
+	if format == "ndjson" {
+		for _, value := range values {
+			if _, err := fmt.Fprintln(w, string(value)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var data []byte
+	if cfgFlags.Compact {
+		data, err = json.Marshal(values)
+	} else {
+		data, err = json.MarshalIndent(values, "", "  ")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// readQueryFile reads one query per line from path, skipping blank lines
+// and # comments.
+func readQueryFile(path string) ([]string, error) {
+	path, err := config.ExpandPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open query file: %w", err)
+	}
+	defer f.Close()
+
+	var queries []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		queries = append(queries, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read query file: %w", err)
+	}
+	return queries, nil
+}
+
+// firstLine returns the first line of err's message.
+func firstLine(err error) string {
+	return strings.SplitN(err.Error(), "\n", 2)[0]
+}
@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mule-ai/search/internal/config"
+	searxnglib "github.com/mule-ai/search/internal/searxng"
+	"github.com/mule-ai/search/internal/validation"
+)
+
+// benchmarkResult is the outcome of one benchmark search against an instance.
+type benchmarkResult struct {
+	Instance string
+	Latency  time.Duration
+	Results  int
+	Err      error
+}
+
+// newBenchmarkCommand creates the benchmark command, which compares the
+// response time of several SearXNG instances for the same query.
+func newBenchmarkCommand() *cobra.Command {
+	var timeout int
+	var sequential bool
+	var concurrency int
+
+	cmd := &cobra.Command{
+		Use:   "benchmark <query> <instance> [instance...]",
+		Short: "Compare response times of SearXNG instances",
+		Long: `Run the same search against each instance and report latency,
+result count and status, fastest first.
+
+Failed instances are listed at the end of the report; the command still
+succeeds as long as the report is produced.
+
+At most --concurrency instances are queried at once (default: the
+concurrency setting in your config file).
+
+Examples:
+  search benchmark "golang" https://search.butler.ooo https://searx.be
+  search benchmark --timeout 5 --sequential "test" https://a.example https://b.example`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validation.ValidateQuery(args[0]); err != nil {
+				return err
+			}
+			if err := validation.ValidateTimeout(timeout); err != nil {
+				return err
+			}
+			if !cmd.Flags().Changed("concurrency") {
+				// Fall back to the default if the config file can't be read
+				if cfg, err := config.LoadConfig(&config.CliConfig{}); err == nil {
+					concurrency = cfg.Concurrency
+				}
+			}
+			if sequential {
+				concurrency = 1
+			}
+			if err := validation.ValidateConcurrency(concurrency); err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			limiter := searxnglib.NewLimiter(concurrency)
+			results := runBenchmark(ctx, args[0], args[1:], time.Duration(timeout)*time.Second, limiter)
+			return writeBenchmarkReport(cmd.OutOrStdout(), results)
+		},
+	}
+
+	cmd.Flags().IntVarP(&timeout, "timeout", "t", 10, "Timeout per instance in seconds")
+	cmd.Flags().BoolVar(&sequential, "sequential", false, "Query instances one at a time (same as --concurrency 1)")
+	cmd.Flags().IntVar(&concurrency, "concurrency", config.DefaultConcurrency, "Maximum instances queried at once; higher values risk rate limiting")
+
+	return cmd
+}
+
+// runBenchmark searches every instance for query, at most
+// limiter.Limit() at a time, and returns the results sorted fastest
+// first, with failures last.
+func runBenchmark(ctx context.Context, query string, instances []string, timeout time.Duration, limiter *searxnglib.Limiter) []benchmarkResult {
+	results := make([]benchmarkResult, len(instances))
+
+	var wg sync.WaitGroup
+	for i, instance := range instances {
+		wg.Add(1)
+		go func(i int, instance string) {
+			defer wg.Done()
+			// Take the slot here rather than in the client so waiting
+			// isn't counted as latency
+			if err := limiter.Acquire(ctx); err != nil {
+				results[i] = benchmarkResult{Instance: instance, Err: err}
+				return
+			}
+			defer limiter.Release()
+			results[i] = benchmarkInstance(ctx, query, instance, timeout)
+		}(i, instance)
+	}
+	wg.Wait()
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if (results[i].Err == nil) != (results[j].Err == nil) {
+			return results[i].Err == nil
+		}
+		return results[i].Latency < results[j].Latency
+	})
+	return results
+}
+
+// benchmarkInstance runs a single timed search against instance.
+func benchmarkInstance(ctx context.Context, query, instance string, timeout time.Duration) benchmarkResult {
+	result := benchmarkResult{Instance: instance}
+	if err := validation.ValidateInstanceURL(instance); err != nil {
+		result.Err = err
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client := searxnglib.NewClientWithTimeout(instance, timeout)
+	req := searxnglib.NewSearchRequest(query)
+	req.Format = "json"
+
+	start := time.Now()
+	resp, err := client.SearchContext(ctx, req)
+	result.Latency = time.Since(start)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Results = len(resp.Results)
+	return result
+}
+
+// writeBenchmarkReport writes results as an aligned table.
+func writeBenchmarkReport(w io.Writer, results []benchmarkResult) error {
+	width := len("Instance")
+	for _, r := range results {
+		if len(r.Instance) > width {
+			width = len(r.Instance)
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "%-*s  %10s  %7s  %s\n", width, "Instance", "Latency", "Results", "Status"); err != nil {
+		return err
+	}
+	for _, r := range results {
+		latency, count, status := "-", "-", "ok"
+		if r.Err != nil {
+			// Keep the report to one line per instance
+			status = "failed: " + firstLine(r.Err)
+		} else {
+			latency = fmt.Sprintf("%dms", r.Latency.Milliseconds())
+			count = fmt.Sprintf("%d", r.Results)
+		}
+		if _, err := fmt.Fprintf(w, "%-*s  %10s  %7s  %s\n", width, r.Instance, latency, count, status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
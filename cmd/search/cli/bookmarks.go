@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mule-ai/search/internal/config"
+	searxnglib "github.com/mule-ai/search/internal/searxng"
+)
+
+// newBookmarksCommand creates the bookmarks command, which lists results
+// saved with --bookmark, and its open subcommand.
+func newBookmarksCommand() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "bookmarks",
+		Short: "List results saved with --bookmark",
+		Long: `List the results saved with --bookmark N, oldest first, numbered for
+"bookmarks open".
+
+Bookmarks are stored in ~/.config/search/bookmarks.jsonl, one JSON object per
+line with the title, url, query and time saved.
+
+With --format json, print them as a JSON array for scripts:
+  [{"index":1,"title":"...","url":"...","query":"...","time":"..."}, ...]
+
+Examples:
+  search --bookmark 2 golang generics
+  search bookmarks
+  search bookmarks open 1`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch strings.ToLower(format) {
+			case "text", "json":
+			default:
+				return fmt.Errorf("unsupported bookmarks format %q: use text or json", format)
+			}
+
+			bookmarks, err := config.LoadBookmarks()
+			if err != nil {
+				return err
+			}
+			if strings.EqualFold(format, "json") {
+				return writeBookmarksJSON(cmd.OutOrStdout(), bookmarks)
+			}
+			return writeBookmarks(cmd.OutOrStdout(), bookmarks)
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "text", "Output format: text or json")
+	cmd.AddCommand(newBookmarksOpenCommand())
+	return cmd
+}
+
+// newBookmarksOpenCommand creates the bookmarks open command, which opens
+// a saved result in the browser.
+func newBookmarksOpenCommand() *cobra.Command {
+	var configPath string
+
+	cmd := &cobra.Command{
+		Use:   "open <N>",
+		Short: "Open a bookmark in the browser",
+		Long: `Open bookmark N, as numbered by "search bookmarks", in the browser
+configured with browser in the config file, or the OS default.
+
+Examples:
+  search bookmarks open 1`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bookmarks, err := config.LoadBookmarks()
+			if err != nil {
+				return err
+			}
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 1 || n > len(bookmarks) {
+				if len(bookmarks) == 0 {
+					return fmt.Errorf("no bookmarks to open; save one with --bookmark N")
+				}
+				return fmt.Errorf("invalid bookmark %q: use a number from 1 to %d", args[0], len(bookmarks))
+			}
+
+			cfg, err := config.LoadConfig(&config.CliConfig{ConfigPath: configPath})
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+			if err := openURLs([]string{bookmarks[n-1].URL}, cfg.Browser); err != nil {
+				return fmt.Errorf("failed to open bookmark in browser: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&configPath, "config", "", "Config file path (default: ~/.config/search/config.yaml)")
+	return cmd
+}
+
+// writeBookmarks writes the numbered bookmark listing.
+func writeBookmarks(w io.Writer, bookmarks []config.Bookmark) error {
+	if len(bookmarks) == 0 {
+		_, err := fmt.Fprintln(w, "No bookmarks yet. Save a result with: search --bookmark N <query>")
+		return err
+	}
+	for i, b := range bookmarks {
+		if _, err := fmt.Fprintf(w, "[%d] %s\n    %s\n    %q, %s\n\n",
+			i+1, b.Title, b.URL, b.Query, b.Time.Local().Format("2006-01-02 15:04")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bookmarkJSON is the layout of a bookmark in bookmarks --format json.
+type bookmarkJSON struct {
+	Index int       `json:"index"`
+	Title string    `json:"title"`
+	URL   string    `json:"url"`
+	Query string    `json:"query"`
+	Time  time.Time `json:"time"`
+}
+
+// writeBookmarksJSON writes the bookmarks as a JSON array, numbered as in
+// the text listing.
+func writeBookmarksJSON(w io.Writer, bookmarks []config.Bookmark) error {
+	out := make([]bookmarkJSON, 0, len(bookmarks))
+	for i, b := range bookmarks {
+		out = append(out, bookmarkJSON{Index: i + 1, Title: b.Title, URL: b.URL, Query: b.Query, Time: b.Time})
+	}
+	data, err := json.Marshal(out)
+	if err != nil {
+		return fmt.Errorf("failed to marshal bookmarks: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// bookmarkResult saves result n of results, numbered from start+1 as in
+// the output, for --bookmark. Failures are reported to w rather than
+// failing the search that was already shown.
+func bookmarkResult(w io.Writer, results *searxnglib.SearchResponse, n, start int) {
+	i := n - start - 1
+	if i < 0 || i >= len(results.Results) {
+		if len(results.Results) == 0 {
+			fmt.Fprintf(w, "Warning: cannot bookmark result %d: there are no results\n", n)
+		} else {
+			fmt.Fprintf(w, "Warning: cannot bookmark result %d: results %d to %d are shown\n", n, start+1, start+len(results.Results))
+		}
+		return
+	}
+
+	result := results.Results[i]
+	err := config.AppendBookmark(config.Bookmark{
+		Title: result.Title,
+		URL:   result.URL,
+		Query: results.Query,
+		Time:  time.Now().UTC(),
+	})
+	if err != nil {
+		fmt.Fprintf(w, "Warning: failed to save bookmark: %v\n", err)
+		return
+	}
+	fmt.Fprintf(w, "Bookmarked [%d] %s\n", n, result.Title)
+}
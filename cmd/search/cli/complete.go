@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mule-ai/search/internal/config"
+	searxnglib "github.com/mule-ai/search/internal/searxng"
+	"github.com/mule-ai/search/internal/validation"
+)
+
+// newCompleteCommand creates the complete command, which prints the
+// instance's completions for a partial query.
+func newCompleteCommand() *cobra.Command {
+	var configPath string
+	var instance string
+	var timeout int
+
+	cmd := &cobra.Command{
+		Use:   "complete <partial>",
+		Short: "Print query completions from the instance",
+		Long: `Ask the instance's autocomplete endpoint for completions of a partial
+query and print them one per line.
+
+Instances choose their autocomplete backend, and some have none, in which
+case nothing is printed. Instances without the endpoint are reported as
+an error.
+
+Examples:
+  search complete gola
+  search complete -i https://searx.example "how to"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validation.ValidateTimeout(timeout); err != nil {
+				return err
+			}
+
+			cfg, err := config.LoadConfig(&config.CliConfig{ConfigPath: configPath, Instance: instance})
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+			if err := validation.ValidateInstanceURL(cfg.Instance); err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			client := searxnglib.NewClientWithTimeout(cfg.Instance, time.Duration(timeout)*time.Second)
+			client.SetAPIKey(cfg.APIKey)
+			if cfg.AuthType == config.AuthTypeHMAC {
+				client.SetHMACAuth(cfg.AuthSecret, cfg.AuthHeader)
+			}
+			completions, err := client.AutocompleteContext(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			return writeCompletions(cmd.OutOrStdout(), completions)
+		},
+	}
+
+	cmd.Flags().StringVarP(&instance, "instance", "i", "", "SearXNG instance URL (default: from config)")
+	cmd.Flags().IntVarP(&timeout, "timeout", "t", 5, "Timeout in seconds")
+	cmd.Flags().StringVar(&configPath, "config", "", "Config file path (default: ~/.config/search/config.yaml)")
+
+	return cmd
+}
+
+// writeCompletions writes one completion per line.
+func writeCompletions(w io.Writer, completions []string) error {
+	for _, c := range completions {
+		if _, err := fmt.Fprintln(w, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -3,8 +3,12 @@ package cli
 import (
 	"fmt"
 	"os"
+	"sort"
 
 	"github.com/spf13/cobra"
+
+	searxnglib "github.com/mule-ai/search/internal/searxng"
+	"github.com/mule-ai/search/internal/validation"
 )
 
 // AddCompletionCommand adds the shell completion command to the root command.
@@ -51,3 +55,54 @@ To install completions system-wide:
 		},
 	})
 }
+
+// registerFlagCompletions adds dynamic shell completions for the flags
+// with a fixed set of values.
+func registerFlagCompletions(cmd *cobra.Command) {
+	_ = cmd.RegisterFlagCompletionFunc("category", completeCategories)
+	_ = cmd.RegisterFlagCompletionFunc("format", completeFormats)
+	_ = cmd.RegisterFlagCompletionFunc("safe", completeSafeSearch)
+	_ = cmd.RegisterFlagCompletionFunc("group-by", completeGroupBy)
+	_ = cmd.RegisterFlagCompletionFunc("color", completeColor)
+	_ = cmd.RegisterFlagCompletionFunc("spinner", completeSpinner)
+}
+
+// completeCategories suggests the known SearXNG categories with descriptions.
+func completeCategories(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	names := searxnglib.GetCategoryNames()
+	sort.Strings(names)
+	completions := make([]string, 0, len(names))
+	for _, name := range names {
+		if cat, err := searxnglib.GetCategory(name); err == nil {
+			completions = append(completions, fmt.Sprintf("%s\t%s", name, cat.DisplayName))
+		} else {
+			completions = append(completions, name)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeFormats suggests the supported output formats.
+func completeFormats(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return validation.ValidFormats, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeGroupBy suggests the supported result grouping keys.
+func completeGroupBy(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return validation.ValidGroupByValues, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeColor suggests the --color modes.
+func completeColor(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return validation.ValidColorModes, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSpinner suggests the --spinner styles.
+func completeSpinner(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return validation.ValidSpinnerStyles, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSafeSearch suggests the safe search levels with their meaning.
+func completeSafeSearch(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return []string{"0\toff", "1\tmoderate", "2\tstrict"}, cobra.ShellCompDirectiveNoFileComp
+}
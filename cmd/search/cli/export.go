@@ -0,0 +1,188 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mule-ai/search/internal/config"
+	"github.com/mule-ai/search/internal/formatter"
+	searxnglib "github.com/mule-ai/search/internal/searxng"
+	"github.com/mule-ai/search/internal/ui"
+	"github.com/mule-ai/search/internal/validation"
+)
+
+// exportExtensions maps the formats export can write to their file
+// extensions.
+var exportExtensions = map[string]string{
+	"json":     "json",
+	"ndjson":   "jsonl",
+	"markdown": "md",
+	"md":       "md",
+	"html":     "html",
+	"text":     "txt",
+}
+
+// maxExportNameLength caps the query part of export file names.
+const maxExportNameLength = 80
+
+// newExportCommand creates the export command, which writes one search's
+// results in several formats at once.
+func newExportCommand() *cobra.Command {
+	var (
+		configPath string
+		instance   string
+		formats    string
+		dir        string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export <query>",
+		Short: "Save one search's results in several formats",
+		Long: `Search once and write the results in each of the given formats to
+<dir>/<query>.<ext>, with the query turned into a safe file name, e.g.
+"Go generics?" becomes go-generics.json.
+
+Formats are json, ndjson, markdown (md), html and text. Existing files
+are overwritten. The written paths are printed, one per line.
+
+Examples:
+  search export "golang generics"
+  search export --formats json,html --dir ~/archive "rust async"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := ui.SanitizeInput(args[0])
+			if err := validation.ValidateQuery(query); err != nil {
+				return err
+			}
+			list, err := exportFormats(formats)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.LoadConfig(&config.CliConfig{ConfigPath: configPath, Instance: instance, SafeSearch: -1})
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+			if err := validation.ValidateInstanceURL(cfg.Instance); err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			warnInsecure(cfg)
+			client := searxnglib.NewClient(cfg)
+			client.SetRateLimiter(searxnglib.NewRateLimiter(cfg.RateLimit), nil)
+			category := ""
+			if len(cfg.Categories) > 0 {
+				category = cfg.Categories[0]
+			}
+			results, err := client.SearchWithConfigContext(ctx, query, cfg.Results, "json", category,
+				cfg.Timeout, cfg.Language, cfg.SafeSearch, 1, "")
+			if err != nil {
+				return err
+			}
+
+			paths, err := writeExports(dir, exportFileName(query), results, list, category)
+			for _, path := range paths {
+				fmt.Fprintln(cmd.OutOrStdout(), path)
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&configPath, "config", "", "Config file path (default: ~/.config/search/config.yaml)")
+	cmd.Flags().StringVarP(&instance, "instance", "i", "", "SearXNG instance URL")
+	cmd.Flags().StringVar(&formats, "formats", "json,markdown,html", "Comma-separated formats to write: json, ndjson, markdown, html, text")
+	cmd.Flags().StringVar(&dir, "dir", ".", "Directory to write the files to, created if needed")
+	return cmd
+}
+
+// exportFormats parses the --formats list, dropping duplicates such as
+// markdown and md that would write the same file.
+func exportFormats(value string) ([]string, error) {
+	var list []string
+	seen := make(map[string]bool)
+	for _, format := range strings.Split(value, ",") {
+		format = strings.ToLower(strings.TrimSpace(format))
+		if format == "" {
+			continue
+		}
+		ext, ok := exportExtensions[format]
+		if !ok {
+			return nil, fmt.Errorf("unsupported export format %q: use json, ndjson, markdown, html or text", format)
+		}
+		if !seen[ext] {
+			seen[ext] = true
+			list = append(list, format)
+		}
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("no export formats given: use e.g. --formats json,markdown,html")
+	}
+	return list, nil
+}
+
+// writeExports formats results in each format and writes them to
+// dir/name.<ext>, returning the paths written so far even when one fails.
+func writeExports(dir, name string, results *searxnglib.SearchResponse, formats []string, category string) ([]string, error) {
+	dir, err := config.ExpandPath(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	var paths []string
+	for _, format := range formats {
+		f, err := formatter.NewFormatterWithOptions(format, category, formatter.Options{NoColor: true, NoWrap: true})
+		if err != nil {
+			return paths, err
+		}
+		output, err := f.Format(results)
+		if err != nil {
+			return paths, fmt.Errorf("failed to format %s: %w", format, err)
+		}
+		path := filepath.Join(dir, name+"."+exportExtensions[format])
+		if err := os.WriteFile(path, []byte(output), 0o644); err != nil {
+			return paths, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// exportFileName turns query into a file name safe on every platform:
+// lowercase letters and digits, with every other run of characters
+// replaced by a single "-".
+func exportFileName(query string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(query) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			if b.Len() >= maxExportNameLength {
+				break
+			}
+			continue
+		}
+		dash = true
+	}
+	if b.Len() == 0 {
+		return "search"
+	}
+	return b.String()
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestExportFileName(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"golang generics", "golang-generics"},
+		{"Go Generics?", "go-generics"},
+		{"../../etc/passwd", "etc-passwd"},
+		{`site:go.dev "error handling"`, "site-go-dev-error-handling"},
+		{"café über", "café-über"},
+		{"???", "search"},
+		{strings.Repeat("a", 200), strings.Repeat("a", maxExportNameLength)},
+	}
+	for _, tt := range tests {
+		if got := exportFileName(tt.query); got != tt.want {
+			t.Errorf("exportFileName(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestExportFormats(t *testing.T) {
+	got, err := exportFormats("json, Markdown,md,html")
+	if err != nil {
+		t.Fatalf("exportFormats() error = %v", err)
+	}
+	if strings.Join(got, ",") != "json,markdown,html" {
+		t.Errorf("exportFormats() = %v, want [json markdown html]", got)
+	}
+	for _, value := range []string{"json,pdf", "", " , "} {
+		if _, err := exportFormats(value); err == nil {
+			t.Errorf("exportFormats(%q) expected error", value)
+		}
+	}
+}
+
+func TestExportCommand(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"query":"golang generics","number_of_results":1,"results":[`+
+			`{"title":"Generics in Go","url":"https://go.dev/doc/tutorial/generics","content":"Type parameters","engine":"google"}]}`)
+	}))
+	defer server.Close()
+
+	dir := filepath.Join(t.TempDir(), "archive")
+	cmd := NewRootCommand()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"export", "-i", server.URL, "--dir", dir, "golang generics"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	if n := requests.Load(); n != 1 {
+		t.Errorf("export sent %d requests, want 1", n)
+	}
+	var want []string
+	for _, ext := range []string{"json", "md", "html"} {
+		want = append(want, filepath.Join(dir, "golang-generics."+ext))
+	}
+	if got := strings.Fields(out.String()); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("reported files = %v, want %v", got, want)
+	}
+
+	data, err := os.ReadFile(want[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Errorf("json export is not valid JSON: %v", err)
+	}
+	for _, path := range want[1:] {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), "Generics in Go") {
+			t.Errorf("%s is missing the result:\n%s", path, data)
+		}
+	}
+}
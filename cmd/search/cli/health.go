@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mule-ai/search/internal/config"
+	searxnglib "github.com/mule-ai/search/internal/searxng"
+	"github.com/mule-ai/search/internal/validation"
+)
+
+// newHealthCommand creates the health command, which checks that the
+// configured instance answers searches.
+func newHealthCommand() *cobra.Command {
+	var configPath string
+	var instance string
+	var timeout int
+
+	cmd := &cobra.Command{
+		Use:   "health",
+		Short: "Check that the instance is reachable and returns JSON",
+		Long: `Check the configured instance without running a search of your own:
+load its root page, then send a probe query with format=json.
+
+Reports reachability, whether the TLS certificate verified, whether the
+probe returned JSON, and how long each step took. The checks use the same
+timeout, API key and proxy environment as a real search.
+
+Exits non-zero if any check fails, with the exit code of the failure.
+
+Examples:
+  search health
+  search health -i https://searx.example`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCfg := &config.CliConfig{ConfigPath: configPath, Instance: instance}
+			if timeout != 0 {
+				if err := validation.ValidateTimeout(timeout); err != nil {
+					return err
+				}
+				cliCfg.Timeout = timeout
+			}
+			cfg, err := config.LoadConfig(cliCfg)
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+			if err := validation.ValidateInstanceURL(cfg.Instance); err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			warnInsecure(cfg)
+			report := searxnglib.NewClient(cfg).CheckHealth(ctx)
+			if err := writeHealthReport(cmd.OutOrStdout(), report); err != nil {
+				return err
+			}
+			return report.Err
+		},
+	}
+
+	cmd.Flags().StringVarP(&instance, "instance", "i", "", "SearXNG instance URL (default: from config)")
+	cmd.Flags().IntVarP(&timeout, "timeout", "t", 0, "Timeout in seconds (default: from config)")
+	cmd.Flags().StringVar(&configPath, "config", "", "Config file path (default: ~/.config/search/config.yaml)")
+
+	return cmd
+}
+
+// writeHealthReport writes one line per check.
+func writeHealthReport(w io.Writer, report *searxnglib.HealthReport) error {
+	status := func(ok bool) string {
+		if ok {
+			return "ok"
+		}
+		return "FAIL"
+	}
+	ms := func(d time.Duration) string {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+
+	lines := []string{fmt.Sprintf("Instance:  %s", report.Instance)}
+	lines = append(lines, fmt.Sprintf("Reachable: %s (%s)", status(report.Reachable), ms(report.ReachLatency)))
+	lines = append(lines, fmt.Sprintf("TLS:       %s", report.TLS))
+	if report.Reachable {
+		probe := status(report.JSON)
+		if report.JSON {
+			probe = fmt.Sprintf("ok, %d results", report.Results)
+		}
+		lines = append(lines, fmt.Sprintf("JSON:      %s (%s)", probe, ms(report.ProbeLatency)))
+	} else {
+		lines = append(lines, "JSON:      skipped")
+	}
+	healthy := "healthy"
+	if !report.Healthy() {
+		healthy = "unhealthy"
+	}
+	lines = append(lines, fmt.Sprintf("Status:    %s", healthy))
+
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
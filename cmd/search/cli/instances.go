@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mule-ai/search/internal/config"
+	searxnglib "github.com/mule-ai/search/internal/searxng"
+)
+
+// instanceListURL is the public instance list fetched by instances discover.
+// Tests point it at a local server.
+var instanceListURL = searxnglib.DefaultInstanceListURL
+
+// newInstancesCommand creates the instances command and its subcommands.
+func newInstancesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "instances",
+		Short: "Find public SearXNG instances",
+	}
+	cmd.AddCommand(newInstancesDiscoverCommand())
+	return cmd
+}
+
+// newInstancesDiscoverCommand creates the instances discover command, which
+// lists healthy public instances from searx.space.
+func newInstancesDiscoverCommand() *cobra.Command {
+	var limit int
+	var timeout int
+	var save bool
+	var configPath string
+
+	cmd := &cobra.Command{
+		Use:   "discover",
+		Short: "List healthy public instances from searx.space",
+		Long: `Fetch the searx.space instance list and print healthy HTTPS instances,
+most reliable first, then fastest.
+
+searx.space does not report whether an instance allows JSON API access,
+so check a candidate with 'search -i <url> test' or compare several with
+'search benchmark'.
+
+With --save, the top instance becomes the default in your config file.
+
+Examples:
+  search instances discover
+  search instances discover --limit 5 --save`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if limit < 1 {
+				return fmt.Errorf("--limit must be at least 1")
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			instances, err := searxnglib.FetchPublicInstances(ctx, instanceListURL, time.Duration(timeout)*time.Second)
+			if err != nil {
+				return err
+			}
+			if len(instances) == 0 {
+				return fmt.Errorf("no healthy public instances found")
+			}
+			if len(instances) > limit {
+				instances = instances[:limit]
+			}
+
+			if err := writeInstanceList(cmd.OutOrStdout(), instances); err != nil {
+				return err
+			}
+
+			if save {
+				path, err := saveDefaultInstance(configPath, instances[0].URL)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Saved %s as the default instance in %s\n", instances[0].URL, path)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&limit, "limit", "n", 10, "Maximum number of instances to list")
+	cmd.Flags().IntVarP(&timeout, "timeout", "t", 30, "Timeout for fetching the instance list in seconds")
+	cmd.Flags().BoolVar(&save, "save", false, "Save the top instance as the default in the config file")
+	cmd.Flags().StringVar(&configPath, "config", "", "Config file to update with --save (default: ~/.config/search/config.yaml)")
+
+	return cmd
+}
+
+// writeInstanceList writes instances as an aligned table.
+func writeInstanceList(w io.Writer, instances []searxnglib.PublicInstance) error {
+	width := len("Instance")
+	for _, inst := range instances {
+		if len(inst.URL) > width {
+			width = len(inst.URL)
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "%-*s  %11s  %8s  %s\n", width, "Instance", "Reliability", "Response", "Version"); err != nil {
+		return err
+	}
+	for _, inst := range instances {
+		response := "-"
+		if inst.ResponseTime > 0 {
+			response = fmt.Sprintf("%dms", int(inst.ResponseTime*1000))
+		}
+		reliability := fmt.Sprintf("%.0f%%", inst.Reliability)
+		if _, err := fmt.Fprintf(w, "%-*s  %11s  %8s  %s\n", width, inst.URL, reliability, response, inst.Version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveDefaultInstance sets instance as the default in the config file at
+// path, or in the one SEARCH_CONFIG or the default names when path is
+// empty, and returns the path written. Only the instance key changes, so
+// settings from the environment never end up in the file.
+func saveDefaultInstance(path, instance string) (string, error) {
+	var err error
+	if path == "" {
+		path = os.Getenv(config.ConfigPathEnv)
+	}
+	if path == "" {
+		path, err = config.DefaultConfigPath()
+	} else {
+		path, err = config.ExpandPath(path)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := config.SetInstanceInFile(path, instance); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -5,8 +5,17 @@
 package cli
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"math"
+	"net/url"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -15,6 +24,7 @@ import (
 	"github.com/mule-ai/search/internal/browser"
 	"github.com/mule-ai/search/internal/cache"
 	"github.com/mule-ai/search/internal/config"
+	"github.com/mule-ai/search/internal/errors"
 	"github.com/mule-ai/search/internal/formatter"
 	searxnglib "github.com/mule-ai/search/internal/searxng"
 	"github.com/mule-ai/search/internal/ui"
@@ -37,6 +47,7 @@ type ConfigFlags struct {
 	Format       string
 	Category     string
 	Timeout      int
+	Deadline     string
 	Language     string
 	SafeSearch   int
 	ConfigPath   string
@@ -45,15 +56,130 @@ type ConfigFlags struct {
 	TimeRange    string
 	Open         bool
 	OpenAll      bool
+	// Print only the first result's URL
+	First        bool
+	// Print only the results' URLs, one per line
+	URLsOnly bool
+	// Pick results to open from a numbered menu
+	Select bool
+	// When to color output: auto, always or never
+	Color        string
+	// Disable colored output; resolved from Color and --no-color before use
 	NoColor      bool
+	// Don't truncate or wrap result content in text output
+	NoWrap       bool
+	// Output width in columns; 0 detects the terminal width
+	Width        int
 	APIKey       string
+	// Skip TLS certificate verification for the instance
+	Insecure     bool
 	// Cache flags
 	CacheEnabled *bool
 	NoCache      bool
+	// Serve cache hits but never add to the cache
+	NoCacheWrite bool
 	CacheSize    int
 	CacheTTL     int
 	ClearCache   bool
 	CacheStats   bool
+	// Response size guard
+	MaxResponseSize int64
+	// Text output
+	FieldSeparator  string
+	ContentMaxLines int
+	// Query filters
+	NoAppendQuery bool
+	AutoCorrect   bool
+	// JSON output
+	Compact    bool
+	NoMetadata bool
+	// Template output
+	Template string
+	// Scoring
+	NormalizeScores bool
+	// Grouping
+	GroupBy string
+	// Exit status
+	FailOnEmpty bool
+	// Query operators
+	Site     string
+	FileType string
+	InTitle  string
+	// Browser command for --open
+	Browser string
+	// Style of the --verbose search spinner: dots, line or none
+	Spinner string
+	// Accept-Language header, independent of the search language
+	AcceptLanguage string
+	// Skip the --open-all confirmation
+	Yes bool
+	// Result to save to the bookmarks file, as numbered in the output
+	Bookmark int
+	// Show each result's ranking signals in text output
+	ExplainResults bool
+	// Report how many results each engine contributed
+	EngineStats bool
+	// Print the categories and exit instead of searching
+	ListCategories bool
+	// Lead text and markdown output with answers and infoboxes
+	Summary bool
+	// Extra key=value query parameters sent to the instance
+	Params []string
+	// Saved JSON response to format instead of searching
+	ParseFile string
+	// File of queries to search for, one per line
+	QueryFile string
+	// Emit raw result fields in JSON output
+	Raw bool
+	// Remove tracking parameters from result URLs
+	StripTracking bool
+	// Remove HTML tags and entities from result titles and content
+	StripHTML bool
+	// Shorten displayed URLs in text and table output
+	PrettyURLs bool
+	// Sections to leave out of the output
+	NoAnswers     bool
+	NoInfoboxes   bool
+	NoSuggestions bool
+	// Locale for number formatting
+	Locale string
+	// Published date filter
+	Since       string
+	Until       string
+	RequireDate bool
+	// Parallel searches
+	Concurrency int
+	// Retries for failed searches within the timeout
+	Retries int
+	// RateLimit in requests per second sent to the instance
+	RateLimit float64
+	// MaxRedirects followed by requests to the instance
+	MaxRedirects int
+	// Cap on results from any one engine
+	ResultsPerEngine int
+	// Fewest runes of content a result needs to be kept
+	MinContentLength int
+	// Fewest results to show after filtering, fetching more pages as needed
+	MinResults int
+	// Results to skip before those shown, fetching more pages as needed
+	Offset int
+	// Content language detection and filter
+	DetectLanguage bool
+	OnlyLanguage   string
+	// File to write the effective config to
+	ConfigDump     string
+	IncludeSecrets bool
+	// Instance list to pick the instance from
+	InstanceFromFile string
+	InstanceSelect   string
+	// Format parameter sent to the instance, for debugging
+	APIFormat string
+	// Print DNS, connect, TLS and first-byte timings of each request
+	Trace bool
+	// Keep the results of a response that was cut off
+	AllowPartial bool
+	// Configured preset shaping this search
+	Preset string
 }
 
 func NewRootCommand() *RootCommand {
@@ -77,15 +203,34 @@ Examples:
   search -f json "rust programming" | jq '.results[] | .title'`,
 		PersistentPreRunE: persistentPreRun(&cfgFlags),
 		RunE:              run(&cfgFlags),
-		Args:            cobra.ExactArgs(1),
+		// main prints the error and picks the exit status
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if cfgFlags.ParseFile != "" || cfgFlags.QueryFile != "" {
+				return cobra.NoArgs(cmd, args)
+			}
+			if cfgFlags.ConfigDump != "" || cfgFlags.ListCategories {
+				return cobra.MaximumNArgs(1)(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		},
 	}
 
 	addGlobalFlags(cmd.Flags(), &cfgFlags)
+	registerFlagCompletions(cmd)
 	cmd.AddCommand(newVersionCommand())
 	cmd.AddCommand(newCategoriesCommand())
+	cmd.AddCommand(newTemplatesCommand())
+	cmd.AddCommand(newBenchmarkCommand())
+	cmd.AddCommand(newInstancesCommand())
+	cmd.AddCommand(newCompleteCommand())
+	cmd.AddCommand(newHealthCommand())
+	cmd.AddCommand(newBookmarksCommand())
+	cmd.AddCommand(newExportCommand())
 	AddCompletionCommand(cmd)
 
 	// Set version template for --version flag
@@ -99,7 +244,13 @@ Examples:
 	if version.GoVersion != "unknown" {
 		versionOutput += fmt.Sprintf("\nGo version: %s", version.GoVersion)
 	}
-	cmd.SetVersionTemplate(versionOutput + "\n")
+	// search --version --format json prints the same object as version --format json
+	if data, err := versionJSON(); err == nil {
+		cmd.SetVersionTemplate(`{{if eq (.Flags.Lookup "format").Value.String "json"}}` + data +
+			"\n{{else}}" + versionOutput + "\n{{end}}")
+	} else {
+		cmd.SetVersionTemplate(versionOutput + "\n")
+	}
 	cmd.Version = version.Version
 
 	rc.Command = cmd
@@ -108,34 +259,54 @@ Examples:
 
 func addGlobalFlags(fs *pflag.FlagSet, cfg *ConfigFlags) {
 	fs.StringVarP(&cfg.Instance, "instance", "i",
-		"https://search.butler.ooo", "SearXNG instance URL")
+		config.DefaultInstance(), "SearXNG instance URL")
 	fs.IntVarP(&cfg.Results, "results", "n",
-		10, "Number of results to return")
+		10, "Most results to show, fetching more pages as needed (0 = all of the first page)")
 	fs.StringVarP(&cfg.Format, "format", "f",
-		"text", "Output format: json, markdown, text")
+		"text", "Output format: json, ndjson, jsonl-pretty, json-lines-with-meta, markdown, text, table")
 	fs.StringVarP(&cfg.Category, "category", "c",
 		"general", "Search category")
 	fs.IntVarP(&cfg.Timeout, "timeout", "t",
 		30, "Request timeout in seconds")
+	fs.StringVar(&cfg.Deadline, "deadline", "",
+		"Finish by this RFC3339 time, e.g. 2024-01-01T12:00:00Z; replaces --timeout")
 	fs.StringVarP(&cfg.Language, "language", "l",
 		"en", "Language code")
 	fs.IntVarP(&cfg.SafeSearch, "safe", "s",
 		1, "Safe search level (0, 1, 2)")
 	fs.StringVar(&cfg.ConfigPath, "config", "",
-		"Custom config file path")
+		"Custom config file path (default: $SEARCH_CONFIG, or ~/.config/search/config.yaml)")
 	fs.BoolVarP(&cfg.Verbose, "verbose", "v",
 		false, "Enable verbose output")
+	fs.BoolVar(&cfg.Trace, "trace", false,
+		"Print DNS, connect, TLS and time-to-first-byte timings of each request to stderr")
+	fs.BoolVar(&cfg.AllowPartial, "allow-partial", false,
+		"Show the results read from a response that was cut off, e.g. by a dropped connection, instead of failing")
 	fs.IntVar(&cfg.Page, "page", 1, "Page number for pagination")
 	fs.StringVar(&cfg.TimeRange, "time", "",
 		"Time range filter: day, week, month, year")
 	fs.BoolVar(&cfg.Open, "open", false,
 		"Open first result in browser")
+	fs.BoolVarP(&cfg.First, "first", "1", false,
+		"Print only the first result's URL, ignoring --format; fails if there are no results")
+	fs.BoolVar(&cfg.URLsOnly, "urls-only", false,
+		"Print only the results' URLs, one per line, ignoring --format; fails if there are no results")
 	fs.BoolVar(&cfg.OpenAll, "open-all", false,
 		"Open all results in browser")
+	fs.BoolVar(&cfg.Select, "select", false,
+		"List the results as a numbered menu and open the ones you choose in the browser (interactive terminals only)")
+	fs.StringVar(&cfg.Color, "color", "auto",
+		"When to color output: auto (only on a terminal, unless NO_COLOR is set), always or never")
 	fs.BoolVar(&cfg.NoColor, "no-color", false,
-		"Disable colored output")
+		"Disable colored output (same as --color=never)")
+	fs.BoolVar(&cfg.NoWrap, "no-wrap", false,
+		"Don't truncate or wrap result content in text output (default when stdout is not a terminal; --no-wrap=false to wrap)")
+	fs.IntVar(&cfg.Width, "width", 0,
+		"Output width in columns for text and markdown (default: terminal width, or 80)")
 	fs.StringVar(&cfg.APIKey, "api-key", "",
 		"API key for SearXNG authentication")
+	fs.BoolVar(&cfg.Insecure, "insecure", false,
+		"Skip TLS certificate verification for the instance, e.g. a self-signed LAN instance (unsafe)")
 	// Cache flags
 	cacheEnabled := true
 	fs.BoolVar(&cacheEnabled, "cache", true,
@@ -143,6 +314,8 @@ func addGlobalFlags(fs *pflag.FlagSet, cfg *ConfigFlags) {
 	cfg.CacheEnabled = &cacheEnabled
 	fs.BoolVar(&cfg.NoCache, "no-cache", false,
 		"Disable caching for this request")
+	fs.BoolVar(&cfg.NoCacheWrite, "no-cache-write", false,
+		"Serve results already cached but don't cache new ones")
 	fs.IntVar(&cfg.CacheSize, "cache-size", 100,
 		"Maximum number of cache entries")
 	fs.IntVar(&cfg.CacheTTL, "cache-ttl", 300,
@@ -151,13 +324,140 @@ func addGlobalFlags(fs *pflag.FlagSet, cfg *ConfigFlags) {
 		"Clear the cache before searching")
 	fs.BoolVar(&cfg.CacheStats, "cache-stats", false,
 		"Show cache statistics")
+	fs.Int64Var(&cfg.MaxResponseSize, "max-response-size", config.DefaultMaxResponseBytes,
+		"Maximum response size in bytes accepted from the instance")
+	fs.StringVar(&cfg.FieldSeparator, "field-separator", "",
+		"Print text results one per line joined by this separator (tab, pipe, comma, semicolon, space or one character)")
+	fs.Lookup("field-separator").NoOptDefVal = "tab"
+	fs.IntVar(&cfg.ContentMaxLines, "content-max-lines", 0,
+		"Limit each result's content to N wrapped lines in text output (0 = unlimited)")
+	fs.BoolVar(&cfg.NoAppendQuery, "no-append-query", false,
+		"Don't add the configured append_query to this search")
+	fs.BoolVar(&cfg.AutoCorrect, "auto-correct", false,
+		"Rerun with the top suggestion when a search returns few results")
+	fs.BoolVar(&cfg.Compact, "compact", false,
+		"Print JSON on a single line without indentation (json, ndjson)")
+	fs.BoolVar(&cfg.NoMetadata, "no-metadata", false,
+		"Print only the results: a bare JSON array, or markdown without the header")
+	fs.StringVar(&cfg.Template, "template", "",
+		"Format results with a Go template: a name in ~/.config/search/templates or a file path")
+	fs.BoolVar(&cfg.NormalizeScores, "normalize-scores", false,
+		"Rescale result scores to 0-1 within this result set")
+	fs.StringVar(&cfg.GroupBy, "group-by", "",
+		"Group results under headings: category (text, markdown, json)")
+	fs.BoolVar(&cfg.FailOnEmpty, "fail-on-empty", false,
+		"Exit with status 2 when the search returns no results")
+	fs.StringVar(&cfg.Site, "site", "",
+		"Restrict results to a domain (adds site:)")
+	fs.StringVar(&cfg.FileType, "filetype", "",
+		"Restrict results to a file type, e.g. pdf (adds filetype:)")
+	fs.StringVar(&cfg.InTitle, "intitle", "",
+		"Require words in the result title (adds intitle:)")
+	fs.StringVar(&cfg.AcceptLanguage, "accept-language", "",
+		"Accept-Language header for the instance UI, e.g. \"en-US,en;q=0.9\" (default: the search language)")
+	fs.StringVar(&cfg.Spinner, "spinner", "",
+		"Style of the --verbose search spinner: dots, line or none (default: dots)")
+	fs.StringVar(&cfg.Browser, "browser", "",
+		"Browser command for --open and --open-all, e.g. \"firefox --new-tab\" (default: OS default)")
+	fs.BoolVar(&cfg.Yes, "yes", false,
+		"Open every result with --open-all even when there are more than open_all_max")
+	fs.StringArrayVar(&cfg.Params, "param", nil,
+		"Extra SearXNG query parameter as key=value, e.g. theme=simple (repeatable)")
+	fs.BoolVar(&cfg.Summary, "summary", false,
+		"Show answers and infoboxes first, then the top 3 results (text and markdown)")
+	fs.BoolVar(&cfg.ExplainResults, "explain-results", false,
+		"Show each result's raw score, engines and category in text output (implied by --verbose)")
+	fs.BoolVar(&cfg.ListCategories, "list-categories", false,
+		"List the search categories, like the categories command, and exit; no query needed")
+	fs.BoolVar(&cfg.EngineStats, "engine-stats", false,
+		"Report each engine's result count and average score (to stderr, or as engine_stats in JSON output)")
+	fs.IntVar(&cfg.Bookmark, "bookmark", 0,
+		"Save result N, as numbered in the output, to ~/.config/search/bookmarks.jsonl")
+	fs.StringVar(&cfg.ParseFile, "parse-file", "",
+		"Format a saved SearXNG JSON response instead of searching (no query needed)")
+	fs.StringVar(&cfg.QueryFile, "query-file", "",
+		"Search for each query in a file, one per line (# comments), and print the results grouped by query (no query needed)")
+	fs.BoolVar(&cfg.Raw, "raw", false,
+		"Emit every field the instance sent for each result (json only)")
+	fs.BoolVar(&cfg.StripTracking, "strip-tracking", false,
+		"Remove tracking parameters (utm_*, fbclid, gclid, ...) from result URLs")
+	fs.BoolVar(&cfg.StripHTML, "strip-html", false,
+		"Remove HTML tags and decode entities in result titles and content (default for text, markdown and table; --strip-html=false to keep them)")
+	fs.BoolVar(&cfg.PrettyURLs, "prettify-urls", false,
+		"Shorten URLs in text and table output and list the full URLs after the results")
+	fs.BoolVar(&cfg.NoAnswers, "no-answers", false,
+		"Leave direct answers out of the output")
+	fs.BoolVar(&cfg.NoInfoboxes, "no-infoboxes", false,
+		"Leave infoboxes out of the output")
+	fs.BoolVar(&cfg.NoSuggestions, "no-suggestions", false,
+		"Leave query suggestions out of the output")
+	fs.StringVar(&cfg.Locale, "locale", "",
+		"Locale for result counts in text and markdown, e.g. de_DE (default: $LC_ALL, $LC_NUMERIC or $LANG)")
+	fs.StringVar(&cfg.Since, "since", "",
+		"Only keep results published on or after this date (YYYY-MM-DD or RFC3339)")
+	fs.StringVar(&cfg.Until, "until", "",
+		"Only keep results published on or before this date (YYYY-MM-DD or RFC3339)")
+	fs.BoolVar(&cfg.RequireDate, "require-date", false,
+		"With --since or --until, also drop results that have no published date")
+	fs.IntVar(&cfg.Concurrency, "concurrency", config.DefaultConcurrency,
+		"Maximum searches run at once; higher values risk rate limiting")
+	fs.IntVar(&cfg.Retries, "retries", 0,
+		"Retry a failed search up to N times; all attempts share the --timeout budget")
+	fs.Float64Var(&cfg.RateLimit, "rate-limit", config.DefaultRateLimit,
+		"Maximum requests per second sent to the instance (0 = no limit)")
+	fs.IntVar(&cfg.MaxRedirects, "max-redirects", config.DefaultMaxRedirects,
+		"Maximum redirects followed; Authorization is never sent to another host (0 = none)")
+	fs.IntVar(&cfg.ResultsPerEngine, "results-per-engine", 0,
+		"Keep at most N results from each engine, the highest scored (0 = no cap)")
+	fs.IntVar(&cfg.MinContentLength, "min-content-length", 0,
+		"Drop results whose content, without HTML, is shorter than N characters (0 = keep all)")
+	fs.IntVar(&cfg.MinResults, "min-results", 0,
+		"Fetch more pages until at least N results are left after filtering, raising --results if lower (0 = off)")
+	fs.IntVar(&cfg.Offset, "offset", 0,
+		"Skip the first N results, fetching more pages as needed; --offset 10 -n 10 shows results 11-20")
+	fs.BoolVar(&cfg.DetectLanguage, "detect-language", false,
+		"Guess each result's language from its text (adds detected_language to JSON)")
+	fs.StringVar(&cfg.OnlyLanguage, "only-language", "",
+		"Drop results detected in another language, e.g. de (implies --detect-language)")
+	fs.StringVar(&cfg.ConfigDump, "config-dump", "",
+		"Write the effective configuration to this file; without a query, exit after writing")
+	fs.BoolVar(&cfg.IncludeSecrets, "include-secrets", false,
+		"Keep api_key and auth_secret in the --config-dump output")
+	fs.StringVar(&cfg.InstanceFromFile, "instance-from-file", "",
+		"Pick the instance from a file of URLs, one per line, falling back to the next on failure")
+	fs.StringVar(&cfg.InstanceSelect, "instance-select", config.InstanceSelectRoundRobin,
+		"How --instance-from-file picks an instance: roundrobin or random")
+	fs.StringVar(&cfg.Preset, "preset", "",
+		"Apply a preset from the config file's presets, e.g. news-de; explicit flags override it")
+	fs.StringVar(&cfg.APIFormat, "api-format", "",
+		"Format parameter to request from the instance instead of json (for debugging instances)")
+	fs.MarkHidden("api-format")
 }
 
 func newVersionCommand() *cobra.Command {
-	return &cobra.Command{
+	var format string
+
+	cmd := &cobra.Command{
 		Use:   "version",
 		Short: "Show version information",
+		Long: `Show version information.
+
+With --format json, print it as a JSON object for scripts:
+  {"version":"1.0.0","git_commit":"abc123","build_date":"...","go_version":"..."}`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			switch strings.ToLower(format) {
+			case "text":
+			case "json":
+				data, err := versionJSON()
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), data)
+				return nil
+			default:
+				return fmt.Errorf("unsupported version format %q: use text or json", format)
+			}
+
 			fmt.Printf("search version %s\n", version.Version)
 			if version.GitCommit != "unknown" {
 				fmt.Printf("Git commit: %s\n", version.GitCommit)
@@ -171,10 +471,29 @@ func newVersionCommand() *cobra.Command {
 			return nil
 		},
 	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "text", "Output format: text or json")
+	return cmd
+}
+
+// versionJSON returns the build information as a JSON object.
+func versionJSON() (string, error) {
+	data, err := json.Marshal(version.Get())
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal version: %w", err)
+	}
+	return string(data), nil
 }
 
 func newCategoriesCommand() *cobra.Command {
-	return &cobra.Command{
+	var (
+		format     string
+		configPath string
+		instance   string
+		refresh    bool
+	)
+
+	cmd := &cobra.Command{
 		Use:   "categories",
 		Short: "List available search categories",
 		Long: `List all available search categories supported by SearXNG.
@@ -189,23 +508,147 @@ Categories allow you to narrow your search to specific types of content:
   - it: Information technology
   - science: Scientific research
   - files: File and document search
-  - social media: Social media content`,
+  - social media: Social media content
+
+Instances can add categories of their own and drop built-in ones. With
+--refresh, the configured instance's categories are fetched from its
+/config endpoint and cached in ~/.cache/search/categories.json; later runs list
+the cached categories for that instance. Without a cached list, or when
+the instance can't be reached, the built-in list above is shown.
+
+With --format json, print them as a JSON array for scripts:
+  [{"name":"general","display_name":"General","description":"...","example_query":"..."}, ...]
+
+Examples:
+  search categories
+  search categories --refresh -i https://searx.example`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			fmt.Println("Available Search Categories:")
-			fmt.Println()
-			
-			categories := searxnglib.GetCategoryNames()
-			for _, name := range categories {
-				cat, _ := searxnglib.GetCategory(name)
-				fmt.Printf("  %-15s %s\n", cat.Name, cat.DisplayName)
-				fmt.Printf("                 %s\n", cat.Description)
-				fmt.Printf("                 Example: search -c %s \"%s\"\n", cat.Name, cat.ExampleQuery)
-				fmt.Println()
-			}
-			
-			return nil
+			switch strings.ToLower(format) {
+			case "text", "json":
+			default:
+				return fmt.Errorf("unsupported categories format %q: use text or json", format)
+			}
+
+			cfg, err := config.LoadConfig(&config.CliConfig{ConfigPath: configPath, Instance: instance, SafeSearch: -1})
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+			if refresh {
+				if err := validation.ValidateInstanceURL(cfg.Instance); err != nil {
+					return err
+				}
+				ctx := cmd.Context()
+				if ctx == nil {
+					ctx = context.Background()
+				}
+				refreshCategories(ctx, cfg)
+			}
+
+			return writeCategories(cmd.OutOrStdout(), cfg, format)
 		},
 	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "text", "Output format: text or json")
+	cmd.Flags().StringVar(&configPath, "config", "", "Config file path (default: ~/.config/search/config.yaml)")
+	cmd.Flags().StringVarP(&instance, "instance", "i", "", "SearXNG instance URL (default: from config)")
+	cmd.Flags().BoolVar(&refresh, "refresh", false, "Fetch the instance's categories and update the cached list")
+	return cmd
+}
+
+// writeCategories lists the categories cached for cfg.Instance, or the
+// built-in ones when none are cached, as text or, with format json, a
+// JSON array. Both the categories command and --list-categories use it.
+func writeCategories(w io.Writer, cfg *config.Config, format string) error {
+	names, source := searxnglib.GetCategoryNames(), ""
+	if cached, err := config.LoadCachedCategories(cfg.Instance); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; showing the built-in categories\n", err)
+	} else if cached != nil && len(cached.Categories) > 0 {
+		names = cached.Categories
+		source = fmt.Sprintf("%s, fetched %s", cfg.Instance, cached.Fetched.Local().Format("2006-01-02 15:04"))
+	}
+
+	if strings.EqualFold(format, "json") {
+		data, err := categoriesJSON(names)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, data)
+		return err
+	}
+
+	if source != "" {
+		fmt.Fprintf(w, "Available Search Categories (%s):\n", source)
+	} else {
+		fmt.Fprintln(w, "Available Search Categories:")
+	}
+	fmt.Fprintln(w)
+	
+	for _, name := range names {
+		cat, ok := searxnglib.ValidCategories[name]
+		if !ok {
+			// A category only the instance knows about
+			fmt.Fprintf(w, "  %-15s %s\n", name, searxnglib.CategoryDisplayName(name))
+			fmt.Fprintln(w)
+			continue
+		}
+		fmt.Fprintf(w, "  %-15s %s\n", cat.Name, cat.DisplayName)
+		fmt.Fprintf(w, "                 %s\n", cat.Description)
+		fmt.Fprintf(w, "                 Example: search -c %s \"%s\"\n", cat.Name, cat.ExampleQuery)
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+// refreshCategories fetches cfg.Instance's categories and caches them.
+// Failures, such as being offline, are reported as warnings so the cached
+// or built-in list is shown instead.
+func refreshCategories(ctx context.Context, cfg *config.Config) {
+	warnInsecure(cfg)
+	client := searxnglib.NewClient(cfg)
+	categories, err := client.FetchCategories(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to fetch categories from %s: %v\n", cfg.Instance, err)
+		return
+	}
+	if len(categories) == 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %s reported no categories\n", cfg.Instance)
+		return
+	}
+	if err := config.SaveCachedCategories(cfg.Instance, categories); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache categories: %v\n", err)
+	}
+}
+
+// categoryJSON is the layout of a category in categories --format json.
+type categoryJSON struct {
+	Name         string `json:"name"`
+	DisplayName  string `json:"display_name"`
+	Description  string `json:"description"`
+	ExampleQuery string `json:"example_query"`
+}
+
+// categoriesJSON returns the named categories as a JSON array, in the
+// order the text listing uses. Categories missing from the built-in
+// registry have only a name and display name.
+func categoriesJSON(names []string) (string, error) {
+	categories := make([]categoryJSON, 0, len(names))
+	for _, name := range names {
+		cat, ok := searxnglib.ValidCategories[name]
+		if !ok {
+			cat = searxnglib.Category{Name: name, DisplayName: searxnglib.CategoryDisplayName(name)}
+		}
+		categories = append(categories, categoryJSON{
+			Name:         cat.Name,
+			DisplayName:  cat.DisplayName,
+			Description:  cat.Description,
+			ExampleQuery: cat.ExampleQuery,
+		})
+	}
+	data, err := json.Marshal(categories)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal categories: %w", err)
+	}
+	return string(data), nil
 }
 
 func persistentPreRun(cfg *ConfigFlags) func(*cobra.Command, []string) error {
@@ -219,11 +662,46 @@ func persistentPreRun(cfg *ConfigFlags) func(*cobra.Command, []string) error {
 
 func run(cfgFlags *ConfigFlags) func(*cobra.Command, []string) error {
 	return func(cmd *cobra.Command, args []string) error {
+		// Output that isn't going to a terminal is left for the reader to wrap
+		if !cmd.Flags().Changed("no-wrap") {
+			cfgFlags.NoWrap = !ui.IsInteractive(os.Stdout)
+		}
+		if err := validation.ValidateColor(cfgFlags.Color); err != nil {
+			return err
+		}
+		if cfgFlags.NoColor {
+			cfgFlags.Color = "never"
+		}
+		cfgFlags.NoColor = !ui.UseColor(cfgFlags.Color, ui.IsInteractive(os.Stdout))
+		if cfgFlags.ParseFile != "" {
+			return runParseFile(cmd, cfgFlags)
+		}
+		if cfgFlags.QueryFile != "" {
+			return runQueryFile(cmd, cfgFlags)
+		}
+		if cfgFlags.ListCategories {
+			cfg, err := config.LoadConfig(configOverride(cmd, cfgFlags))
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+			return writeCategories(os.Stdout, cfg, cfg.Format)
+		}
 		if len(args) == 0 {
+			if cfgFlags.ConfigDump != "" {
+				return runConfigDump(cmd, cfgFlags)
+			}
 			return cmd.Help()
 		}
 
-		query := args[0]
+		// The query as typed, reported alongside any rewrite of it
+		typed := ui.SanitizeInput(args[0])
+
+		// Add search operators from --site, --filetype and --intitle
+		query := searxnglib.BuildQuery(args[0], searxnglib.QueryOpts{
+			Site:     cfgFlags.Site,
+			FileType: cfgFlags.FileType,
+			InTitle:  cfgFlags.InTitle,
+		})
 
 		// Sanitize input to remove potentially dangerous characters
 		query = ui.SanitizeInput(query)
@@ -238,6 +716,10 @@ func run(cfgFlags *ConfigFlags) func(*cobra.Command, []string) error {
 		if err := validation.ValidateTimeout(cfgFlags.Timeout); err != nil {
 			return err
 		}
+		deadline, err := searchDeadline(cfgFlags)
+		if err != nil {
+			return err
+		}
 		if err := validation.ValidateFormat(cfgFlags.Format); err != nil {
 			return err
 		}
@@ -256,88 +738,174 @@ func run(cfgFlags *ConfigFlags) func(*cobra.Command, []string) error {
 		if err := validation.ValidateTimeRange(cfgFlags.TimeRange); err != nil {
 			return err
 		}
-
-		if cfgFlags.Verbose {
-			fmt.Fprintf(os.Stderr, "Query: %s\n", query)
+		if err := validation.ValidateMaxResponseSize(cfgFlags.MaxResponseSize); err != nil {
+			return err
 		}
-
-		// Load config
-		cfgOverride := &config.CliConfig{
-			ConfigPath:  cfgFlags.ConfigPath,
-			Verbose:     cfgFlags.Verbose,
-			Page:        cfgFlags.Page,
-			TimeRange:   cfgFlags.TimeRange,
+		if err := validation.ValidateFieldSeparator(cfgFlags.FieldSeparator); err != nil {
+			return err
 		}
-
-		// Only override config with CLI flags if they were explicitly set
-		if cmd.Flags().Changed("instance") {
-			cfgOverride.Instance = cfgFlags.Instance
+		if err := validation.ValidateContentMaxLines(cfgFlags.ContentMaxLines); err != nil {
+			return err
+		}
+		if err := validation.ValidateWidth(cfgFlags.Width); err != nil {
+			return err
+		}
+		if err := validation.ValidateGroupBy(cfgFlags.GroupBy); err != nil {
+			return err
+		}
+		if _, err := dateFilter(cfgFlags); err != nil {
+			return err
+		}
+		if err := validation.ValidateConcurrency(cfgFlags.Concurrency); err != nil {
+			return err
+		}
+		if err := validation.ValidateRetries(cfgFlags.Retries); err != nil {
+			return err
+		}
+		if err := validation.ValidateRateLimit(cfgFlags.RateLimit); err != nil {
+			return err
 		}
-		if cmd.Flags().Changed("results") {
-			cfgOverride.Results = cfgFlags.Results
+		if err := validation.ValidateMaxRedirects(cfgFlags.MaxRedirects); err != nil {
+			return err
 		}
-		if cmd.Flags().Changed("format") {
-			cfgOverride.Format = cfgFlags.Format
+		if err := validation.ValidateBookmark(cfgFlags.Bookmark); err != nil {
+			return err
 		}
-		if cmd.Flags().Changed("category") {
-			cfgOverride.Category = cfgFlags.Category
+		for _, param := range cfgFlags.Params {
+			if err := validation.ValidateParam(param); err != nil {
+				return err
+			}
 		}
-		if cmd.Flags().Changed("timeout") {
-			cfgOverride.Timeout = cfgFlags.Timeout
+		if err := validation.ValidateResultsPerEngine(cfgFlags.ResultsPerEngine); err != nil {
+			return err
 		}
-		if cmd.Flags().Changed("language") {
-			cfgOverride.Language = cfgFlags.Language
+		if err := validation.ValidateMinContentLength(cfgFlags.MinContentLength); err != nil {
+			return err
 		}
-		if cmd.Flags().Changed("safe") {
-			cfgOverride.SafeSearch = cfgFlags.SafeSearch
+		if err := validation.ValidateMinResults(cfgFlags.MinResults); err != nil {
+			return err
 		}
-		if cmd.Flags().Changed("cache") {
-			cfgOverride.CacheEnabled = cfgFlags.CacheEnabled
+		if err := validation.ValidateOffset(cfgFlags.Offset, cfgFlags.Page); err != nil {
+			return err
 		}
-		if cmd.Flags().Changed("no-cache") {
-			cfgOverride.NoCache = cfgFlags.NoCache
+		// Fail before searching when there is no one to pick results
+		if cfgFlags.Select && !(ui.IsInteractive(os.Stdin) && ui.IsInteractive(os.Stderr)) {
+			return validation.ValidationError{
+				Field:      "select",
+				Value:      true,
+				Message:    "--select needs an interactive terminal to prompt on",
+				Suggestion: "Use --open to open the first result or --open-all to open them all",
+			}
 		}
-		if cmd.Flags().Changed("cache-size") && cfgFlags.CacheSize > 0 {
-			cfgOverride.CacheSize = &cfgFlags.CacheSize
+		if err := validation.ValidateOnlyLanguage(cfgFlags.OnlyLanguage); err != nil {
+			return err
 		}
-		if cmd.Flags().Changed("cache-ttl") && cfgFlags.CacheTTL > 0 {
-			cfgOverride.CacheTTL = &cfgFlags.CacheTTL
+		if err := validation.ValidateInstanceSelect(cfgFlags.InstanceSelect); err != nil {
+			return err
 		}
-		if cmd.Flags().Changed("api-key") {
-			cfgOverride.APIKey = cfgFlags.APIKey
+
+		if cfgFlags.Verbose {
+			fmt.Fprintf(os.Stderr, "Query: %s\n", query)
 		}
 
-		cfg, err := config.LoadConfig(cfgOverride)
+		// Load config
+		cfg, err := config.LoadConfig(configOverride(cmd, cfgFlags))
 		if err != nil {
 			return fmt.Errorf("failed to load configuration: %w", err)
 		}
+		warnInsecure(cfg)
+		resolveStripHTML(cmd, cfgFlags, cfg.Format)
+		if cfgFlags.Preset != "" {
+			if err := validatePreset(cfg); err != nil {
+				return err
+			}
+		}
+		// The deadline replaces the timeout; the context below enforces it
+		if !deadline.IsZero() {
+			cfg.Timeout = int(math.Ceil(time.Until(deadline).Seconds()))
+		}
+		if cfgFlags.ConfigDump != "" {
+			if err := dumpConfig(cfg, cfgFlags.ConfigDump, cfgFlags.IncludeSecrets); err != nil {
+				return err
+			}
+		}
+
+		// Add the configured implicit filters and re-check the effective query
+		if cfg.AppendQuery != "" {
+			query = appendQuery(query, cfg.AppendQuery)
+			if err := validation.ValidateQuery(query); err != nil {
+				return err
+			}
+			if cfg.Verbose {
+				fmt.Fprintf(os.Stderr, "Effective query: %s\n", query)
+			}
+		}
+
+		// The instance list replaces the configured instance. Without one,
+		// the built-in default falls back to the other built-ins so that a
+		// fresh install isn't broken by one public instance being down
+		var fallbacks []string
+		builtin := false
+		if cfgFlags.InstanceFromFile != "" {
+			instances, err := instanceCandidates(cfgFlags.InstanceFromFile, cfgFlags.InstanceSelect)
+			if err != nil {
+				return err
+			}
+			cfg.Instance, fallbacks = instances[0], instances[1:]
+		} else if !cmd.Flags().Changed("instance") && cfg.Instance == config.DefaultInstance() {
+			fallbacks, builtin = config.DefaultInstances[1:], true
+		}
 
 		// Validate instance URL from final config
 		if err := validation.ValidateInstanceURL(cfg.Instance); err != nil {
 			return err
 		}
-
-		if cfg.Verbose {
-			fmt.Fprintf(os.Stderr, "Using instance: %s\n", cfg.Instance)
+		if err := validation.ValidateAcceptLanguage(cfg.AcceptLanguage); err != nil {
+			return err
+		}
+		if err := validation.ValidateSpinner(cfg.Spinner); err != nil {
+			return err
+		}
+		if err := validation.ValidateAuth(cfg.AuthType, cfg.AuthSecret); err != nil {
+			return err
+		}
+		if cfgFlags.Compact {
+			if err := validation.ValidateCompactFormat(cfg.Format); err != nil {
+				return err
+			}
+		}
+		// Check the browser before searching rather than after
+		if (cfgFlags.Open || cfgFlags.OpenAll) && cfg.Browser != "" {
+			if err := browser.ValidateCommand(cfg.Browser); err != nil {
+				return err
+			}
 		}
 
-		// Create SearXNG client
-		client := searxnglib.NewClient(cfg)
-
-		// Wrap with caching if enabled
-		var searchClient interface {
-			SearchWithConfig(query string, results int, format string, category string, timeout int, language string, safeSearch int, page int, timeRange string) (*searxnglib.SearchResponse, error)
+		// Load the template up front so a bad one fails before searching
+		templateFormatter, err := loadTemplate(cfgFlags.Template)
+		if err != nil {
+			return err
 		}
-		searchClient = client
 
-		var cachedClient *cache.CachedClient
-		if cfg.CacheEnabled {
-			cachedClient = cache.NewCachedClient(
-				client,
-				cfg.CacheSize,
-				time.Duration(cfg.CacheTTL)*time.Second,
-			)
+		if cfg.Verbose {
+			fmt.Fprintf(os.Stderr, "Using instance: %s\n", cfg.Instance)
+			if cfg.AuthType == config.AuthTypeHMAC {
+				header := cfg.AuthHeader
+				if header == "" {
+					header = config.DefaultAuthHeader
+				}
+				fmt.Fprintf(os.Stderr, "Using HMAC auth: %s header, secret %s\n", header, cfg.RedactedCopy().AuthSecret)
+			} else if cfg.APIKey != "" {
+				fmt.Fprintf(os.Stderr, "Using API key: %s\n", cfg.RedactedCopy().APIKey)
+			}
+		}
 
+		// Create SearXNG client, wrapped with caching if enabled. A failed
+		// search has already used up its retries, so one failure is enough
+		// to stop trying an instance for the rest of the run
+		breaker := searxnglib.NewCircuitBreaker(1, 0)
+		searchClient, cachedClient := newSearchClient(cfg, cfgFlags, breaker)
+		if cachedClient != nil {
 			// Handle cache clearing if requested
 			if cfgFlags.ClearCache {
 				cachedClient.ClearCache()
@@ -351,100 +919,997 @@ func run(cfgFlags *ConfigFlags) func(*cobra.Command, []string) error {
 				stats := cachedClient.GetStats()
 				fmt.Fprintf(os.Stderr, "Cache stats: %d/%d entries\n", stats.Size, stats.MaxSize)
 			}
-
-			// Create a wrapper that implements the SearchWithConfig interface
-			searchClient = &cachedSearchClient{cached: cachedClient}
 		}
 
 		// Create spinner for search operation
-		spinner := ui.NewSearchSpinner(cfg.Verbose && !cfgFlags.NoColor)
+		spinner := ui.NewSearchSpinner(cfg.Verbose && !cfgFlags.NoColor, cfg.Spinner)
+
+		// Abort the request and restore the terminal on Ctrl-C or SIGTERM
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		defer spinner.Restore()
+		if !deadline.IsZero() {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithDeadline(ctx, deadline)
+			defer cancel()
+		}
 
 		// Start spinner
 		spinner.Start()
 		startTime := time.Now()
 
 		// Perform search
-		results, err := searchClient.SearchWithConfig(
-			query,
-			cfg.Results,
-			cfg.Format,
-			cfg.Categories[0],
-			cfg.Timeout,
-			cfg.Language,
-			cfg.SafeSearch,
-			cfgFlags.Page,
-			cfgFlags.TimeRange,
-		)
+		searchPage := func(q string, page int) (*searxnglib.SearchResponse, error) {
+			for {
+				resp, err := searchClient.SearchWithConfigContext(
+					ctx,
+					q,
+					cfg.Results,
+					cfg.Format,
+					cfg.Categories[0],
+					cfg.Timeout,
+					cfg.Language,
+					cfg.SafeSearch,
+					page,
+					cfg.TimeRange,
+				)
+				if err == nil || !(searxnglib.IsInstanceFailure(err) || errors.GetErrorCode(err) == errors.ErrCodeCircuitOpen) {
+					return resp, err
+				}
+				for len(fallbacks) > 0 && !breaker.Allow(fallbacks[0]) {
+					if cfg.Verbose {
+						fmt.Fprintf(os.Stderr, "Skipping instance %s: circuit breaker open\n", fallbacks[0])
+					}
+					fallbacks = fallbacks[1:]
+				}
+				if len(fallbacks) == 0 {
+					return resp, err
+				}
+				if builtin {
+					fmt.Fprintf(os.Stderr, "Note: built-in instance %s is unreachable (%v); trying %s. "+
+						"Set your own instance with --instance or in the config file\n", cfg.Instance, err, fallbacks[0])
+				} else if cfg.Verbose {
+					fmt.Fprintf(os.Stderr, "Instance %s failed (%v); trying %s\n", cfg.Instance, err, fallbacks[0])
+				}
+				cfg.Instance, fallbacks = fallbacks[0], fallbacks[1:]
+				searchClient, _ = newSearchClient(cfg, cfgFlags, breaker)
+			}
+		}
+		var notifyPage func(page int, err error)
+		if cfg.Verbose {
+			notifyPage = func(page int, err error) {
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Page %d failed (%v); showing the results so far\n", page, err)
+					return
+				}
+				fmt.Fprintf(os.Stderr, "Fetching page %d for more results\n", page)
+			}
+		}
+		count, err := shownCount(cfgFlags)
+		if err != nil {
+			return err
+		}
+		limit, minimum := fetchBounds(cfg, cfgFlags)
+		search := func(q string) (*searxnglib.SearchResponse, error) {
+			pages := 0
+			resp, err := fetchResults(func(page int) (*searxnglib.SearchResponse, error) {
+				pages++
+				return searchPage(q, page)
+			}, max(cfgFlags.Page, 1), limit, minimum, count, notifyPage)
+			if cfg.Verbose && err == nil {
+				fmt.Fprintf(os.Stderr, "Fetched %d page(s)\n", pages)
+			}
+			return resp, err
+		}
+		results, err := search(query)
+		// Running out of --deadline is a timeout, not a cancellation
+		if err != nil && ctx.Err() == context.DeadlineExceeded {
+			err = errors.NetworkError(context.DeadlineExceeded).
+				WithSuggestion("The --deadline passed before the instance answered")
+		}
 
 		// Calculate search duration
 		duration := time.Since(startTime)
 
 		// Stop spinner with results
 		if err != nil {
+			if errors.GetErrorCode(err) == errors.ErrCodeCanceled {
+				spinner.Restore()
+				return err
+			}
 			spinner.StopWithError(err)
 			return fmt.Errorf("search failed: %w", err)
 		}
 
 		spinner.Stop(len(results.Results), fmt.Sprintf("%.2fs", duration.Seconds()))
 
+		// Report the query that was actually sent, including any appended
+		// filters, and what was typed when they differ
+		results.Query = query
+		if query != typed {
+			results.OriginalQuery = typed
+		}
+
+		// Retry with SearXNG's suggestion when the query looks misspelled
+		if cfgFlags.AutoCorrect {
+			if corrected := autoCorrectQuery(results, query, cfg.AutoCorrectThreshold); corrected != "" {
+				if cfg.Verbose {
+					fmt.Fprintf(os.Stderr, "Few results, retrying with suggestion: %s\n", corrected)
+				}
+				retry, err := search(corrected)
+				if err != nil {
+					if cfg.Verbose {
+						fmt.Fprintf(os.Stderr, "Auto-correct search failed: %v\n", err)
+					}
+				} else if len(retry.Results) > len(results.Results) {
+					retry.Query = corrected
+					retry.OriginalQuery = typed
+					results = retry
+				}
+			}
+		}
+
 		if !cfg.Verbose {
 			// If not verbose, spinner already showed the results count
 		} else {
 			fmt.Fprintf(os.Stderr, "Found %d results\n", len(results.Results))
+			if results.Partial {
+				fmt.Fprintln(os.Stderr, "Warning: the response was cut off; showing only the results read before that")
+			}
 		}
 
-		// Format and output results - use category-aware formatter
-		category := ""
-		if len(cfg.Categories) > 0 {
-			category = cfg.Categories[0]
+		return writeResults(results, cfg, cfgFlags, templateFormatter)
+	}
+}
+
+// validatePreset checks the settings a preset can change, which, unlike
+// the flags they stand in for, were not validated before loading.
+func validatePreset(cfg *config.Config) error {
+	if err := validation.ValidateCategory(cfg.Categories[0]); err != nil {
+		return err
+	}
+	if err := validation.ValidateLanguage(cfg.Language); err != nil {
+		return err
+	}
+	if err := validation.ValidateTimeRange(cfg.TimeRange); err != nil {
+		return err
+	}
+	if err := validation.ValidateResultCount(cfg.Results); err != nil {
+		return err
+	}
+	if err := validation.ValidateFormat(cfg.Format); err != nil {
+		return err
+	}
+	return validation.ValidateSafeSearch(cfg.SafeSearch)
+}
+
+// maxResultPages caps how many instance pages one search fetches to fill
+// --results or --min-results.
+const maxResultPages = 10
+
+// fetchResults fetches page start and, while fewer than limit results
+// have been collected or count reports fewer than minimum of them, the
+// pages after it, appending the results each adds to the first page's
+// response. count, for --min-results, is the number of results left after
+// filtering; nil counts every result. It stops early once the instance's
+// reported total has been collected, at a page with no results not
+// already seen, or after maxResultPages pages. With a limit and minimum of
+// 0 only the first page is fetched. The response records how many pages
+// were fetched in PagesFetched.
+//
+// A failure on a later page keeps the results so far; notify, if not nil,
+// is called before each later page and with the error of one that fails.
+func fetchResults(fetch func(page int) (*searxnglib.SearchResponse, error), start, limit, minimum int, count func([]searxnglib.SearchResult) int, notify func(page int, err error)) (*searxnglib.SearchResponse, error) {
+	first, err := fetch(start)
+	if err != nil {
+		return first, err
+	}
+	first.PagesFetched = 1
+	if limit == 0 && minimum == 0 {
+		return first, nil
+	}
+	if count == nil {
+		count = func(results []searxnglib.SearchResult) int { return len(results) }
+	}
+
+	seen := make(map[string]bool, len(first.Results))
+	for _, r := range first.Results {
+		seen[r.URL] = true
+	}
+	more := func() bool {
+		n := len(first.Results)
+		short := n < limit || (minimum > 0 && count(first.Results) < minimum)
+		return n > 0 && short && (first.NumberOfResults <= 0 || n < first.NumberOfResults)
+	}
+	for page := start + 1; more() && page < start+maxResultPages; page++ {
+		if notify != nil {
+			notify(page, nil)
 		}
-		outputFormatter, err := formatter.NewFormatterForCategory(cfg.Format, category, cfgFlags.NoColor)
+		resp, err := fetch(page)
 		if err != nil {
-			return fmt.Errorf("failed to create formatter: %w", err)
+			if notify != nil {
+				notify(page, err)
+			}
+			break
+		}
+		first.PagesFetched++
+		first.Partial = first.Partial || resp.Partial
+		added := 0
+		for _, r := range resp.Results {
+			if !seen[r.URL] {
+				seen[r.URL] = true
+				first.Results = append(first.Results, r)
+				added++
+			}
 		}
+		if added == 0 {
+			break
+		}
+	}
+	return first, nil
+}
 
-		output, err := outputFormatter.Format(results)
-		if err != nil {
-			return fmt.Errorf("failed to format results: %w", err)
+// resultLimit is the most results to show: the --results or configured
+// count, raised to --min-results when lower, or 0 for everything on the
+// first page with --results 0.
+func resultLimit(cfg *config.Config, cfgFlags *ConfigFlags) int {
+	if cfgFlags.Results == 0 {
+		return cfgFlags.MinResults
+	}
+	return max(cfg.Results, cfgFlags.MinResults)
+}
+
+// fetchBounds returns the limit and minimum passed to fetchResults. With
+// --offset the skipped results must be fetched too, and, as the window is
+// taken after filtering, they count towards the minimum.
+func fetchBounds(cfg *config.Config, cfgFlags *ConfigFlags) (limit, minimum int) {
+	limit, minimum = resultLimit(cfg, cfgFlags), cfgFlags.MinResults
+	if cfgFlags.Offset > 0 {
+		limit += cfgFlags.Offset
+		minimum = limit
+	}
+	return limit, minimum
+}
+
+// shownCount returns a function counting the results left once the
+// pipeline has processed them, for --min-results, or nil when there is no
+// pipeline and every result counts.
+func shownCount(cfgFlags *ConfigFlags) (func([]searxnglib.SearchResult) int, error) {
+	pipeline, _, err := resultPipeline(cfgFlags)
+	if err != nil || len(pipeline) == 0 {
+		return nil, err
+	}
+	return func(results []searxnglib.SearchResult) int {
+		// Processors may rewrite results in place, so work on a copy
+		return len(pipeline.Process(append([]searxnglib.SearchResult(nil), results...)))
+	}, nil
+}
+
+// warnInsecure warns on stderr when cfg turns off TLS certificate
+// verification, so it is never left on unnoticed.
+func warnInsecure(cfg *config.Config) {
+	if cfg.Insecure {
+		fmt.Fprintf(os.Stderr, "WARNING: TLS certificate verification is disabled (insecure); the connection to %s can be intercepted\n", cfg.Instance)
+	}
+}
+
+// searcher is the search method shared by the plain and cached clients.
+type searcher interface {
+	SearchWithConfigContext(ctx context.Context, query string, results int, format string, category string, timeout int, language string, safeSearch int, page int, timeRange string) (*searxnglib.SearchResponse, error)
+}
+
+// newSearchClient creates a client for cfg.Instance that requests
+// --api-format, or json when empty, and with --trace prints the timings
+// of each request. Searches skip the instance while breaker is open for
+// it; clients for different instances can share one breaker. When caching
+// is enabled the client is wrapped, and the cache is returned as well.
+func newSearchClient(cfg *config.Config, cfgFlags *ConfigFlags, breaker *searxnglib.CircuitBreaker) (searcher, *cache.CachedClient) {
+	client := searxnglib.NewClient(cfg)
+	client.SetCircuitBreaker(breaker)
+	client.SetAPIFormat(strings.TrimSpace(cfgFlags.APIFormat))
+	client.SetAllowPartial(cfgFlags.AllowPartial)
+	if cfgFlags.Trace {
+		client.SetTrace(func(timings searxnglib.Timings) {
+			fmt.Fprintf(os.Stderr, "Trace: %s\n", timings)
+		})
+	}
+	client.SetLimiter(searxnglib.NewLimiter(cfg.Concurrency))
+	var notifyRetry searxnglib.RetryNotify
+	if cfg.Verbose {
+		notifyRetry = func(attempt int, err error, remaining time.Duration) {
+			fmt.Fprintf(os.Stderr, "Attempt %d failed (%v); retrying with %s of the timeout budget left\n", attempt, err, remaining)
+		}
+	}
+	client.SetRetries(cfg.Retries, notifyRetry)
+	var notifyRateLimit searxnglib.RateLimitNotify
+	if cfg.Verbose {
+		notifyRateLimit = func(wait time.Duration) {
+			fmt.Fprintf(os.Stderr, "Waiting %s to respect the rate limit\n", wait.Round(time.Millisecond))
+		}
+	}
+	client.SetRateLimiter(searxnglib.NewRateLimiter(cfg.RateLimit), notifyRateLimit)
+
+	if !cfg.CacheEnabled {
+		return client, nil
+	}
+	cachedClient := cache.NewCachedClient(
+		client,
+		cfg.CacheSize,
+		time.Duration(cfg.CacheTTL)*time.Second,
+	)
+	cachedClient.SetNegativeTTL(time.Duration(cfg.CacheNegativeTTL) * time.Second)
+	return &cachedSearchClient{cached: cachedClient, readOnly: cfgFlags.NoCacheWrite}, cachedClient
+}
+
+// instanceCandidates reads and validates the --instance-from-file list
+// and orders it for this run: the selected instance, then fallbacks.
+func instanceCandidates(path, mode string) ([]string, error) {
+	instances, err := config.ReadInstanceList(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, instance := range instances {
+		if err := validation.ValidateInstanceURL(instance); err != nil {
+			return nil, err
+		}
+	}
+	return config.SelectInstances(path, instances, strings.ToLower(mode))
+}
+
+// runConfigDump writes the effective configuration to the --config-dump
+// path without searching.
+func runConfigDump(cmd *cobra.Command, cfgFlags *ConfigFlags) error {
+	cfg, err := config.LoadConfig(configOverride(cmd, cfgFlags))
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	return dumpConfig(cfg, cfgFlags.ConfigDump, cfgFlags.IncludeSecrets)
+}
+
+// dumpConfig writes cfg to path. The API key and HMAC secret are left out
+// unless includeSecrets is set.
+func dumpConfig(cfg *config.Config, path string, includeSecrets bool) error {
+	path, err := config.ExpandPath(path)
+	if err != nil {
+		return err
+	}
+
+	dump := *cfg
+	if !includeSecrets {
+		dump.APIKey = ""
+		dump.AuthSecret = ""
+	}
+	if err := config.WriteConfig(path, dump); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Wrote configuration to %s\n", path)
+	return nil
+}
+
+// runParseFile formats the saved response named by --parse-file without
+// contacting an instance.
+func runParseFile(cmd *cobra.Command, cfgFlags *ConfigFlags) error {
+	if err := validation.ValidateFormat(cfgFlags.Format); err != nil {
+		return err
+	}
+	if err := validation.ValidateFieldSeparator(cfgFlags.FieldSeparator); err != nil {
+		return err
+	}
+	if err := validation.ValidateContentMaxLines(cfgFlags.ContentMaxLines); err != nil {
+		return err
+	}
+	if err := validation.ValidateWidth(cfgFlags.Width); err != nil {
+		return err
+	}
+	if err := validation.ValidateGroupBy(cfgFlags.GroupBy); err != nil {
+		return err
+	}
+	if _, err := dateFilter(cfgFlags); err != nil {
+		return err
+	}
+	if err := validation.ValidateResultsPerEngine(cfgFlags.ResultsPerEngine); err != nil {
+		return err
+	}
+	if err := validation.ValidateMinContentLength(cfgFlags.MinContentLength); err != nil {
+		return err
+	}
+	if err := validation.ValidateOnlyLanguage(cfgFlags.OnlyLanguage); err != nil {
+		return err
+	}
+	if err := validation.ValidateBookmark(cfgFlags.Bookmark); err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig(configOverride(cmd, cfgFlags))
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	resolveStripHTML(cmd, cfgFlags, cfg.Format)
+	if cfgFlags.Compact {
+		if err := validation.ValidateCompactFormat(cfg.Format); err != nil {
+			return err
+		}
+	}
+	if (cfgFlags.Open || cfgFlags.OpenAll) && cfg.Browser != "" {
+		if err := browser.ValidateCommand(cfg.Browser); err != nil {
+			return err
+		}
+	}
+
+	templateFormatter, err := loadTemplate(cfgFlags.Template)
+	if err != nil {
+		return err
+	}
+
+	results, err := readResponseFile(cfgFlags.ParseFile)
+	if err != nil {
+		return err
+	}
+	if cfg.Verbose {
+		fmt.Fprintf(os.Stderr, "Parsed %d results from %s\n", len(results.Results), cfgFlags.ParseFile)
+	}
+
+	return writeResults(results, cfg, cfgFlags, templateFormatter)
+}
+
+// readResponseFile reads and parses a saved SearXNG JSON response.
+func readResponseFile(path string) (*searxnglib.SearchResponse, error) {
+	expanded, err := config.ExpandPath(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(expanded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response file: %w", err)
+	}
+	results, err := searxnglib.ParseResponse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s as a SearXNG JSON response: %w", path, err)
+	}
+	return results, nil
+}
+
+// searchDeadline parses --deadline, which must be in the future. It
+// returns the zero time when no deadline is set.
+func searchDeadline(cfgFlags *ConfigFlags) (time.Time, error) {
+	if err := validation.ValidateDeadline(cfgFlags.Deadline, time.Now()); err != nil {
+		return time.Time{}, err
+	}
+	if cfgFlags.Deadline == "" {
+		return time.Time{}, nil
+	}
+	deadline, _ := time.Parse(time.RFC3339, cfgFlags.Deadline)
+	return deadline, nil
+}
+
+// dateFilter builds the published date filter from --since, --until and
+// --require-date, validating the dates.
+func dateFilter(cfgFlags *ConfigFlags) (searxnglib.DateFilter, error) {
+	filter := searxnglib.DateFilter{RequireDate: cfgFlags.RequireDate}
+	if err := validation.ValidateDate("since", cfgFlags.Since); err != nil {
+		return filter, err
+	}
+	if err := validation.ValidateDate("until", cfgFlags.Until); err != nil {
+		return filter, err
+	}
+	if cfgFlags.Since != "" {
+		filter.Since, _ = searxnglib.ParseFilterDate(cfgFlags.Since, false)
+	}
+	if cfgFlags.Until != "" {
+		filter.Until, _ = searxnglib.ParseFilterDate(cfgFlags.Until, true)
+	}
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && filter.Since.After(filter.Until) {
+		return filter, validation.ValidationError{
+			Field:   "since",
+			Value:   cfgFlags.Since,
+			Message: fmt.Sprintf("--since is after --until (%s)", cfgFlags.Until),
 		}
+	}
+	return filter, nil
+}
+
+// startIndex is the number of results on the pages before page, so
+// numbering continues across pages.
+func startIndex(page, resultsPerPage int) int {
+	if page <= 1 {
+		return 0
+	}
+	return (page - 1) * resultsPerPage
+}
+
+// resultPipeline builds the processors the flags enable, in the order
+// they run: filters that drop results first, then the per-engine cap so it
+// keeps the best remaining results, then rewrites. filtered reports
+// whether any filter can drop results.
+func resultPipeline(cfgFlags *ConfigFlags) (pipeline searxnglib.Pipeline, filtered bool, err error) {
+	filter, err := dateFilter(cfgFlags)
+	if err != nil {
+		return nil, false, err
+	}
+	// Later steps, such as language detection, see the text without markup
+	if cfgFlags.StripHTML {
+		pipeline = append(pipeline, searxnglib.HTMLStripper{})
+	}
+	if filter.Active() {
+		pipeline = append(pipeline, filter)
+		filtered = true
+	}
+	if cfgFlags.MinContentLength > 0 {
+		pipeline = append(pipeline, searxnglib.ContentLengthFilter(cfgFlags.MinContentLength))
+		filtered = true
+	}
+	if cfgFlags.DetectLanguage || cfgFlags.OnlyLanguage != "" {
+		pipeline = append(pipeline, searxnglib.LanguageDetector{})
+	}
+	if cfgFlags.OnlyLanguage != "" {
+		pipeline = append(pipeline, searxnglib.LanguageFilter(cfgFlags.OnlyLanguage))
+		filtered = true
+	}
+	if cfgFlags.ResultsPerEngine > 0 {
+		pipeline = append(pipeline, searxnglib.EngineLimit(cfgFlags.ResultsPerEngine))
+	}
+
+	// Cleaned URLs are both printed and opened
+	if cfgFlags.StripTracking {
+		pipeline = append(pipeline, searxnglib.TrackingStripper{})
+	}
+	if cfgFlags.NormalizeScores {
+		pipeline = append(pipeline, searxnglib.ScoreNormalizer{})
+	}
+	return pipeline, filtered, nil
+}
+
+// resolveStripHTML turns --strip-html on for the formats read by people,
+// unless it was given explicitly. JSON output keeps the content as the
+// instance sent it.
+func resolveStripHTML(cmd *cobra.Command, cfgFlags *ConfigFlags, format string) {
+	if cmd.Flags().Changed("strip-html") {
+		return
+	}
+	switch strings.ToLower(format) {
+	case "text", "markdown", "table":
+		cfgFlags.StripHTML = true
+	default:
+		cfgFlags.StripHTML = false
+	}
+}
+
+// configOverride collects the CLI flags that override the config file.
+// Most are only applied when set explicitly.
+func configOverride(cmd *cobra.Command, cfgFlags *ConfigFlags) *config.CliConfig {
+	cfgOverride := &config.CliConfig{
+		ConfigPath:  cfgFlags.ConfigPath,
+		Verbose:     cfgFlags.Verbose,
+		Page:        cfgFlags.Page,
+		TimeRange:   cfgFlags.TimeRange,
+		Preset:      cfgFlags.Preset,
+	}
 
-		fmt.Print(output)
+	// Only override config with CLI flags if they were explicitly set
+	if cmd.Flags().Changed("instance") {
+		cfgOverride.Instance = cfgFlags.Instance
+	}
+	if cmd.Flags().Changed("results") {
+		cfgOverride.Results = cfgFlags.Results
+	}
+	if cmd.Flags().Changed("format") {
+		cfgOverride.Format = cfgFlags.Format
+	}
+	if cmd.Flags().Changed("category") {
+		cfgOverride.Category = cfgFlags.Category
+	}
+	if cmd.Flags().Changed("timeout") {
+		cfgOverride.Timeout = cfgFlags.Timeout
+	}
+	if cmd.Flags().Changed("language") {
+		cfgOverride.Language = cfgFlags.Language
+	}
+	if cmd.Flags().Changed("safe") {
+		cfgOverride.SafeSearch = cfgFlags.SafeSearch
+	}
+	if cmd.Flags().Changed("cache") {
+		cfgOverride.CacheEnabled = cfgFlags.CacheEnabled
+	}
+	if cmd.Flags().Changed("no-cache") {
+		cfgOverride.NoCache = cfgFlags.NoCache
+	}
+	if cmd.Flags().Changed("cache-size") && cfgFlags.CacheSize > 0 {
+		cfgOverride.CacheSize = &cfgFlags.CacheSize
+	}
+	if cmd.Flags().Changed("cache-ttl") && cfgFlags.CacheTTL > 0 {
+		cfgOverride.CacheTTL = &cfgFlags.CacheTTL
+	}
+	if cmd.Flags().Changed("api-key") {
+		cfgOverride.APIKey = cfgFlags.APIKey
+	}
+	if cmd.Flags().Changed("insecure") {
+		cfgOverride.Insecure = &cfgFlags.Insecure
+	}
+	if cmd.Flags().Changed("max-response-size") {
+		cfgOverride.MaxResponseBytes = cfgFlags.MaxResponseSize
+	}
+	if cmd.Flags().Changed("no-append-query") {
+		cfgOverride.NoAppendQuery = cfgFlags.NoAppendQuery
+	}
+	if cmd.Flags().Changed("spinner") {
+		cfgOverride.Spinner = cfgFlags.Spinner
+	}
+	if cmd.Flags().Changed("browser") {
+		cfgOverride.Browser = cfgFlags.Browser
+	}
+	if cmd.Flags().Changed("accept-language") {
+		cfgOverride.AcceptLanguage = cfgFlags.AcceptLanguage
+	}
+	if cmd.Flags().Changed("concurrency") {
+		cfgOverride.Concurrency = cfgFlags.Concurrency
+	}
+	if cmd.Flags().Changed("retries") {
+		cfgOverride.Retries = &cfgFlags.Retries
+	}
+	if cmd.Flags().Changed("rate-limit") {
+		cfgOverride.RateLimit = &cfgFlags.RateLimit
+	}
+	if cmd.Flags().Changed("max-redirects") {
+		cfgOverride.MaxRedirects = &cfgFlags.MaxRedirects
+	}
+	if len(cfgFlags.Params) > 0 {
+		cfgOverride.ExtraParams = make(map[string]string, len(cfgFlags.Params))
+		for _, param := range cfgFlags.Params {
+			key, value, _ := strings.Cut(param, "=")
+			cfgOverride.ExtraParams[key] = value
+		}
+	}
 
-		// Handle browser opening flags
-		if cfgFlags.Open || cfgFlags.OpenAll {
-			if err := openResults(results, cfgFlags.OpenAll, cfgFlags.Verbose); err != nil {
-				return fmt.Errorf("failed to open results in browser: %w", err)
+	return cfgOverride
+}
+
+// loadTemplate resolves and parses the --template value, or returns nil
+// when name is empty.
+func loadTemplate(name string) (*formatter.TemplateFormatter, error) {
+	if name == "" {
+		return nil, nil
+	}
+	path, err := config.ResolveTemplate(name)
+	if err != nil {
+		return nil, err
+	}
+	return formatter.NewTemplateFormatterFromFile(path)
+}
+
+// writeResults applies the date filter, per-engine cap and URL cleaning,
+// formats results to stdout and handles --first, --fail-on-empty, --open
+// and --open-all.
+func writeResults(results *searxnglib.SearchResponse, cfg *config.Config, cfgFlags *ConfigFlags, templateFormatter *formatter.TemplateFormatter) error {
+	if err := shapeResults(results, cfg, cfgFlags); err != nil {
+		return err
+	}
+
+	// "I'm feeling lucky": the bare URL, for $(search -1 ...)
+	if cfgFlags.First {
+		if len(results.Results) == 0 {
+			return errors.EmptyResults(results.Query)
+		}
+		_, err := fmt.Fprintln(os.Stdout, results.Results[0].URL)
+		return err
+	}
+
+	// A menu of results to open replaces the usual output
+	if cfgFlags.Select {
+		return selectResults(results, cfg.Browser, os.Stdin, os.Stderr)
+	}
+
+	// Every URL, for xargs, link checkers and archivers
+	if cfgFlags.URLsOnly {
+		if len(results.Results) == 0 {
+			return errors.EmptyResults(results.Query)
+		}
+		w := bufio.NewWriter(os.Stdout)
+		for _, result := range results.Results {
+			fmt.Fprintln(w, result.URL)
+		}
+		return w.Flush()
+	}
+
+	start := startIndex(cfgFlags.Page, cfg.Results) + cfgFlags.Offset
+	outputFormatter, err := newOutputFormatter(cfg.Format, cfg, cfgFlags, start, templateFormatter)
+	if err != nil {
+		return err
+	}
+
+	// Streaming formatters write each result as it's formatted, though
+	// the response has been decoded in full by now
+	if err := formatter.WriteTo(os.Stdout, outputFormatter, results); err != nil {
+		return fmt.Errorf("failed to format results: %w", err)
+	}
+
+	// JSON with metadata carries the stats itself; other output keeps
+	// stdout for the results
+	if cfgFlags.EngineStats && (templateFormatter != nil || !strings.EqualFold(cfg.Format, "json") || cfgFlags.NoMetadata) {
+		writeEngineStats(os.Stderr, searxnglib.EngineStats(results.Results))
+	}
+
+	if cfgFlags.Bookmark > 0 {
+		bookmarkResult(os.Stderr, results, cfgFlags.Bookmark, start)
+	}
+
+	// Output is still printed so scripts see the (empty) result set
+	if cfgFlags.FailOnEmpty && len(results.Results) == 0 {
+		return errors.EmptyResults(results.Query)
+	}
+
+	// Handle browser opening flags
+	if cfgFlags.Open || cfgFlags.OpenAll {
+		if cfgFlags.OpenAll {
+			interactive := ui.IsInteractive(os.Stdin) && ui.IsInteractive(os.Stderr)
+			if err := checkOpenAllLimit(openAllCount(results), cfg.OpenAllMax, cfgFlags.Yes, interactive, os.Stdin, os.Stderr); err != nil {
+				return err
 			}
 		}
+		if err := openResults(results, cfgFlags.OpenAll, cfgFlags.Verbose, cfg.Browser); err != nil {
+			return fmt.Errorf("failed to open results in browser: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// writeEngineStats writes the --engine-stats table.
+func writeEngineStats(w io.Writer, stats []searxnglib.EngineStat) {
+	if len(stats) == 0 {
+		fmt.Fprintln(w, "Engine stats: no results")
+		return
+	}
+	width := len("Engine")
+	for _, s := range stats {
+		width = max(width, len(s.Engine))
+	}
+	fmt.Fprintf(w, "%-*s  %7s  %9s\n", width, "Engine", "Results", "Avg score")
+	for _, s := range stats {
+		fmt.Fprintf(w, "%-*s  %7d  %9.2f\n", width, s.Engine, s.Results, s.AverageScore)
+	}
+}
+
+// autoCorrectQuery returns the suggestion to rerun the search with when
+// resp has fewer than threshold results, or "" if no rerun is needed.
+func autoCorrectQuery(resp *searxnglib.SearchResponse, query string, threshold int) string {
+	if len(resp.Results) >= threshold || len(resp.Suggestions) == 0 {
+		return ""
+	}
+	suggestion := ui.SanitizeInput(resp.Suggestions[0])
+	if suggestion == "" || strings.EqualFold(suggestion, query) {
+		return ""
+	}
+	return suggestion
+}
+
+// appendQuery joins the sanitized extra filter text onto query.
+func appendQuery(query, extra string) string {
+	extra = ui.SanitizeInput(extra)
+	if extra == "" {
+		return query
+	}
+	return query + " " + extra
+}
+
+// shapeResults runs the results through the pipeline the flags enable,
+// trims them to --results and drops the sections the flags leave out.
+func shapeResults(results *searxnglib.SearchResponse, cfg *config.Config, cfgFlags *ConfigFlags) error {
+	// Filtered counts replace the instance's estimate
+	pipeline, filtered, err := resultPipeline(cfgFlags)
+	if err != nil {
+		return err
+	}
+	results.Results = pipeline.Process(results.Results)
+	if filtered {
+		results.NumberOfResults = len(results.Results)
+	}
+	// --offset skips results after filtering, so the window is of those shown
+	if cfgFlags.Offset > 0 {
+		results.Results = results.Results[min(cfgFlags.Offset, len(results.Results)):]
+	}
+	// --results is the most shown, however many the instance returned
+	if limit := resultLimit(cfg, cfgFlags); limit > 0 && len(results.Results) > limit {
+		results.Results = results.Results[:limit]
+	}
+	// The instance's estimate doesn't describe results merged from several
+	// pages; report the deduplicated set that is shown instead
+	if results.PagesFetched > 1 {
+		results.NumberOfResults = len(results.Results)
+	}
 
+	// Every formatter skips sections that are empty
+	if cfgFlags.NoAnswers {
+		results.Answers = nil
+	}
+	if cfgFlags.NoInfoboxes {
+		results.Infoboxes = nil
+	}
+	if cfgFlags.NoSuggestions {
+		results.Suggestions = nil
+	}
+	return nil
+}
+
+// newOutputFormatter creates the formatter for format, configured by the
+// output flags, numbering results from start. A template, when given,
+// replaces the format entirely.
+func newOutputFormatter(format string, cfg *config.Config, cfgFlags *ConfigFlags, start int, templateFormatter *formatter.TemplateFormatter) (formatter.Formatter, error) {
+	if templateFormatter != nil {
+		return templateFormatter, nil
+	}
+
+	// Use the category-aware formatter
+	category := ""
+	if len(cfg.Categories) > 0 {
+		category = cfg.Categories[0]
+	}
+	width := cfgFlags.Width
+	if width == 0 {
+		// A terminal narrower than --width allows gets the default width
+		if width = ui.TerminalWidth(os.Stdout); width < validation.MinWidth {
+			width = 0
+		}
+	}
+	locale := cfgFlags.Locale
+	if locale == "" {
+		locale = formatter.LocaleFromEnv()
+	}
+	outputFormatter, err := formatter.NewFormatterWithOptions(format, category, formatter.Options{
+		NoColor:         cfgFlags.NoColor,
+		FieldSeparator:  formatter.ResolveFieldSeparator(cfgFlags.FieldSeparator),
+		Compact:         cfgFlags.Compact,
+		ContentMaxLines: cfgFlags.ContentMaxLines,
+		NoMetadata:      cfgFlags.NoMetadata,
+		GroupByCategory: strings.EqualFold(cfgFlags.GroupBy, "category"),
+		Raw:             cfgFlags.Raw,
+		Locale:          locale,
+		PrettyURLs:      cfgFlags.PrettyURLs,
+		StartIndex:      start,
+		Explain:         cfgFlags.ExplainResults || cfg.Verbose,
+		Summary:         cfgFlags.Summary,
+		NoWrap:          cfgFlags.NoWrap,
+		Width:           width,
+		EngineStats:     cfgFlags.EngineStats,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create formatter: %w", err)
+	}
+	return outputFormatter, nil
+}
+
+// checkOpenAllLimit guards --open-all against opening more than limit tabs.
+// Above the limit it asks for confirmation on an interactive terminal and
+// fails otherwise, unless yes is set.
+func checkOpenAllLimit(count, limit int, yes, interactive bool, in io.Reader, out io.Writer) error {
+	if yes || limit <= 0 || count <= limit {
 		return nil
 	}
+
+	if interactive {
+		ok, err := ui.Confirm(in, out, fmt.Sprintf("Open %d results in the browser?", count))
+		if err != nil {
+			return fmt.Errorf("failed to read confirmation: %w", err)
+		}
+		if ok {
+			return nil
+		}
+		return errors.New(errors.ErrCodeCanceled, "Opening results canceled")
+	}
+
+	return errors.New(errors.ErrCodeInvalidRange,
+		fmt.Sprintf("Refusing to open %d results, more than open_all_max (%d)", count, limit)).
+		WithSuggestion(fmt.Sprintf("Use -n %d or fewer, raise open_all_max in your config, or pass --yes", limit))
 }
 
-// openResults opens search results in the browser
-func openResults(results *searxnglib.SearchResponse, openAll bool, verbose bool) error {
+// selectResults lists results as a numbered menu on out, reads the
+// numbers to open from in and opens those results in the browser. A
+// non-empty browserCmd is used instead of the OS default.
+func selectResults(results *searxnglib.SearchResponse, browserCmd string, in io.Reader, out io.Writer) error {
 	if len(results.Results) == 0 {
-		return fmt.Errorf("no results to open")
+		return errors.EmptyResults(results.Query)
+	}
+
+	for i, result := range results.Results {
+		fmt.Fprintf(out, "%2d. %s\n    %s\n", i+1, result.Title, result.URL)
+	}
+	picks, err := ui.Select(in, out, "Open which results?", len(results.Results))
+	if err != nil {
+		return fmt.Errorf("failed to read the choice: %w", err)
+	}
+	if len(picks) == 0 {
+		return errors.New(errors.ErrCodeCanceled, "Opening results canceled")
 	}
 
-	// Collect URLs to open
 	var urls []string
+	for _, n := range picks {
+		urls = append(urls, results.Results[n-1].URL)
+	}
+	urls = openableURLs(urls, out)
+	if len(urls) == 0 {
+		return fmt.Errorf("no chosen result has an http or https URL to open")
+	}
+	if err := openURLs(urls, browserCmd); err != nil {
+		return fmt.Errorf("failed to open results in browser: %w", err)
+	}
+	return nil
+}
+
+// openResults opens search results in the browser. A non-empty browserCmd
+// is used instead of the OS default.
+func openResults(results *searxnglib.SearchResponse, openAll bool, verbose bool, browserCmd string) error {
+	if len(results.Results) == 0 {
+		return fmt.Errorf("no results to open")
+	}
+
+	// Collect URLs to open, leaving out duplicates and unsafe schemes
+	urls := openableURLs(resultURLs(results), os.Stderr)
+	if len(urls) == 0 {
+		return fmt.Errorf("no results with an http or https URL to open")
+	}
 	if openAll {
 		// Open all results
-		for _, result := range results.Results {
-			urls = append(urls, result.URL)
-		}
 		if verbose {
 			fmt.Fprintf(os.Stderr, "\nOpening %d results in browser...\n", len(urls))
 		}
 	} else {
 		// Open only the first result
-		urls = append(urls, results.Results[0].URL)
+		urls = urls[:1]
 		if verbose {
-			fmt.Fprintf(os.Stderr, "\nOpening first result in browser: %s\n", results.Results[0].URL)
+			fmt.Fprintf(os.Stderr, "\nOpening first result in browser: %s\n", urls[0])
 		}
 	}
 
+	return openURLs(urls, browserCmd)
+}
+
+// resultURLs returns the URLs of results in order.
+func resultURLs(results *searxnglib.SearchResponse) []string {
+	urls := make([]string, 0, len(results.Results))
+	for _, result := range results.Results {
+		urls = append(urls, result.URL)
+	}
+	return urls
+}
+
+// openAllCount is how many tabs --open-all opens for results: their http
+// and https URLs without repeats.
+func openAllCount(results *searxnglib.SearchResponse) int {
+	return len(openableURLs(resultURLs(results), io.Discard))
+}
+
+// openableURLs returns urls without repeats and without any whose scheme
+// isn't http or https, such as javascript: or data: URLs from a hostile
+// engine. Each skipped unsafe URL is reported to w.
+func openableURLs(urls []string, w io.Writer) []string {
+	seen := make(map[string]bool, len(urls))
+	var openable []string
+	for _, rawURL := range urls {
+		if seen[rawURL] {
+			continue
+		}
+		seen[rawURL] = true
+
+		u, err := url.Parse(rawURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			fmt.Fprintf(w, "Warning: not opening %q: only http and https URLs are opened\n", rawURL)
+			continue
+		}
+		openable = append(openable, rawURL)
+	}
+	return openable
+}
+
+// openURLs opens urls in the browser. A non-empty browserCmd is used
+// instead of the OS default.
+func openURLs(urls []string, browserCmd string) error {
+	if browserCmd != "" {
+		return browser.OpenURLsWithCommand(urls, browserCmd)
+	}
+
 	// Check if browser opening is supported
 	if !browser.IsSupported() {
 		return fmt.Errorf("browser opening is not supported on this system")
@@ -465,13 +1930,15 @@ func Execute() error {
 	return rootCmd.Execute()
 }
 
-// cachedSearchClient wraps a CachedClient to implement the SearchWithConfig interface.
+// cachedSearchClient wraps a CachedClient to implement the SearchWithConfig
+// interface. With readOnly, it only reads from the cache.
 type cachedSearchClient struct {
-	cached *cache.CachedClient
+	cached   *cache.CachedClient
+	readOnly bool
 }
 
-// SearchWithConfig executes a search using individual request parameters.
-func (c *cachedSearchClient) SearchWithConfig(query string, results int, format string, category string, timeout int, language string, safeSearch int, page int, timeRange string) (*searxnglib.SearchResponse, error) {
+// SearchWithConfigContext executes a search using individual request parameters.
+func (c *cachedSearchClient) SearchWithConfigContext(ctx context.Context, query string, results int, format string, category string, timeout int, language string, safeSearch int, page int, timeRange string) (*searxnglib.SearchResponse, error) {
 	req := searxnglib.NewSearchRequest(query)
 	req.Page = page
 	req.Format = "json" // API always returns JSON
@@ -479,5 +1946,8 @@ func (c *cachedSearchClient) SearchWithConfig(query string, results int, format
 	req.Languages = []string{language}
 	req.SafeSearch = safeSearch
 	req.TimeRange = timeRange
-	return c.cached.Search(req)
+	if c.readOnly {
+		return c.cached.SearchReadOnlyContext(ctx, req)
+	}
+	return c.cached.SearchContext(ctx, req)
 }
@@ -2,13 +2,19 @@ package cli
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+
+	searxnglib "github.com/mule-ai/search/internal/searxng"
 )
 
 func TestNewRootCommand(t *testing.T) {
@@ -178,6 +184,65 @@ func TestCategoriesCommand(t *testing.T) {
 	}
 }
 
+func TestCategoriesCommandRefresh(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/config" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"categories":["general","q&a","repos"]}`)
+	}))
+	instance := server.URL
+
+	list := func(args ...string) []categoryJSON {
+		t.Helper()
+		cmd := NewRootCommand()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs(append([]string{"categories", "-f", "json"}, args...))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("categories %v failed: %v", args, err)
+		}
+		var categories []categoryJSON
+		if err := json.Unmarshal(out.Bytes(), &categories); err != nil {
+			t.Fatalf("categories output is not JSON: %v\n%s", err, out.String())
+		}
+		return categories
+	}
+	names := func(categories []categoryJSON) string {
+		var names []string
+		for _, c := range categories {
+			names = append(names, c.Name)
+		}
+		return strings.Join(names, ",")
+	}
+
+	got := list("--refresh", "-i", instance)
+	if names(got) != "general,q&a,repos" {
+		t.Fatalf("refreshed categories = %s, want general,q&a,repos", names(got))
+	}
+	if got[0].Description == "" || got[1].DisplayName != "Q&a" || got[1].Description != "" {
+		t.Errorf("categories = %+v, want built-in details for general only", got)
+	}
+
+	// The cached list is used once the instance is gone
+	server.Close()
+	if got := list("-i", instance); names(got) != "general,q&a,repos" {
+		t.Errorf("cached categories = %s, want general,q&a,repos", names(got))
+	}
+	// A failed refresh keeps the cached list
+	if got := list("--refresh", "-i", instance); names(got) != "general,q&a,repos" {
+		t.Errorf("categories after failed refresh = %s, want the cached list", names(got))
+	}
+	// Other instances fall back to the built-in list
+	if got := list("-i", "https://other.example"); len(got) != len(searxnglib.GetCategoryNames()) {
+		t.Errorf("uncached instance listed %d categories, want the %d built-in ones", len(got), len(searxnglib.GetCategoryNames()))
+	}
+}
+
 func TestRootCommandRequiresArgs(t *testing.T) {
 	cmd := NewRootCommand()
 
@@ -389,3 +454,38 @@ func TestCategoriesCommandDescription(t *testing.T) {
 		}
 	}
 }
+
+func TestFlagCompletions(t *testing.T) {
+	cmd := NewRootCommand()
+
+	tests := []struct {
+		flag string
+		want string
+	}{
+		{"category", "images\tImages"},
+		{"format", "ndjson"},
+		{"safe", "2\tstrict"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.flag, func(t *testing.T) {
+			complete, ok := cmd.GetFlagCompletionFunc(tt.flag)
+			if !ok {
+				t.Fatalf("no completion registered for --%s", tt.flag)
+			}
+			got, directive := complete(cmd.Command, nil, "")
+			if directive != cobra.ShellCompDirectiveNoFileComp {
+				t.Errorf("--%s directive = %v, want NoFileComp", tt.flag, directive)
+			}
+			found := false
+			for _, c := range got {
+				if c == tt.want {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("--%s completions %v missing %q", tt.flag, got, tt.want)
+			}
+		})
+	}
+}
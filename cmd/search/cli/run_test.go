@@ -3,17 +3,81 @@ package cli
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/mule-ai/search/internal/config"
+	searcherrors "github.com/mule-ai/search/internal/errors"
 	"github.com/mule-ai/search/internal/searxng"
+	"github.com/mule-ai/search/pkg/version"
 )
 
+// TestMain keeps the tests, which point HOME at temporary directories,
+// from finding the developer's XDG directories.
+func TestMain(m *testing.M) {
+	os.Unsetenv("XDG_CONFIG_HOME")
+	os.Unsetenv("XDG_CACHE_HOME")
+	os.Exit(m.Run())
+}
+
+// runRoot runs the root command with args and returns what it wrote to
+// stdout, where the formatters print results.
+func runRoot(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	stdout, _, err := runRootStderr(t, args...)
+	return stdout, err
+}
+
+// runRootStderr is runRoot that also returns what the command wrote to
+// stderr, where notes and verbose output go. Both pipes are drained while
+// the command runs, so output larger than a pipe buffer cannot block it.
+func runRootStderr(t *testing.T, args ...string) (stdout, stderr string, err error) {
+	t.Helper()
+	capture := func(f **os.File) (restore func() string) {
+		r, w, pipeErr := os.Pipe()
+		if pipeErr != nil {
+			t.Fatal(pipeErr)
+		}
+		old := *f
+		*f = w
+		done := make(chan string)
+		go func() {
+			var buf bytes.Buffer
+			io.Copy(&buf, r)
+			r.Close()
+			done <- buf.String()
+		}()
+		return func() string {
+			w.Close()
+			*f = old
+			return <-done
+		}
+	}
+	restoreStdout := capture(&os.Stdout)
+	restoreStderr := capture(&os.Stderr)
+
+	// Cobra's own output, such as usage on an error, follows the swapped
+	// files because the command's writers are left unset
+	cmd := NewRootCommand()
+	cmd.SetArgs(args)
+	err = cmd.Execute()
+	return restoreStdout(), restoreStderr(), err
+}
+
 // TestRunFunction tests the main run function with mocked search
 func TestRunFunction(t *testing.T) {
 	// Save original os.Args and restore after test
@@ -85,6 +149,33 @@ func TestRunFunction(t *testing.T) {
 	})
 }
 
+func TestOpenableURLs(t *testing.T) {
+	urls := []string{
+		"https://go.dev/",
+		"javascript:alert(1)",
+		"https://go.dev/",
+		"data:text/html;base64,PHNjcmlwdD4=",
+		"HTTP://example.com/",
+		"vbscript:msgbox",
+		"https:///no-host",
+		"http://example.com/page",
+	}
+	var warnings bytes.Buffer
+	got := openableURLs(urls, &warnings)
+	want := []string{"https://go.dev/", "HTTP://example.com/", "http://example.com/page"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("openableURLs() = %v, want %v", got, want)
+	}
+	for _, skipped := range []string{"javascript:alert(1)", "data:text/html", "vbscript:msgbox", "https:///no-host"} {
+		if !strings.Contains(warnings.String(), skipped) {
+			t.Errorf("expected a warning for %q, got:\n%s", skipped, warnings.String())
+		}
+	}
+	if strings.Count(warnings.String(), "Warning:") != 4 {
+		t.Errorf("expected 4 warnings, got:\n%s", warnings.String())
+	}
+}
+
 // TestOpenResults tests the openResults helper function
 // TestOpenResults tests the openResults helper function
 func TestOpenResults(t *testing.T) {
@@ -93,7 +184,7 @@ func TestOpenResults(t *testing.T) {
 			Results: []searxng.SearchResult{},
 		}
 
-		err := openResults(emptyResults, false, false)
+		err := openResults(emptyResults, false, false, "")
 		if err == nil {
 			t.Error("Expected error when no results to open")
 		}
@@ -102,6 +193,85 @@ func TestOpenResults(t *testing.T) {
 		}
 	})
 
+	t.Run("openResults with browser command", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("fake browser script requires a POSIX shell")
+		}
+		dir := t.TempDir()
+		out := filepath.Join(dir, "args.txt")
+		script := filepath.Join(dir, "fake-browser")
+		body := "#!/bin/sh\nprintf '%s\\n' \"$@\" > " + out + ".tmp && mv " + out + ".tmp " + out + "\n"
+		if err := os.WriteFile(script, []byte(body), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		results := &searxng.SearchResponse{
+			Results: []searxng.SearchResult{
+				{URL: "https://example.com/1"},
+				{URL: "https://example.com/2"},
+			},
+		}
+		if err := openResults(results, true, false, script); err != nil {
+			t.Fatalf("openResults() error = %v", err)
+		}
+
+		var got []byte
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+			if data, err := os.ReadFile(out); err == nil {
+				got = data
+				break
+			}
+		}
+		if string(got) != "https://example.com/1\nhttps://example.com/2\n" {
+			t.Errorf("Expected both URLs passed to the browser, got %q", got)
+		}
+	})
+
+	t.Run("openResults skips duplicate and unsafe URLs", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("fake browser script requires a POSIX shell")
+		}
+		dir := t.TempDir()
+		out := filepath.Join(dir, "args.txt")
+		script := filepath.Join(dir, "fake-browser")
+		body := "#!/bin/sh\nprintf '%s\\n' \"$@\" > " + out + ".tmp && mv " + out + ".tmp " + out + "\n"
+		if err := os.WriteFile(script, []byte(body), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		results := &searxng.SearchResponse{
+			Results: []searxng.SearchResult{
+				{URL: "javascript:alert(1)"},
+				{URL: "https://example.com/1"},
+				{URL: "data:text/html,<script>alert(1)</script>"},
+				{URL: "https://example.com/1"},
+				{URL: "http://example.com/2"},
+				{URL: "file:///etc/passwd"},
+			},
+		}
+		if err := openResults(results, true, false, script); err != nil {
+			t.Fatalf("openResults() error = %v", err)
+		}
+
+		var got []byte
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+			if data, err := os.ReadFile(out); err == nil {
+				got = data
+				break
+			}
+		}
+		if string(got) != "https://example.com/1\nhttp://example.com/2\n" {
+			t.Errorf("Expected only the unique http(s) URLs passed to the browser, got %q", got)
+		}
+
+		unsafe := &searxng.SearchResponse{
+			Results: []searxng.SearchResult{{URL: "javascript:alert(1)"}},
+		}
+		if err := openResults(unsafe, false, false, script); err == nil {
+			t.Error("Expected an error when no result has an http(s) URL")
+		}
+	})
+
 	t.Run("openResults with results (requires browser support)", func(t *testing.T) {
 		// This test would require mocking browser.IsSupported and browser.OpenURLs
 		// For now, we just verify the function signature and test the error case
@@ -120,6 +290,64 @@ func TestOpenResults(t *testing.T) {
 		}
 	})
 }
+func TestSelectResults(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if runtime.GOOS == "windows" {
+		t.Skip("fake browser script requires a POSIX shell")
+	}
+	dir := t.TempDir()
+	out := filepath.Join(dir, "args.txt")
+	script := filepath.Join(dir, "fake-browser")
+	body := "#!/bin/sh\nprintf '%s\\n' \"$@\" > " + out + ".tmp && mv " + out + ".tmp " + out + "\n"
+	if err := os.WriteFile(script, []byte(body), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	results := &searxng.SearchResponse{
+		Query: "golang",
+		Results: []searxng.SearchResult{
+			{Title: "Go", URL: "https://go.dev/"},
+			{Title: "Spec", URL: "https://go.dev/ref/spec"},
+			{Title: "Tour", URL: "https://go.dev/tour/"},
+		},
+	}
+
+	var menu bytes.Buffer
+	if err := selectResults(results, script, strings.NewReader("3,2\n"), &menu); err != nil {
+		t.Fatalf("selectResults() error = %v", err)
+	}
+	if !strings.Contains(menu.String(), " 2. Spec\n    https://go.dev/ref/spec\n") {
+		t.Errorf("expected a numbered menu, got %q", menu.String())
+	}
+
+	var got []byte
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if data, err := os.ReadFile(out); err == nil {
+			got = data
+			break
+		}
+	}
+	if string(got) != "https://go.dev/tour/\nhttps://go.dev/ref/spec\n" {
+		t.Errorf("Expected the chosen URLs in order passed to the browser, got %q", got)
+	}
+
+	// An empty answer opens nothing
+	err := selectResults(results, script, strings.NewReader("\n"), io.Discard)
+	if code := searcherrors.ExitCode(err); code != searcherrors.ExitCanceled {
+		t.Errorf("expected exit code %d on an empty choice, got %d (%v)", searcherrors.ExitCanceled, code, err)
+	}
+
+	// Without a terminal the flag fails before searching
+	cmd := NewRootCommand()
+	cmd.SetArgs([]string{"-i", "http://127.0.0.1:1", "--no-cache", "--select", "golang"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	err = cmd.Execute()
+	if code := searcherrors.ExitCode(err); code != searcherrors.ExitInvalidInput {
+		t.Errorf("expected exit code %d for --select without a terminal, got %d (%v)", searcherrors.ExitInvalidInput, code, err)
+	}
+}
+
 func TestConfigFlags(t *testing.T) {
 	cfg := ConfigFlags{}
 
@@ -457,6 +685,90 @@ func TestNewVersionCommandCoverage(t *testing.T) {
 	}
 }
 
+// TestVersionCommandJSON tests machine-readable version output
+func TestCategoriesCommandJSON(t *testing.T) {
+	var out bytes.Buffer
+	cmd := NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"categories", "--format", "json"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("categories --format json failed: %v", err)
+	}
+
+	var categories []map[string]string
+	if err := json.Unmarshal(out.Bytes(), &categories); err != nil {
+		t.Fatalf("expected a JSON array, got %q: %v", out.String(), err)
+	}
+	names := searxng.GetCategoryNames()
+	if len(categories) != len(names) {
+		t.Fatalf("got %d categories, want %d", len(categories), len(names))
+	}
+	for i, cat := range categories {
+		if len(cat) != 4 {
+			t.Errorf("category %d has keys %v, want exactly name, display_name, description, example_query", i, cat)
+		}
+		for _, key := range []string{"name", "display_name", "description", "example_query"} {
+			if cat[key] == "" {
+				t.Errorf("category %d is missing %q: %v", i, key, cat)
+			}
+		}
+		if cat["name"] != names[i] {
+			t.Errorf("category %d name = %q, want %q", i, cat["name"], names[i])
+		}
+	}
+
+	cmd = NewRootCommand()
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"categories", "--format", "yaml"})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
+
+func TestVersionCommandJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"version subcommand", []string{"version", "--format", "json"}},
+		{"version flag", []string{"--version", "-f", "json"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			cmd := NewRootCommand()
+			cmd.SetOut(&out)
+			cmd.SetArgs(tt.args)
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("%v failed: %v", tt.args, err)
+			}
+
+			var info map[string]string
+			if err := json.Unmarshal(out.Bytes(), &info); err != nil {
+				t.Fatalf("expected JSON, got %q: %v", out.String(), err)
+			}
+			if info["version"] != version.Version {
+				t.Errorf("expected version %q, got %q", version.Version, info["version"])
+			}
+			for _, key := range []string{"git_commit", "build_date", "go_version"} {
+				if _, ok := info[key]; !ok {
+					t.Errorf("expected key %q in %v", key, info)
+				}
+			}
+		})
+	}
+
+	cmd := NewRootCommand()
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"version", "--format", "yaml"})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected an error for an unsupported version format")
+	}
+}
+
 // TestNewCategoriesCommandCoverage tests categories command
 func TestNewCategoriesCommandCoverage(t *testing.T) {
 	cmd := newCategoriesCommand()
@@ -587,3 +899,1725 @@ func TestFlagParsing(t *testing.T) {
 		})
 	}
 }
+
+// TestAppendQuery tests joining configured filters onto the query
+func TestAppendQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		extra string
+		want  string
+	}{
+		{"appends filter", "golang", "-site:spam.example", "golang -site:spam.example"},
+		{"multiple filters", "golang", "-site:a.example -site:b.example", "golang -site:a.example -site:b.example"},
+		{"trims whitespace", "golang", "  -site:spam.example  ", "golang -site:spam.example"},
+		{"strips control characters", "golang", "-site:spam\x00.example\x1b", "golang -site:spam.example"},
+		{"blank filter is ignored", "golang", "   ", "golang"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := appendQuery(tt.query, tt.extra); got != tt.want {
+				t.Errorf("appendQuery(%q, %q) = %q, want %q", tt.query, tt.extra, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCheckOpenAllLimit tests the --open-all safety cap
+func TestCheckOpenAllLimit(t *testing.T) {
+	t.Run("within limit", func(t *testing.T) {
+		if err := checkOpenAllLimit(10, 10, false, false, nil, io.Discard); err != nil {
+			t.Errorf("Expected no error at the limit, got %v", err)
+		}
+	})
+
+	t.Run("non-interactive over limit", func(t *testing.T) {
+		err := checkOpenAllLimit(25, 10, false, false, nil, io.Discard)
+		if err == nil {
+			t.Fatal("Expected error when over the limit without a terminal")
+		}
+		for _, want := range []string{"25 results", "open_all_max (10)", "-n 10", "--yes"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("Expected error to mention %q, got %q", want, err.Error())
+			}
+		}
+		if code := searcherrors.ExitCode(err); code != searcherrors.ExitInvalidInput {
+			t.Errorf("Expected exit code %d, got %d", searcherrors.ExitInvalidInput, code)
+		}
+	})
+
+	t.Run("yes skips the limit", func(t *testing.T) {
+		if err := checkOpenAllLimit(25, 10, true, false, nil, io.Discard); err != nil {
+			t.Errorf("Expected --yes to skip the limit, got %v", err)
+		}
+	})
+
+	t.Run("interactive confirm", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := checkOpenAllLimit(25, 10, false, true, strings.NewReader("y\n"), &out); err != nil {
+			t.Errorf("Expected confirmation to allow opening, got %v", err)
+		}
+		if !strings.Contains(out.String(), "Open 25 results in the browser? [y/N]") {
+			t.Errorf("Expected confirmation prompt, got %q", out.String())
+		}
+	})
+
+	t.Run("interactive decline", func(t *testing.T) {
+		if err := checkOpenAllLimit(25, 10, false, true, strings.NewReader("\n"), io.Discard); err == nil {
+			t.Error("Expected error when confirmation is declined")
+		}
+	})
+
+	t.Run("counts tabs, not results", func(t *testing.T) {
+		results := &searxng.SearchResponse{Results: []searxng.SearchResult{
+			{URL: "https://go.dev/"},
+			{URL: "https://go.dev/"},
+			{URL: "javascript:alert(1)"},
+			{URL: "https://go.dev/tour/"},
+		}}
+		if got := openAllCount(results); got != 2 {
+			t.Errorf("openAllCount() = %d, want 2 without repeats and unsafe URLs", got)
+		}
+		if err := checkOpenAllLimit(openAllCount(results), 2, false, false, nil, io.Discard); err != nil {
+			t.Errorf("Expected repeated URLs not to count against the limit, got %v", err)
+		}
+	})
+}
+
+// TestAutoCorrectQuery tests choosing a suggestion to rerun the search with
+func TestAutoCorrectQuery(t *testing.T) {
+	few := []searxng.SearchResult{{Title: "Only one"}}
+	many := []searxng.SearchResult{{Title: "1"}, {Title: "2"}, {Title: "3"}}
+
+	tests := []struct {
+		name string
+		resp *searxng.SearchResponse
+		want string
+	}{
+		{"few results with suggestion", &searxng.SearchResponse{Results: few, Suggestions: []string{"golang", "go lang"}}, "golang"},
+		{"no results with suggestion", &searxng.SearchResponse{Suggestions: []string{"golang"}}, "golang"},
+		{"enough results", &searxng.SearchResponse{Results: many, Suggestions: []string{"golang"}}, ""},
+		{"no suggestions", &searxng.SearchResponse{Results: few}, ""},
+		{"suggestion equals query", &searxng.SearchResponse{Results: few, Suggestions: []string{"GOLANF"}}, ""},
+		{"suggestion is sanitized", &searxng.SearchResponse{Results: few, Suggestions: []string{" golang\x00 "}}, "golang"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := autoCorrectQuery(tt.resp, "golanf", 3); got != tt.want {
+				t.Errorf("autoCorrectQuery() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestTemplatesListCommand tests listing named templates
+func TestTemplatesListCommand(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	run := func() string {
+		cmd := NewRootCommand()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		cmd.SetArgs([]string{"templates", "list"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("templates list failed: %v", err)
+		}
+		return out.String()
+	}
+
+	if out := run(); !strings.Contains(out, "No templates found") {
+		t.Errorf("Expected empty message, got %q", out)
+	}
+
+	dir := home + "/.config/search/templates"
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dir+"/news.tmpl", []byte("{{.Query}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if out := run(); out != "news\n" {
+		t.Errorf("Expected template list %q, got %q", "news\n", out)
+	}
+}
+
+// TestBenchmarkCommand tests benchmarking instances fastest first
+func TestBenchmarkCommand(t *testing.T) {
+	newServer := func(delay time.Duration, results int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(delay)
+			items := make([]string, results)
+			for i := range items {
+				items[i] = `{"title":"r","url":"https://example.com"}`
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"query":"test","results":[%s]}`, strings.Join(items, ","))
+		}))
+	}
+	slow := newServer(60*time.Millisecond, 1)
+	defer slow.Close()
+	fast := newServer(0, 3)
+	defer fast.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+
+	cmd := NewRootCommand()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"benchmark", "test", broken.URL, slow.URL, fast.URL})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("benchmark failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected header and 3 rows, got:\n%s", out.String())
+	}
+	if !strings.HasPrefix(lines[1], fast.URL) || !strings.Contains(lines[1], "  3  ok") {
+		t.Errorf("Expected fast instance first, got %q", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], slow.URL) {
+		t.Errorf("Expected slow instance second, got %q", lines[2])
+	}
+	if !strings.HasPrefix(lines[3], broken.URL) || !strings.Contains(lines[3], "failed") {
+		t.Errorf("Expected failed instance last, got %q", lines[3])
+	}
+
+	// Latency excludes time spent waiting for a slot, so order holds
+	out.Reset()
+	cmd = NewRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"benchmark", "--concurrency", "1", "test", slow.URL, fast.URL})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("benchmark with --concurrency 1 failed: %v", err)
+	}
+	lines = strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], fast.URL) {
+		t.Errorf("Expected fast instance first with --concurrency 1, got:\n%s", out.String())
+	}
+
+	cmd = NewRootCommand()
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"benchmark", "--concurrency", "0", "test", fast.URL})
+	if err := cmd.Execute(); err == nil {
+		t.Error("Expected error for --concurrency 0")
+	}
+}
+
+func TestInstancesDiscoverCommand(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"instances": {
+			"https://b.example/": {"network_type": "normal", "http": {"status_code": 200},
+				"timing": {"search": {"success_percentage": 90, "all": {"median": 0.2}}}},
+			"https://a.example/": {"network_type": "normal", "http": {"status_code": 200},
+				"timing": {"search": {"success_percentage": 100, "all": {"median": 0.5}}}}
+		}}`)
+	}))
+	defer ts.Close()
+
+	oldURL := instanceListURL
+	instanceListURL = ts.URL
+	defer func() { instanceListURL = oldURL }()
+
+	// Secrets from the environment must not be written to the file
+	t.Setenv("SEARCH_API_KEY", "sk-from-the-environment")
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("# my settings\ninstance: https://old.example\nresults: 20\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := NewRootCommand()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"instances", "discover", "--save", "--config", configPath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("instances discover failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected header, 2 rows and a save note, got:\n%s", out.String())
+	}
+	if !strings.HasPrefix(lines[1], "https://a.example ") || !strings.Contains(lines[1], "100%") {
+		t.Errorf("Expected the most reliable instance first, got %q", lines[1])
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("config was not saved: %v", err)
+	}
+	if !strings.Contains(string(data), "https://a.example") {
+		t.Errorf("Expected saved config to use the top instance, got:\n%s", data)
+	}
+	if strings.Contains(string(data), "sk-from-the-environment") || strings.Contains(string(data), "api_key") {
+		t.Errorf("Expected no API key from the environment in the saved config, got:\n%s", data)
+	}
+	if want := "# my settings\ninstance: https://a.example\nresults: 20\n"; string(data) != want {
+		t.Errorf("Expected only the instance to change, got:\n%s", data)
+	}
+	if info, err := os.Stat(configPath); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the saved config to be private (0600), got %v (%v)", info.Mode().Perm(), err)
+	}
+}
+
+func TestCompleteCommand(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/autocompleter" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `[%q, ["golang", "golang tutorial"]]`, r.URL.Query().Get("q"))
+	}))
+	defer ts.Close()
+
+	cmd := NewRootCommand()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"complete", "-i", ts.URL, "gola"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("complete failed: %v", err)
+	}
+	if got, want := out.String(), "golang\ngolang tutorial\n"; got != want {
+		t.Errorf("complete output = %q, want %q", got, want)
+	}
+
+	unsupported := httptest.NewServer(http.NotFoundHandler())
+	defer unsupported.Close()
+
+	cmd = NewRootCommand()
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"complete", "-i", unsupported.URL, "gola"})
+	err := cmd.Execute()
+	if code := searcherrors.GetErrorCode(err); code != searcherrors.ErrCodeAutocompleteUnsupported {
+		t.Errorf("expected an unsupported error, got %v", err)
+	}
+}
+
+func TestHealthCommand(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"query":"searxng","results":[]}`)
+	}))
+	defer healthy.Close()
+	html := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, "<html></html>")
+	}))
+	defer html.Close()
+
+	execute := func(instance string) (string, error) {
+		cmd := NewRootCommand()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs([]string{"health", "-i", instance, "-t", "5"})
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := execute(healthy.URL)
+	if err != nil {
+		t.Fatalf("health failed: %v", err)
+	}
+	if !strings.Contains(out, "Reachable: ok") || !strings.Contains(out, "JSON:      ok, 0 results") || !strings.Contains(out, "Status:    healthy") {
+		t.Errorf("unexpected report:\n%s", out)
+	}
+
+	out, err = execute(html.URL)
+	if code := searcherrors.ExitCode(err); code != searcherrors.ExitInstance {
+		t.Errorf("expected exit code %d for an instance without JSON, got %d (%v)", searcherrors.ExitInstance, code, err)
+	}
+	if !strings.Contains(out, "JSON:      FAIL") || !strings.Contains(out, "Status:    unhealthy") {
+		t.Errorf("unexpected report:\n%s", out)
+	}
+}
+
+func TestRunQueryOperators(t *testing.T) {
+	var gotQuery string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.Query().Get("q")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"query":"ignored","results":[]}`)
+	}))
+	defer ts.Close()
+
+	t.Setenv("HOME", t.TempDir())
+
+	out, err := runRoot(t, "--instance", ts.URL, "--no-cache", "-f", "json",
+		"--site", "https://go.dev/", "--filetype", "pdf", "--intitle", "memory model",
+		"golang site:go.dev")
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+
+	want := `golang site:go.dev filetype:pdf intitle:"memory model"`
+	if gotQuery != want {
+		t.Errorf("instance received q = %q, want %q", gotQuery, want)
+	}
+	if !strings.Contains(out, `"query": "golang site:go.dev filetype:pdf intitle:\"memory model\""`) {
+		t.Errorf("output should report the effective query, got:\n%s", out)
+	}
+}
+
+func TestRunInstanceFromFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var downHits, upHits int
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downHits++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upHits++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"query":"golang","number_of_results":1,"results":[{"title":"Go","url":"https://go.dev","content":"","engine":"test","score":1}]}`)
+	}))
+	defer up.Close()
+
+	list := filepath.Join(t.TempDir(), "instances.txt")
+	if err := os.WriteFile(list, []byte("# mine\n"+down.URL+"\n"+up.URL+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	execute := func(args ...string) error {
+		_, err := runRoot(t, args...)
+		return err
+	}
+
+	// The first run starts at the failing instance and falls back
+	if err := execute("--instance-from-file", list, "--no-cache", "-f", "json", "golang"); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if downHits != 1 || upHits != 1 {
+		t.Errorf("expected a fallback from the first instance, got %d and %d requests", downHits, upHits)
+	}
+
+	// The next run starts at the second instance
+	if err := execute("--instance-from-file", list, "--no-cache", "-f", "json", "golang"); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if downHits != 1 || upHits != 2 {
+		t.Errorf("expected round-robin to start at the second instance, got %d and %d requests", downHits, upHits)
+	}
+
+	invalid := filepath.Join(t.TempDir(), "invalid.txt")
+	if err := os.WriteFile(invalid, []byte(up.URL+"\nftp://search.example\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := execute("--instance-from-file", invalid, "golang"); err == nil {
+		t.Error("expected an error for an invalid instance in the list")
+	}
+	if err := execute("--instance-from-file", list, "--instance-select", "weighted", "golang"); err == nil {
+		t.Error("expected an error for an unknown selection mode")
+	}
+}
+
+func TestRunSkipsOpenInstance(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var downHits, upHits int
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downHits++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upHits++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"query":"golang","number_of_results":1,"results":[{"title":"Go","url":"https://go.dev","content":"","engine":"test","score":1}]}`)
+	}))
+	defer up.Close()
+
+	// The failing instance is listed again as a fallback; its breaker is
+	// open by then, so the fallback is skipped without a request
+	list := filepath.Join(t.TempDir(), "instances.txt")
+	if err := os.WriteFile(list, []byte(down.URL+"\n"+down.URL+"\n"+up.URL+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := runRoot(t, "--instance-from-file", list, "--no-cache", "--retries", "0", "--rate-limit", "0", "-f", "json", "golang"); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if downHits != 1 || upHits != 1 {
+		t.Errorf("expected the open instance to be skipped, got %d and %d requests", downHits, upHits)
+	}
+}
+
+// TestRunParseFile tests formatting a saved response without searching
+func TestRunParseFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+
+	execute := func(args ...string) (string, error) {
+		return runRoot(t, args...)
+	}
+
+	saved := filepath.Join(dir, "saved.json")
+	response := `{"query":"golang","number_of_results":1,"results":[{"title":"The Go Programming Language","url":"https://go.dev","content":"Go is open source","engine":"google","score":1.5}]}`
+	if err := os.WriteFile(saved, []byte(response), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("formats saved response", func(t *testing.T) {
+		out, err := execute("--parse-file", saved, "-f", "markdown")
+		if err != nil {
+			t.Fatalf("--parse-file failed: %v", err)
+		}
+		for _, want := range []string{"The Go Programming Language", "https://go.dev"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("output should contain %q, got:\n%s", want, out)
+			}
+		}
+	})
+
+	t.Run("malformed JSON", func(t *testing.T) {
+		bad := filepath.Join(dir, "bad.json")
+		if err := os.WriteFile(bad, []byte(`{"results": [`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := execute("--parse-file", bad)
+		if err == nil || !strings.Contains(err.Error(), "failed to parse") {
+			t.Errorf("expected parse error, got %v", err)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := execute("--parse-file", filepath.Join(dir, "missing.json"))
+		if err == nil || !strings.Contains(err.Error(), "failed to read response file") {
+			t.Errorf("expected read error, got %v", err)
+		}
+	})
+
+	t.Run("rejects a query", func(t *testing.T) {
+		if _, err := execute("--parse-file", saved, "golang"); err == nil {
+			t.Error("expected an error when a query is given with --parse-file")
+		}
+	})
+}
+
+// TestRunStripTracking tests removing tracking parameters before output
+func TestRunStripTracking(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	saved := filepath.Join(t.TempDir(), "saved.json")
+	response := `{"query":"golang","results":[{"title":"Go","url":"https://go.dev/doc?id=1&utm_source=feed&fbclid=abc#intro","content":"","engine":"google"}]}`
+	if err := os.WriteFile(saved, []byte(response), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runRoot(t, "--parse-file", saved, "-f", "markdown", "--strip-tracking")
+
+	if err != nil {
+		t.Fatalf("--strip-tracking failed: %v", err)
+	}
+	if !strings.Contains(out, "https://go.dev/doc?id=1#intro") || strings.Contains(out, "utm_source") {
+		t.Errorf("expected cleaned URL in output, got:\n%s", out)
+	}
+}
+
+// TestRunDateFilter tests --since, --until and --require-date
+func TestRunDateFilter(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	saved := filepath.Join(t.TempDir(), "saved.json")
+	response := `{"query":"golang","number_of_results":900,"results":[
+		{"title":"Old","url":"https://old.example","publishedDate":"2023-05-01T00:00:00"},
+		{"title":"Recent","url":"https://recent.example","publishedDate":"2024-03-01T09:00:00"},
+		{"title":"Undated","url":"https://undated.example","publishedDate":null}]}`
+	if err := os.WriteFile(saved, []byte(response), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	execute := func(args ...string) (string, error) {
+		return runRoot(t, append([]string{"--parse-file", saved, "-f", "markdown"}, args...)...)
+	}
+
+	out, err := execute("--since", "2024-01-01", "--until", "2024-12-31")
+	if err != nil {
+		t.Fatalf("date filter failed: %v", err)
+	}
+	if strings.Contains(out, "Old") || !strings.Contains(out, "Recent") || !strings.Contains(out, "Undated") {
+		t.Errorf("expected Recent and Undated only, got:\n%s", out)
+	}
+	if !strings.Contains(out, "Found **2** results") {
+		t.Errorf("expected the filtered count, got:\n%s", out)
+	}
+
+	out, err = execute("--since", "2024-01-01", "--require-date")
+	if err != nil {
+		t.Fatalf("date filter failed: %v", err)
+	}
+	if strings.Contains(out, "Undated") || !strings.Contains(out, "Recent") {
+		t.Errorf("expected undated results to be dropped, got:\n%s", out)
+	}
+
+	if _, err := execute("--since", "01/01/2024"); err == nil {
+		t.Error("expected an error for an invalid date")
+	}
+	if _, err := execute("--since", "2024-06-01", "--until", "2024-01-01"); err == nil {
+		t.Error("expected an error when --since is after --until")
+	}
+}
+
+// TestRunResultsPerEngine tests capping results from one engine
+func TestRunResultsPerEngine(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	saved := filepath.Join(t.TempDir(), "saved.json")
+	response := `{"query":"golang","results":[
+		{"title":"G low","url":"https://a.example","engine":"google","score":1},
+		{"title":"G high","url":"https://b.example","engine":"google","score":5},
+		{"title":"Bing","url":"https://c.example","engine":"bing","score":0.5}]}`
+	if err := os.WriteFile(saved, []byte(response), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runRoot(t, "--parse-file", saved, "-f", "markdown", "--results-per-engine", "1")
+
+	if err != nil {
+		t.Fatalf("--results-per-engine failed: %v", err)
+	}
+	if strings.Contains(out, "G low") || !strings.Contains(out, "G high") || !strings.Contains(out, "Bing") {
+		t.Errorf("expected the best google result and the bing result, got:\n%s", out)
+	}
+}
+
+func TestRunPageNumbering(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	saved := filepath.Join(t.TempDir(), "saved.json")
+	response := `{"query":"golang","results":[
+		{"title":"First on page","url":"https://a.example","engine":"google","score":1}]}`
+	if err := os.WriteFile(saved, []byte(response), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runRoot(t, "--parse-file", saved, "--no-color", "--page", "2", "-n", "10")
+
+	if err != nil {
+		t.Fatalf("--page 2 failed: %v", err)
+	}
+	if !strings.Contains(out, "[11] First on page") {
+		t.Errorf("page 2 should start numbering at 11, got:\n%s", out)
+	}
+}
+
+func TestRunOnlyLanguage(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	saved := filepath.Join(t.TempDir(), "saved.json")
+	response := `{"query":"golang","number_of_results":100,"results":[
+		{"title":"The Go Programming Language","url":"https://go.dev","content":"Go is an open source programming language that makes it simple to build secure, scalable systems.","engine":"google","score":1},
+		{"title":"Go (Programmiersprache)","url":"https://de.wikipedia.org/wiki/Go","content":"Go ist eine Programmiersprache, die von Google entwickelt wurde und sich für nebenläufige Anwendungen eignet.","engine":"google","score":0.5}]}`
+	if err := os.WriteFile(saved, []byte(response), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	execute := func(args ...string) (string, error) {
+		return runRoot(t, args...)
+	}
+
+	out, err := execute("--parse-file", saved, "-f", "json", "--only-language", "de")
+	if err != nil {
+		t.Fatalf("--only-language failed: %v", err)
+	}
+	if strings.Contains(out, "https://go.dev") || !strings.Contains(out, `"detected_language": "de"`) {
+		t.Errorf("expected only the German result, got:\n%s", out)
+	}
+	if !strings.Contains(out, `"total_results": 1`) {
+		t.Errorf("expected the filtered count, got:\n%s", out)
+	}
+
+	out, err = execute("--parse-file", saved, "-f", "json")
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if strings.Contains(out, "detected_language") {
+		t.Errorf("detection should be off by default, got:\n%s", out)
+	}
+
+	if _, err := execute("--parse-file", saved, "--only-language", "xx"); err == nil {
+		t.Error("expected an error for an unknown language")
+	}
+}
+
+func TestRunConfigDump(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+
+	execute := func(args ...string) error {
+		cmd := NewRootCommand()
+		cmd.SetArgs(args)
+		cmd.SetErr(io.Discard)
+		cmd.SetOut(io.Discard)
+		return cmd.Execute()
+	}
+
+	t.Run("writes flags without secrets", func(t *testing.T) {
+		path := filepath.Join(dir, "dump.yaml")
+		if err := execute("--config-dump", path, "-n", "25", "-f", "markdown", "--api-key", "secret-key"); err != nil {
+			t.Fatalf("--config-dump failed: %v", err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("dump not written: %v", err)
+		}
+		for _, want := range []string{"results: 25", "format: markdown"} {
+			if !strings.Contains(string(data), want) {
+				t.Errorf("dump should contain %q, got:\n%s", want, data)
+			}
+		}
+		if strings.Contains(string(data), "api_key") || strings.Contains(string(data), "secret-key") {
+			t.Errorf("dump should omit the API key, got:\n%s", data)
+		}
+	})
+
+	t.Run("include secrets", func(t *testing.T) {
+		path := filepath.Join(dir, "secrets.yaml")
+		if err := execute("--config-dump", path, "--api-key", "secret-key", "--include-secrets"); err != nil {
+			t.Fatalf("--config-dump failed: %v", err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("dump not written: %v", err)
+		}
+		if !strings.Contains(string(data), "api_key: secret-key") {
+			t.Errorf("dump should contain the API key, got:\n%s", data)
+		}
+	})
+
+	t.Run("rejects extra arguments", func(t *testing.T) {
+		if err := execute("--config-dump", filepath.Join(dir, "extra.yaml"), "a", "b"); err == nil {
+			t.Error("expected an error for two arguments")
+		}
+	})
+}
+
+func TestRunSectionFlags(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	saved := filepath.Join(t.TempDir(), "saved.json")
+	response := `{"query":"golang","number_of_results":1,
+		"results":[{"title":"Go","url":"https://go.dev","content":"","engine":"google","score":1}],
+		"answers":[{"answer":"answer-marker"}],
+		"infoboxes":[{"infobox":"infobox-marker","content":"about Go"}],
+		"suggestions":["suggestion-marker"]}`
+	if err := os.WriteFile(saved, []byte(response), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	execute := func(args ...string) string {
+		t.Helper()
+		out, err := runRoot(t, args...)
+		if err != nil {
+			t.Fatalf("search %v failed: %v", args, err)
+		}
+		return out
+	}
+
+	sections := map[string]string{
+		"--no-answers":     "answer-marker",
+		"--no-infoboxes":   "infobox-marker",
+		"--no-suggestions": "suggestion-marker",
+	}
+	for _, format := range []string{"text", "markdown", "json"} {
+		out := execute("--parse-file", saved, "-f", format)
+		for _, marker := range sections {
+			if !strings.Contains(out, marker) {
+				t.Errorf("%s output should contain %q by default, got:\n%s", format, marker, out)
+			}
+		}
+
+		for flag, marker := range sections {
+			out := execute("--parse-file", saved, "-f", format, flag)
+			if strings.Contains(out, marker) {
+				t.Errorf("%s output with %s should not contain %q, got:\n%s", format, flag, marker, out)
+			}
+			for other, otherMarker := range sections {
+				if other != flag && !strings.Contains(out, otherMarker) {
+					t.Errorf("%s output with %s should still contain %q", format, flag, otherMarker)
+				}
+			}
+		}
+	}
+}
+
+func TestRunRateLimitVerbose(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"query":"golang","number_of_results":0,"results":[],"suggestions":["go"]}`)
+	}))
+	defer server.Close()
+
+	// --auto-correct sends a second request, which has to wait its turn
+	_, stderr, err := runRootStderr(t, "-i", server.URL, "--verbose", "--no-color", "--auto-correct", "--rate-limit", "20", "golang")
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if !strings.Contains(stderr, "to respect the rate limit") {
+		t.Errorf("Expected a rate limit note in verbose output, got:\n%s", stderr)
+	}
+
+	if _, err := runRoot(t, "-i", server.URL, "--rate-limit", "-1", "golang"); err == nil {
+		t.Error("Expected error for --rate-limit -1")
+	}
+}
+
+func TestRunBookmark(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+
+	execute := func(args ...string) (string, string, error) {
+		return runRootStderr(t, args...)
+	}
+
+	saved := filepath.Join(dir, "saved.json")
+	response := `{"query":"golang","number_of_results":2,"results":[` +
+		`{"title":"The Go Programming Language","url":"https://go.dev","content":"","engine":"google","score":2},` +
+		`{"title":"A Tour of Go","url":"https://go.dev/tour/","content":"","engine":"google","score":1}]}`
+	if err := os.WriteFile(saved, []byte(response), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, stderr, err := execute("--parse-file", saved, "--bookmark", "2"); err != nil || !strings.Contains(stderr, "Bookmarked [2] A Tour of Go") {
+		t.Fatalf("--bookmark 2 = %v, stderr:\n%s", err, stderr)
+	}
+
+	// Numbers follow the output, which continues across pages
+	if _, stderr, err := execute("--parse-file", saved, "--page", "2", "--bookmark", "11"); err != nil || !strings.Contains(stderr, "Bookmarked [11] The Go Programming Language") {
+		t.Fatalf("--page 2 --bookmark 11 = %v, stderr:\n%s", err, stderr)
+	}
+
+	// A result that isn't shown is a warning, not a failed search
+	out, stderr, err := execute("--parse-file", saved, "--bookmark", "5")
+	if err != nil {
+		t.Fatalf("--bookmark 5 should not fail the search: %v", err)
+	}
+	if !strings.Contains(out, "A Tour of Go") || !strings.Contains(stderr, "cannot bookmark result 5") {
+		t.Errorf("expected results and a warning, got stdout:\n%s\nstderr:\n%s", out, stderr)
+	}
+
+	out, _, err = execute("bookmarks")
+	if err != nil {
+		t.Fatalf("bookmarks failed: %v", err)
+	}
+	for _, want := range []string{"[1] A Tour of Go", "https://go.dev/tour/", "[2] The Go Programming Language"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("bookmarks output should contain %q, got:\n%s", want, out)
+		}
+	}
+
+	out, _, err = execute("bookmarks", "--format", "json")
+	if err != nil {
+		t.Fatalf("bookmarks --format json failed: %v", err)
+	}
+	var bookmarks []struct {
+		Index int    `json:"index"`
+		Title string `json:"title"`
+		URL   string `json:"url"`
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal([]byte(out), &bookmarks); err != nil {
+		t.Fatalf("expected a JSON array, got %q: %v", out, err)
+	}
+	if len(bookmarks) != 2 || bookmarks[0].Index != 1 || bookmarks[0].URL != "https://go.dev/tour/" || bookmarks[0].Query != "golang" {
+		t.Errorf("unexpected bookmarks: %+v", bookmarks)
+	}
+
+	for _, n := range []string{"0", "3", "first"} {
+		if _, _, err := execute("bookmarks", "open", n); err == nil {
+			t.Errorf("bookmarks open %s: expected an error", n)
+		}
+	}
+	if _, _, err := execute("--parse-file", saved, "--bookmark", "-1"); err == nil {
+		t.Error("expected error for --bookmark -1")
+	}
+}
+
+func TestRunDeadline(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(5 * time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	execute := func(args ...string) error {
+		cmd := NewRootCommand()
+		cmd.SetArgs(args)
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		return cmd.Execute()
+	}
+
+	// The deadline replaces the longer --timeout
+	start := time.Now()
+	deadline := start.Add(1500 * time.Millisecond).Format(time.RFC3339Nano)
+	err := execute("-i", server.URL, "--timeout", "30", "--deadline", deadline, "golang")
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("search ran %s, past the deadline", elapsed)
+	}
+	if code := searcherrors.ExitCode(err); code != searcherrors.ExitNetwork {
+		t.Errorf("expected exit code %d for a missed deadline, got %d (%v)", searcherrors.ExitNetwork, code, err)
+	}
+
+	err = execute("-i", server.URL, "--deadline", "2024-01-01T00:00:00Z", "golang")
+	if err == nil || !strings.Contains(err.Error(), "deadline passed") {
+		t.Errorf("expected a past deadline to fail at once, got %v", err)
+	}
+	if err := execute("-i", server.URL, "--deadline", "tomorrow", "golang"); err == nil {
+		t.Error("expected error for an invalid --deadline")
+	}
+}
+
+func TestRunFirst(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	body := `{"query":"golang","number_of_results":2,"results":[` +
+		`{"title":"The Go Programming Language","url":"https://go.dev/","content":"Go is open source","engine":"google","score":2},` +
+		`{"title":"A Tour of Go","url":"https://go.dev/tour/","content":"","engine":"google","score":1}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("q") == "nothing" {
+			fmt.Fprint(w, `{"query":"nothing","results":[]}`)
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+
+	execute := func(args ...string) (string, error) {
+		return runRoot(t, args...)
+	}
+
+	for _, args := range [][]string{
+		{"-i", server.URL, "-1", "golang"},
+		{"-i", server.URL, "--first", "-f", "json", "golang"},
+	} {
+		out, err := execute(args...)
+		if err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+		if out != "https://go.dev/\n" {
+			t.Errorf("%v: output = %q, want exactly the first URL and a newline", args, out)
+		}
+	}
+
+	out, err := execute("-i", server.URL, "-1", "nothing")
+	if code := searcherrors.ExitCode(err); code != searcherrors.ExitNoResults {
+		t.Errorf("expected exit code %d without results, got %d (%v)", searcherrors.ExitNoResults, code, err)
+	}
+	if out != "" {
+		t.Errorf("expected no output without results, got %q", out)
+	}
+}
+
+func TestRunURLsOnly(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// The filter keeps one result per engine, dropping the spec
+	body := `{"query":"golang","number_of_results":3,"results":[` +
+		`{"title":"The Go Programming Language","url":"https://go.dev/","content":"Go is open source","engine":"google","score":2},` +
+		`{"title":"Spec","url":"https://go.dev/ref/spec","content":"","engine":"google","score":1.5},` +
+		`{"title":"A Tour of Go","url":"https://go.dev/tour/","content":"","engine":"bing","score":1}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("q") == "nothing" {
+			fmt.Fprint(w, `{"query":"nothing","results":[]}`)
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+
+	execute := func(args ...string) (string, error) {
+		return runRoot(t, append([]string{"-i", server.URL, "--no-cache", "--rate-limit", "0"}, args...)...)
+	}
+
+	for _, args := range [][]string{
+		{"--urls-only", "--results-per-engine", "1", "golang"},
+		{"--urls-only", "-f", "json", "--results-per-engine", "1", "golang"},
+	} {
+		out, err := execute(args...)
+		if err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+		if out != "https://go.dev/\nhttps://go.dev/tour/\n" {
+			t.Errorf("%v: output = %q, want exactly the 2 URLs, one per line", args, out)
+		}
+	}
+
+	out, err := execute("--urls-only", "nothing")
+	if code := searcherrors.ExitCode(err); code != searcherrors.ExitNoResults {
+		t.Errorf("expected exit code %d without results, got %d (%v)", searcherrors.ExitNoResults, code, err)
+	}
+	if out != "" {
+		t.Errorf("expected no output without results, got %q", out)
+	}
+}
+
+func TestRunNoWrap(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	content := strings.Repeat("gophers write concurrent programs ", 6)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"query":"golang","results":[{"title":"Go","url":"https://go.dev/","content":%q,"engine":"google"}]}`, content)
+	}))
+	defer server.Close()
+
+	execute := func(args ...string) string {
+		out, err := runRoot(t, args...)
+		if err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+		return out
+	}
+
+	// stdout is a pipe here, so content is left whole unless asked otherwise
+	if out := execute("-i", server.URL, "--no-color", "golang"); !strings.Contains(out, content) {
+		t.Errorf("piped output should not wrap content; got:\n%s", out)
+	}
+	if out := execute("-i", server.URL, "--no-color", "--no-wrap=false", "golang"); strings.Contains(out, content) {
+		t.Errorf("--no-wrap=false should wrap content; got:\n%s", out)
+	}
+}
+
+func TestRunResultCount(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// Ten results served four to a page, with no total reported, as most
+	// SearXNG instances do
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		page, _ := strconv.Atoi(r.URL.Query().Get("pageno"))
+		var items []string
+		for i := (page - 1) * 4; i < min(page*4, 10); i++ {
+			items = append(items, fmt.Sprintf(`{"title":"Result %d","url":"https://example.com/%d","engine":"test"}`, i+1, i+1))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"query":"golang","number_of_results":0,"results":[%s]}`, strings.Join(items, ","))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		results      string
+		wantResults  int
+		wantRequests int
+	}{
+		{"more than one page", "6", 6, 2},
+		{"less than one page", "3", 3, 1},
+		{"zero is the first page", "0", 4, 1},
+		{"more than there are", "20", 10, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests = 0
+			out, err := runRoot(t, "-i", server.URL, "--no-cache", "--rate-limit", "0", "-f", "json", "-n", tt.results, "golang")
+			if err != nil {
+				t.Fatalf("search failed: %v", err)
+			}
+
+			var resp struct {
+				Results []struct {
+					Title string `json:"title"`
+				} `json:"results"`
+			}
+			if err := json.Unmarshal([]byte(out), &resp); err != nil {
+				t.Fatalf("invalid JSON output: %v\n%s", err, out)
+			}
+			if len(resp.Results) != tt.wantResults {
+				t.Errorf("-n %s showed %d results, want %d", tt.results, len(resp.Results), tt.wantResults)
+			}
+			if len(resp.Results) > 0 && resp.Results[0].Title != "Result 1" {
+				t.Errorf("first result = %q, want Result 1", resp.Results[0].Title)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("-n %s sent %d requests, want %d", tt.results, requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestRunMinResults(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// Twenty results served four to a page, only every other one dated, so
+	// --since with --require-date keeps half of each page
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		page, _ := strconv.Atoi(r.URL.Query().Get("pageno"))
+		var items []string
+		for i := (page - 1) * 4; i < min(page*4, 20); i++ {
+			date := "null"
+			if i%2 == 0 {
+				date = `"2024-01-02T00:00:00"`
+			}
+			items = append(items, fmt.Sprintf(`{"title":"Result %d","url":"https://example.com/%d","engine":"test","publishedDate":%s}`, i+1, i+1, date))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"query":"golang","number_of_results":0,"results":[%s]}`, strings.Join(items, ","))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		args         []string
+		wantResults  int
+		wantRequests int
+	}{
+		{"no floor", []string{"-n", "3"}, 2, 1},
+		{"floor after filtering", []string{"-n", "3", "--min-results", "5"}, 5, 3},
+		{"floor below results", []string{"-n", "4", "--min-results", "1"}, 2, 1},
+		{"runs out", []string{"--min-results", "50"}, 10, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests = 0
+			out, err := runRoot(t, append([]string{"-i", server.URL, "--no-cache", "--rate-limit", "0", "-f", "json", "--since", "2023-01-01", "--require-date", "golang"}, tt.args...)...)
+			if err != nil {
+				t.Fatalf("search failed: %v", err)
+			}
+
+			var resp struct {
+				Results []struct {
+					Title string `json:"title"`
+				} `json:"results"`
+			}
+			if err := json.Unmarshal([]byte(out), &resp); err != nil {
+				t.Fatalf("invalid JSON output: %v\n%s", err, out)
+			}
+			if len(resp.Results) != tt.wantResults {
+				t.Errorf("%v showed %d results, want %d", tt.args, len(resp.Results), tt.wantResults)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("%v sent %d requests, want %d", tt.args, requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestRunInsecure(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"query":"golang","results":[{"title":"Go","url":"https://go.dev/","engine":"test"}]}`)
+	}))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0) // rejected handshakes are expected
+	server.StartTLS()
+	defer server.Close()
+
+	execute := func(args ...string) (string, error) {
+		_, stderr, err := runRootStderr(t, args...)
+		return stderr, err
+	}
+
+	// The self-signed certificate is rejected by default
+	if _, err := execute("-i", server.URL, "--no-cache", "golang"); err == nil {
+		t.Error("expected a certificate error without --insecure")
+	}
+
+	stderr, err := execute("-i", server.URL, "--no-cache", "--insecure", "golang")
+	if err != nil {
+		t.Fatalf("search with --insecure failed: %v", err)
+	}
+	if !strings.Contains(stderr, "WARNING: TLS certificate verification is disabled") {
+		t.Errorf("expected a warning on stderr, got:\n%s", stderr)
+	}
+}
+
+func TestRunEngineStats(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"query":"golang","results":[`+
+			`{"title":"Go","url":"https://go.dev/","engine":"google","engines":["google","bing"],"score":3},`+
+			`{"title":"Tour","url":"https://go.dev/tour/","engine":"google","score":1}]}`)
+	}))
+	defer server.Close()
+
+	execute := func(args ...string) (stdout, stderr string) {
+		t.Helper()
+		stdout, stderr, err := runRootStderr(t, append([]string{"-i", server.URL, "--no-cache", "--rate-limit", "0", "--engine-stats"}, args...)...)
+		if err != nil {
+			t.Fatalf("search %v failed: %v", args, err)
+		}
+		return stdout, stderr
+	}
+
+	stdout, stderr := execute("-f", "text", "golang")
+	if strings.Contains(stdout, "Avg score") {
+		t.Errorf("text output has the stats table on stdout:\n%s", stdout)
+	}
+	for _, want := range []string{"Engine  Results  Avg score", "google        2       2.00", "bing          1       3.00"} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("stderr is missing %q:\n%s", want, stderr)
+		}
+	}
+
+	stdout, stderr = execute("-f", "json", "golang")
+	if strings.Contains(stderr, "Avg score") {
+		t.Errorf("JSON mode wrote the stats table to stderr:\n%s", stderr)
+	}
+	var resp struct {
+		EngineStats []searxng.EngineStat `json:"engine_stats"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &resp); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+	if len(resp.EngineStats) != 2 || resp.EngineStats[0].Engine != "google" || resp.EngineStats[0].Results != 2 {
+		t.Errorf("engine_stats = %+v, want google with 2 results first", resp.EngineStats)
+	}
+}
+
+func TestRunListCategories(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	execute := func(args ...string) string {
+		t.Helper()
+		out, err := runRoot(t, args...)
+		if err != nil {
+			t.Fatalf("search %v failed: %v", args, err)
+		}
+		return out
+	}
+
+	// No query is needed, and one given is not searched for
+	for _, args := range [][]string{{"--list-categories"}, {"--list-categories", "golang"}} {
+		output := execute(args...)
+		if !strings.Contains(output, "Available Search Categories") || !strings.Contains(output, "images") {
+			t.Errorf("search %v output is missing the categories:\n%s", args, output)
+		}
+	}
+
+	var categories []categoryJSON
+	if err := json.Unmarshal([]byte(execute("-f", "json", "--list-categories")), &categories); err != nil {
+		t.Fatalf("-f json --list-categories is not JSON: %v", err)
+	}
+	if len(categories) == 0 || categories[0].Name != "general" {
+		t.Errorf("categories = %+v, want general first", categories)
+	}
+}
+
+func TestRunPreset(t *testing.T) {
+	var got url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"query":"golang","results":[]}`)
+	}))
+	defer ts.Close()
+
+	t.Setenv("HOME", t.TempDir())
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	data := "instance: " + ts.URL + "\npresets:\n  news-de:\n    category: news\n    language: de\n    time: day\n  broken:\n    time: decade\n"
+	if err := os.WriteFile(configPath, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	execute := func(args ...string) error {
+		_, err := runRoot(t, append([]string{"--config", configPath, "--no-cache"}, args...)...)
+		return err
+	}
+
+	if err := execute("--preset", "news-de", "golang"); err != nil {
+		t.Fatalf("search with --preset failed: %v", err)
+	}
+	if got.Get("categories") != "news" || got.Get("language") != "de" || got.Get("time_range") != "day" {
+		t.Errorf("preset not applied, instance received %v", got)
+	}
+
+	// Explicit flags win over the preset
+	if err := execute("--preset", "news-de", "-l", "fr", "--time", "week", "golang"); err != nil {
+		t.Fatalf("search with --preset and flags failed: %v", err)
+	}
+	if got.Get("categories") != "news" || got.Get("language") != "fr" || got.Get("time_range") != "week" {
+		t.Errorf("flags should override the preset, instance received %v", got)
+	}
+
+	err := execute("--preset", "missing", "golang")
+	if err == nil || !strings.Contains(err.Error(), "available presets: broken, news-de") {
+		t.Errorf("unknown preset error = %v, want the available presets listed", err)
+	}
+	if err := execute("--preset", "broken", "golang"); err == nil {
+		t.Error("expected an invalid time range in the preset to fail")
+	}
+}
+
+func TestRunStripHTML(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	saved := filepath.Join(t.TempDir(), "saved.json")
+	response := `{"query":"golang","results":[{"title":"<b>Go</b> &amp; Rust","url":"https://go.dev/","content":"Compare <em>Go</em><br>and Rust","engine":"google"}]}`
+	if err := os.WriteFile(saved, []byte(response), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	execute := func(args ...string) string {
+		out, err := runRoot(t, append([]string{"--parse-file", saved, "--no-color"}, args...)...)
+		if err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+		return out
+	}
+
+	for _, format := range []string{"text", "markdown", "table"} {
+		out := execute("-f", format)
+		if strings.Contains(out, "<b>") || strings.Contains(out, "&amp;") || !strings.Contains(out, "Go & Rust") {
+			t.Errorf("%s output should have the markup stripped, got:\n%s", format, out)
+		}
+	}
+	if out := execute("-f", "text"); !strings.Contains(out, "Compare Go and Rust") {
+		t.Errorf("text content should have the markup stripped, got:\n%s", out)
+	}
+
+	// JSON keeps the content as the instance sent it, unless asked
+	if out := execute("-f", "json"); !strings.Contains(out, `\u003cb\u003eGo\u003c/b\u003e \u0026amp; Rust`) {
+		t.Errorf("json output should keep the raw title, got:\n%s", out)
+	}
+	if out := execute("-f", "json", "--strip-html"); !strings.Contains(out, `"title": "Go \u0026 Rust"`) {
+		t.Errorf("json output with --strip-html should strip the title, got:\n%s", out)
+	}
+	if out := execute("-f", "text", "--strip-html=false"); !strings.Contains(out, "<b>Go</b>") {
+		t.Errorf("text output with --strip-html=false should keep the markup, got:\n%s", out)
+	}
+}
+
+func TestRunMergedPagesJSON(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// Pages of four overlap by one result and claim a large total
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("pageno"))
+		var items []string
+		for i := (page - 1) * 3; i < (page-1)*3+4; i++ {
+			items = append(items, fmt.Sprintf(`{"title":"Result %d","url":"https://example.com/%d","engine":"test"}`, i+1, i+1))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"query":"golang","number_of_results":125000,"results":[%s]}`, strings.Join(items, ","))
+	}))
+	defer server.Close()
+
+	out, err := runRoot(t, "-i", server.URL, "--no-cache", "--rate-limit", "0", "-f", "json", "-n", "7", "golang")
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+
+	var resp struct {
+		Metadata struct {
+			PagesFetched int `json:"pages_fetched"`
+		} `json:"metadata"`
+		Results []struct {
+			URL string `json:"url"`
+		} `json:"results"`
+		TotalResults int `json:"total_results"`
+	}
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+
+	var urls []string
+	for _, result := range resp.Results {
+		urls = append(urls, result.URL)
+	}
+	var want []string
+	for i := 1; i <= 7; i++ {
+		want = append(want, fmt.Sprintf("https://example.com/%d", i))
+	}
+	if strings.Join(urls, " ") != strings.Join(want, " ") {
+		t.Errorf("results = %v, want the deduplicated union %v", urls, want)
+	}
+	if resp.TotalResults != len(resp.Results) {
+		t.Errorf("total_results = %d, want %d to match the results array", resp.TotalResults, len(resp.Results))
+	}
+	if resp.Metadata.PagesFetched != 2 {
+		t.Errorf("pages_fetched = %d, want 2", resp.Metadata.PagesFetched)
+	}
+}
+
+func TestRunColor(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("NO_COLOR", "")
+	saved := filepath.Join(t.TempDir(), "saved.json")
+	response := `{"query":"golang","results":[{"title":"Go","url":"https://go.dev/","content":"The Go language","engine":"google"}]}`
+	if err := os.WriteFile(saved, []byte(response), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Output goes to a pipe, so auto means no color
+	tests := []struct {
+		args      []string
+		wantColor bool
+	}{
+		{nil, false},
+		{[]string{"--color", "auto"}, false},
+		{[]string{"--color", "always"}, true},
+		{[]string{"--color=never"}, false},
+		{[]string{"--no-color"}, false},
+		{[]string{"--no-color", "--color", "always"}, false},
+	}
+
+	for _, tt := range tests {
+		out, err := runRoot(t, append([]string{"--parse-file", saved}, tt.args...)...)
+		if err != nil {
+			t.Fatalf("%v failed: %v", tt.args, err)
+		}
+		if got := strings.Contains(out, "\033["); got != tt.wantColor {
+			t.Errorf("%v: colored = %v, want %v; output:\n%q", tt.args, got, tt.wantColor, out)
+		}
+	}
+
+	cmd := NewRootCommand()
+	cmd.SetArgs([]string{"--parse-file", saved, "--color", "sometimes"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected an invalid --color value to fail")
+	}
+}
+
+func TestRunBuiltinInstanceFallback(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("SEARCH_INSTANCE", "")
+
+	var downHits, upHits int
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downHits++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upHits++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"query":"golang","number_of_results":1,"results":[{"title":"Go","url":"https://go.dev","content":"","engine":"test","score":1}]}`)
+	}))
+	defer up.Close()
+
+	defaults := config.DefaultInstances
+	config.DefaultInstances = []string{down.URL, up.URL}
+	defer func() { config.DefaultInstances = defaults }()
+
+	execute := func(args ...string) (string, error) {
+		_, stderr, err := runRootStderr(t, append([]string{"--no-cache", "--rate-limit", "0", "-f", "json"}, args...)...)
+		return stderr, err
+	}
+
+	// A fresh install uses the default, which is down, and falls back
+	stderr, err := execute("golang")
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if downHits != 1 || upHits != 1 {
+		t.Errorf("expected a fallback to the next built-in, got %d and %d requests", downHits, upHits)
+	}
+	if !strings.Contains(stderr, "Note: built-in instance "+down.URL+" is unreachable") || !strings.Contains(stderr, "--instance") {
+		t.Errorf("expected a note suggesting --instance, got:\n%s", stderr)
+	}
+
+	// An instance the user chose doesn't fall back
+	if _, err := execute("-i", down.URL, "golang"); err == nil {
+		t.Error("expected an explicitly chosen instance that is down to fail")
+	}
+	if upHits != 1 {
+		t.Errorf("explicit instance should not fall back, got %d requests to the fallback", upHits)
+	}
+}
+
+func TestRunQueryFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		if q == "broken" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"query":%q,"results":[{"title":"About %s","url":"https://example.com/%s","engine":"test"}]}`, q, q, q)
+	}))
+	defer server.Close()
+
+	queries := filepath.Join(t.TempDir(), "queries.txt")
+	if err := os.WriteFile(queries, []byte("# research\ngolang\n\nbroken\nrust\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	execute := func(args ...string) (string, error) {
+		return runRoot(t, append([]string{"-i", server.URL, "--rate-limit", "0", "--query-file", queries}, args...)...)
+	}
+
+	out, err := execute("-f", "json")
+	if err != nil {
+		t.Fatalf("--query-file failed: %v", err)
+	}
+	var entries []struct {
+		Query   string `json:"query"`
+		Error   string `json:"error"`
+		Results []struct {
+			URL string `json:"url"`
+		} `json:"results"`
+		Metadata *struct{} `json:"metadata"`
+	}
+	if err := json.Unmarshal([]byte(out), &entries); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3 with comments and blank lines skipped:\n%s", len(entries), out)
+	}
+	for i, want := range []string{"golang", "broken", "rust"} {
+		if entries[i].Query != want {
+			t.Errorf("entry %d query = %q, want %q", i, entries[i].Query, want)
+		}
+	}
+	if len(entries[0].Results) != 1 || entries[0].Results[0].URL != "https://example.com/golang" || entries[0].Metadata == nil {
+		t.Errorf("golang entry should carry its results and metadata, got %+v", entries[0])
+	}
+	if entries[1].Error == "" || entries[1].Results != nil {
+		t.Errorf("broken entry should record its error, got %+v", entries[1])
+	}
+	if len(entries[2].Results) != 1 || entries[2].Results[0].URL != "https://example.com/rust" {
+		t.Errorf("rust entry should carry its results after the failure, got %+v", entries[2])
+	}
+
+	out, err = execute("-f", "text", "--no-color")
+	if err != nil {
+		t.Fatalf("--query-file text failed: %v", err)
+	}
+	golang, broken, rust := strings.Index(out, "=== golang ==="), strings.Index(out, "=== broken ==="), strings.Index(out, "=== rust ===")
+	if golang < 0 || broken < golang || rust < broken || !strings.Contains(out[broken:rust], "Error:") {
+		t.Errorf("text output should group results under each query in order, got:\n%s", out)
+	}
+
+	out, err = execute("-f", "json-lines-with-meta")
+	if err != nil {
+		t.Fatalf("--query-file json-lines-with-meta failed: %v", err)
+	}
+	var types []string
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		var record struct {
+			Type  string `json:"type"`
+			Query string `json:"query"`
+		}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		types = append(types, record.Type+":"+record.Query)
+	}
+	if got, want := strings.Join(types, " "), "meta:golang result: error:broken meta:rust result:"; got != want {
+		t.Errorf("records = %s, want %s", got, want)
+	}
+
+	if _, err := execute("golang"); err == nil {
+		t.Error("expected a query argument with --query-file to fail")
+	}
+
+	allBroken := filepath.Join(t.TempDir(), "broken.txt")
+	if err := os.WriteFile(allBroken, []byte("broken\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := execute("--query-file", allBroken); err == nil {
+		t.Error("expected an error when every query fails")
+	}
+}
+
+func TestRunQueryFileCircuitBreaker(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	queries := filepath.Join(t.TempDir(), "queries.txt")
+	if err := os.WriteFile(queries, []byte("one\ntwo\nthree\nfour\nfive\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// One query at a time, so the breaker has opened before the fourth
+	out, err := runRoot(t, "-i", server.URL, "--no-cache", "--retries", "0", "--rate-limit", "0",
+		"--concurrency", "1", "--query-file", queries, "-f", "json")
+
+	if err == nil {
+		t.Fatal("expected an error when every query fails")
+	}
+	if hits != searxng.DefaultBreakerThreshold {
+		t.Errorf("instance got %d requests, want %d before the breaker opens", hits, searxng.DefaultBreakerThreshold)
+	}
+	if got := strings.Count(out, "Skipping "+server.URL); got != 2 {
+		t.Errorf("expected the last 2 queries to be short-circuited, got %d:\n%s", got, out)
+	}
+}
+
+// TestRunQueryFileSharedCache runs a batch of repeated queries whose
+// workers share one cached client; run with -race.
+func TestRunQueryFileSharedCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// Each query takes several pages, for more cache traffic per worker
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q, page := r.URL.Query().Get("q"), r.URL.Query().Get("pageno")
+		var items []string
+		for i := 0; i < 5; i++ {
+			items = append(items, fmt.Sprintf(`{"title":"%s %s.%d","url":"https://example.com/%s/%s/%d","engine":"test"}`, q, page, i, q, page, i))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"query":%q,"number_of_results":100,"results":[%s]}`, q, strings.Join(items, ","))
+	}))
+	defer server.Close()
+
+	queries := filepath.Join(t.TempDir(), "queries.txt")
+	if err := os.WriteFile(queries, []byte(strings.Repeat("golang\nrust\n", 20)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runRoot(t, "-i", server.URL, "--rate-limit", "0", "--query-file", queries, "-f", "json", "-n", "15")
+	if err != nil {
+		t.Fatalf("--query-file failed: %v", err)
+	}
+	var entries []struct {
+		Query   string            `json:"query"`
+		Results []json.RawMessage `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(out), &entries); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+	if len(entries) != 40 {
+		t.Fatalf("got %d entries, want 40", len(entries))
+	}
+	for i, entry := range entries {
+		if len(entry.Results) != 15 {
+			t.Errorf("entry %d (%s) has %d results, want 15", i, entry.Query, len(entry.Results))
+		}
+	}
+}
+
+func TestRunOffset(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// Pages of four overlap by one result, so windows span pages unevenly
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("pageno"))
+		var items []string
+		for i := (page - 1) * 3; i < (page-1)*3+4; i++ {
+			items = append(items, fmt.Sprintf(`{"title":"Result %d","url":"https://example.com/%d","engine":"test"}`, i+1, i+1))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"query":"golang","number_of_results":125000,"results":[%s]}`, strings.Join(items, ","))
+	}))
+	defer server.Close()
+
+	execute := func(args ...string) (string, error) {
+		return runRoot(t, append([]string{"-i", server.URL, "--no-cache", "--rate-limit", "0", "--no-color"}, args...)...)
+	}
+
+	out, err := execute("-f", "json", "--offset", "10", "-n", "5", "golang")
+	if err != nil {
+		t.Fatalf("search with --offset failed: %v", err)
+	}
+	var resp struct {
+		Results []struct {
+			URL string `json:"url"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out)
+	}
+	var urls, want []string
+	for _, result := range resp.Results {
+		urls = append(urls, result.URL)
+	}
+	for i := 11; i <= 15; i++ {
+		want = append(want, fmt.Sprintf("https://example.com/%d", i))
+	}
+	if strings.Join(urls, " ") != strings.Join(want, " ") {
+		t.Errorf("results = %v, want the window %v", urls, want)
+	}
+
+	// Numbering in text output continues from the offset
+	out, err = execute("--offset", "10", "-n", "2", "golang")
+	if err != nil {
+		t.Fatalf("text search with --offset failed: %v", err)
+	}
+	if !strings.Contains(out, "[11] Result 11") || !strings.Contains(out, "[12] Result 12") || strings.Contains(out, "[1]") {
+		t.Errorf("expected results numbered 11 and 12, got:\n%s", out)
+	}
+
+	if _, err := execute("--offset", "-1", "golang"); err == nil {
+		t.Error("expected a negative --offset to fail")
+	}
+	if _, err := execute("--offset", "10", "--page", "2", "golang"); err == nil {
+		t.Error("expected --offset with --page to fail")
+	}
+}
+
+func TestRunReportsQueryRewrite(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		w.Header().Set("Content-Type", "application/json")
+		if q == "golanf" {
+			fmt.Fprint(w, `{"query":"golanf","results":[],"suggestions":["golang"]}`)
+			return
+		}
+		fmt.Fprintf(w, `{"query":%q,"results":[{"title":"Go","url":"https://go.dev/","engine":"test"}]}`, q)
+	}))
+	defer server.Close()
+
+	execute := func(args ...string) string {
+		out, err := runRoot(t, append([]string{"-i", server.URL, "--no-cache", "--rate-limit", "0", "--no-color"}, args...)...)
+		if err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+		return out
+	}
+
+	// Operators rewrite the query
+	out := execute("--site", "go.dev", "golang")
+	if !strings.Contains(out, "Showing results for golang (searched: golang site:go.dev)") {
+		t.Errorf("expected the rewrite in the text header, got:\n%s", out)
+	}
+	out = execute("-f", "json", "--site", "go.dev", "golang")
+	if !strings.Contains(out, `"query": "golang site:go.dev"`) || !strings.Contains(out, `"original_query": "golang"`) {
+		t.Errorf("expected query and original_query in JSON, got:\n%s", out)
+	}
+
+	// Auto-correct reports what was typed, not the corrected query
+	out = execute("-f", "markdown", "--auto-correct", "golanf")
+	if !strings.Contains(out, "Showing results for **golanf** (searched: golang)") {
+		t.Errorf("expected the correction in the markdown header, got:\n%s", out)
+	}
+
+	// An unchanged query gets no note
+	if out := execute("golang"); strings.Contains(out, "Showing results for") {
+		t.Errorf("expected no rewrite note, got:\n%s", out)
+	}
+	if out := execute("-f", "json", "golang"); strings.Contains(out, "original_query") {
+		t.Errorf("expected no original_query, got:\n%s", out)
+	}
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mule-ai/search/internal/config"
+)
+
+// newTemplatesCommand creates the templates command for managing named
+// output templates in ~/.config/search/templates.
+func newTemplatesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "templates",
+		Short: "Manage named output templates",
+		Long: `Manage named output templates for the --template flag.
+
+Templates are Go text/template files stored as ~/.config/search/templates/<name>.tmpl.
+Use one by name with --template <name>, or pass a file path directly.`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List available named templates",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			names, err := config.ListTemplates()
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if len(names) == 0 {
+				dir, _ := config.TemplatesDir()
+				fmt.Fprintf(out, "No templates found in %s\n", dir)
+				return nil
+			}
+			for _, name := range names {
+				fmt.Fprintln(out, name)
+			}
+			return nil
+		},
+	})
+
+	return cmd
+}
@@ -9,14 +9,16 @@ import (
 	"os"
 
 	"github.com/mule-ai/search/cmd/search/cli"
+	"github.com/mule-ai/search/internal/errors"
 )
 
 // main is the application entry point.
 //
-// It executes the CLI and exits with status 1 if an error occurs.
+// It executes the CLI and exits with a status chosen by errors.ExitCode
+// if an error occurs.
 func main() {
 	if err := cli.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 }
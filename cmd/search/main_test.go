@@ -3,6 +3,8 @@ package main
 import (
 	"bytes"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -91,6 +93,47 @@ func TestMainFunction(t *testing.T) {
 		}
 	})
 
+	// Test --fail-on-empty exit status against a mock returning no results
+	t.Run("fail on empty", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"query":"nothing","results":[]}`)
+		}))
+		defer ts.Close()
+
+		run := func(args ...string) (int, string, string) {
+			cmd := exec.Command(testBinary, args...)
+			cmd.Env = append(os.Environ(), "HOME="+tmpDir)
+			var stdout, stderr bytes.Buffer
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+			err := cmd.Run()
+			if exitErr, ok := err.(*exec.ExitError); ok {
+				return exitErr.ExitCode(), stdout.String(), stderr.String()
+			}
+			if err != nil {
+				t.Fatalf("failed to run binary: %v", err)
+			}
+			return 0, stdout.String(), stderr.String()
+		}
+
+		code, stdout, stderr := run("--instance", ts.URL, "--no-cache", "--fail-on-empty", "-f", "json", "nothing")
+		if code != 2 {
+			t.Errorf("expected exit status 2 with --fail-on-empty, got %d", code)
+		}
+		if !strings.Contains(stdout, `"results"`) {
+			t.Errorf("expected the empty result set to be printed, got:\n%s", stdout)
+		}
+		// An empty result is an outcome, not a misuse: one error line, no usage
+		if n := strings.Count(stderr, "Error:"); n != 1 || strings.Contains(stderr, "Usage:") {
+			t.Errorf("expected a single error line without usage on stderr, got:\n%s", stderr)
+		}
+
+		if code, _, _ := run("--instance", ts.URL, "--no-cache", "nothing"); code != 0 {
+			t.Errorf("expected exit status 0 without --fail-on-empty, got %d", code)
+		}
+	})
+
 	// Test completion command
 	t.Run("completion bash", func(t *testing.T) {
 		cmd := exec.Command(testBinary, "completion", "bash")
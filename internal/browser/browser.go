@@ -225,6 +225,51 @@ func IsSupported() bool {
 	return true
 }
 
+// ValidateCommand checks that the executable of a browser command such as
+// "firefox --private-window" can be found on PATH.
+func ValidateCommand(browserCmd string) error {
+	parts := strings.Fields(browserCmd)
+	if len(parts) == 0 {
+		return fmt.Errorf("browser command cannot be empty")
+	}
+	if _, err := exec.LookPath(parts[0]); err != nil {
+		return fmt.Errorf("browser %q not found on PATH: %w", parts[0], err)
+	}
+	return nil
+}
+
+// OpenURLsWithCommand opens URLs using a specific browser command.
+//
+// Each URL is validated and sanitized like OpenURL. The command is started
+// once with all URLs appended as arguments, which every common browser
+// accepts; each URL opens in its own tab.
+//
+// Example:
+//
+//	urls := []string{"https://example.com", "https://go.dev"}
+//	err := browser.OpenURLsWithCommand(urls, "firefox --new-tab")
+//	if err != nil {
+//	    log.Fatalf("Failed to open URLs: %v", err)
+//	}
+func OpenURLsWithCommand(urls []string, browserCmd string) error {
+	parts := strings.Fields(browserCmd)
+	if len(parts) == 0 {
+		return fmt.Errorf("browser command cannot be empty")
+	}
+
+	args := parts[1:]
+	for i, rawURL := range urls {
+		cleanURL, err := SanitizeURL(rawURL)
+		if err != nil {
+			return fmt.Errorf("failed to open URL %d (%s): %w", i+1, rawURL, err)
+		}
+		args = append(args, cleanURL)
+	}
+
+	// Start the command without waiting so the browser runs independently
+	return exec.Command(parts[0], args...).Start()
+}
+
 // OpenWithCommand opens a URL using a specific browser command.
 //
 // The browser command can include arguments (e.g., "firefox --private-window").
@@ -14,6 +14,7 @@ import (
 type CacheEntry struct {
 	Response interface{}
 	Expires  time.Time
+	ETag     string // Entity tag for revalidating an expired entry
 }
 
 // Cache is a thread-safe in-memory cache with LRU eviction.
@@ -56,8 +57,9 @@ func NewCache(maxSize int, ttl time.Duration) *Cache {
 //	    fmt.Printf("Found cached results: %d\n", len(resp.Results))
 //	}
 func (c *Cache) Get(key string) (interface{}, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	// A hit reorders the LRU list, so even reads need the write lock
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	entry, exists := c.store[key]
 	if !exists {
@@ -85,24 +87,69 @@ func (c *Cache) Get(key string) (interface{}, bool) {
 //	resp := &searxng.SearchResponse{Query: "golang", Results: [...]}
 //	cache.Set("query:golang", resp)
 func (c *Cache) Set(key string, value interface{}) {
+	c.SetWithETag(key, value, "")
+}
+
+// SetWithETag is like Set but also stores the entity tag the instance
+// sent with the response, so the entry can be revalidated once it expires.
+func (c *Cache) SetWithETag(key string, value interface{}, etag string) {
+	c.SetWithTTL(key, value, etag, c.ttl)
+}
+
+// SetWithTTL is like SetWithETag but keeps the entry for ttl instead of
+// the cache's TTL, e.g. a shorter time for a response that may be a blip.
+func (c *Cache) SetWithTTL(key string, value interface{}, etag string, ttl time.Duration) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	// Check if we need to evict
-	if len(c.store) >= c.maxSize {
+	// Replacing an entry (e.g. after revalidation) must not evict another
+	// or leave a duplicate in the LRU list
+	if _, exists := c.store[key]; exists {
+		c.removeFromList(key)
+	} else if len(c.store) >= c.maxSize {
 		c.evictLRU()
 	}
 
 	// Store the entry
 	c.store[key] = &CacheEntry{
 		Response: value,
-		Expires:  time.Now().Add(c.ttl),
+		Expires:  time.Now().Add(ttl),
+		ETag:     etag,
 	}
 
 	// Add to front of list
 	c.list = append([]string{key}, c.list...)
 }
 
+// GetStale retrieves a value and its entity tag even if the entry has
+// expired, for revalidation with If-None-Match. Expired entries stay
+// available until they are evicted or removed by Cleanup.
+func (c *Cache) GetStale(key string) (interface{}, string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, exists := c.store[key]
+	if !exists {
+		return nil, "", false
+	}
+	return entry.Response, entry.ETag, true
+}
+
+// Touch resets the expiry of an entry to the current time + TTL and
+// reports whether the entry exists.
+func (c *Cache) Touch(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, exists := c.store[key]
+	if !exists {
+		return false
+	}
+	entry.Expires = time.Now().Add(c.ttl)
+	c.moveToFront(key)
+	return true
+}
+
 // Delete removes an entry from the cache.
 //
 // Example:
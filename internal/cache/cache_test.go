@@ -2,6 +2,11 @@
 package cache
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -9,6 +14,9 @@ import (
 	"github.com/mule-ai/search/internal/searxng"
 )
 
+// testInstance is the instance URL used when computing cache keys in tests.
+const testInstance = "https://search.butler.ooo"
+
 // TestNewCache tests creating a new cache instance.
 func TestNewCache(t *testing.T) {
 	cache := NewCache(100, 5*time.Minute)
@@ -262,8 +270,8 @@ func TestCacheKey(t *testing.T) {
 		TimeRange:  "week",
 	}
 
-	key1 := cacheKey(req)
-	key2 := cacheKey(req)
+	key1 := cacheKey(testInstance, req)
+	key2 := cacheKey(testInstance, req)
 
 	// Same request should produce same key
 	if key1 != key2 {
@@ -276,12 +284,343 @@ func TestCacheKey(t *testing.T) {
 		Page:   1,
 		Format: "json",
 	}
-	key3 := cacheKey(req2)
+	key3 := cacheKey(testInstance, req2)
 	if key1 == key3 {
 		t.Error("different request produced same key")
 	}
 }
 
+// TestCacheKeyInstanceAndEngines tests that the instance and engine
+// selection are part of the key.
+func TestCacheKeyInstanceAndEngines(t *testing.T) {
+	req := &searxng.SearchRequest{Query: "golang", Page: 1, Format: "json", Engines: []string{"google", "bing"}}
+
+	if cacheKey("https://a.example", req) == cacheKey("https://b.example", req) {
+		t.Error("different instances produced the same key")
+	}
+
+	reordered := *req
+	reordered.Engines = []string{"bing", "google"}
+	if cacheKey(testInstance, req) != cacheKey(testInstance, &reordered) {
+		t.Error("engine order changed the key")
+	}
+	if req.Engines[0] != "google" {
+		t.Error("cacheKey modified the request's engines")
+	}
+
+	other := *req
+	other.Engines = []string{"duckduckgo"}
+	if cacheKey(testInstance, req) == cacheKey(testInstance, &other) {
+		t.Error("different engines produced the same key")
+	}
+
+	joined := &searxng.SearchRequest{Query: "golang", Page: 1, Format: "json", Categories: []string{"newsit"}}
+	split := &searxng.SearchRequest{Query: "golang", Page: 1, Format: "json", Categories: []string{"news", "it"}}
+	if cacheKey(testInstance, joined) == cacheKey(testInstance, split) {
+		t.Error("category boundaries are not part of the key")
+	}
+}
+
+// TestCachedClientSeparatesInstances tests that the same query against two
+// instances sharing one cache is cached twice.
+func TestCachedClientSeparatesInstances(t *testing.T) {
+	newServer := func(name string, hits *int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(hits, 1)
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"query":"golang","number_of_results":1,"results":[{"title":"%s","url":"https://%s.example","content":"","engine":"test","score":1}]}`, name, name)
+		}))
+	}
+
+	var hitsA, hitsB int32
+	serverA := newServer("a", &hitsA)
+	defer serverA.Close()
+	serverB := newServer("b", &hitsB)
+	defer serverB.Close()
+
+	shared := NewCache(10, time.Minute)
+	clientA := NewCachedClientWithCache(searxng.NewClientWithTimeout(serverA.URL, 5*time.Second), shared)
+	clientB := NewCachedClientWithCache(searxng.NewClientWithTimeout(serverB.URL, 5*time.Second), shared)
+
+	for i := 0; i < 2; i++ {
+		respA, err := clientA.Search(searxng.NewSearchRequest("golang"))
+		if err != nil {
+			t.Fatalf("search against instance A failed: %v", err)
+		}
+		respB, err := clientB.Search(searxng.NewSearchRequest("golang"))
+		if err != nil {
+			t.Fatalf("search against instance B failed: %v", err)
+		}
+		if respA.Results[0].Title != "a" || respB.Results[0].Title != "b" {
+			t.Fatalf("got results %q and %q, want each instance's own", respA.Results[0].Title, respB.Results[0].Title)
+		}
+	}
+
+	if size := shared.Size(); size != 2 {
+		t.Errorf("expected 2 cache entries, got %d", size)
+	}
+	if hitsA != 1 || hitsB != 1 {
+		t.Errorf("expected one request per instance, got %d and %d", hitsA, hitsB)
+	}
+}
+
+func TestCachedClientRevalidatesWithETag(t *testing.T) {
+	var full, notModified int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		atomic.AddInt32(&full, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `{"query":"golang","number_of_results":1,"results":[{"title":"Go","url":"https://go.dev","content":"","engine":"test","score":1}]}`)
+	}))
+	defer server.Close()
+
+	ttl := 20 * time.Millisecond
+	client := NewCachedClientWithCache(searxng.NewClientWithTimeout(server.URL, 5*time.Second), NewCache(10, ttl))
+
+	if _, err := client.Search(searxng.NewSearchRequest("golang")); err != nil {
+		t.Fatalf("first search failed: %v", err)
+	}
+	time.Sleep(2 * ttl)
+
+	resp, err := client.Search(searxng.NewSearchRequest("golang"))
+	if err != nil {
+		t.Fatalf("revalidating search failed: %v", err)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].Title != "Go" {
+		t.Fatalf("expected the cached body on 304, got %+v", resp.Results)
+	}
+	if full != 1 || notModified != 1 {
+		t.Errorf("expected 1 full and 1 conditional request, got %d and %d", full, notModified)
+	}
+
+	// The 304 refreshed the entry, so this is served from the cache
+	if _, err := client.Search(searxng.NewSearchRequest("golang")); err != nil {
+		t.Fatalf("third search failed: %v", err)
+	}
+	if full+notModified != 2 {
+		t.Errorf("expected no request for a refreshed entry, got %d total", full+notModified)
+	}
+}
+
+func TestCachedClientWithoutETag(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		if r.Header.Get("If-None-Match") != "" {
+			t.Errorf("unexpected If-None-Match %q without a stored ETag", r.Header.Get("If-None-Match"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"query":"golang","number_of_results":0,"results":[]}`)
+	}))
+	defer server.Close()
+
+	ttl := 20 * time.Millisecond
+	client := NewCachedClientWithCache(searxng.NewClientWithTimeout(server.URL, 5*time.Second), NewCache(10, ttl))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Search(searxng.NewSearchRequest("golang")); err != nil {
+			t.Fatalf("search %d failed: %v", i, err)
+		}
+		time.Sleep(2 * ttl)
+	}
+	if hits != 2 {
+		t.Errorf("expected 2 full requests, got %d", hits)
+	}
+	if size := client.GetCache().Size(); size != 1 {
+		t.Errorf("expected the refetched entry to replace the old one, got %d entries", size)
+	}
+}
+
+func TestCachedClientNegativeTTL(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("q") == "empty" {
+			fmt.Fprint(w, `{"query":"empty","number_of_results":0,"results":[]}`)
+			return
+		}
+		fmt.Fprint(w, `{"query":"golang","number_of_results":1,"results":[{"title":"Go","url":"https://go.dev","content":"","engine":"test","score":1}]}`)
+	}))
+	defer server.Close()
+
+	negativeTTL := 20 * time.Millisecond
+	client := NewCachedClient(searxng.NewClientWithTimeout(server.URL, 5*time.Second), 10, time.Minute)
+	client.SetNegativeTTL(negativeTTL)
+
+	search := func(query string) {
+		t.Helper()
+		if _, err := client.Search(searxng.NewSearchRequest(query)); err != nil {
+			t.Fatalf("search %q failed: %v", query, err)
+		}
+	}
+	search("golang")
+	search("empty")
+	time.Sleep(2 * negativeTTL)
+
+	// The populated response is still cached; the empty one has expired
+	search("golang")
+	if hits != 2 {
+		t.Errorf("expected the populated response from the cache, got %d requests", hits)
+	}
+	search("empty")
+	if hits != 3 {
+		t.Errorf("expected the empty response to be refetched, got %d requests", hits)
+	}
+}
+
+func TestCachedClientSearchReadOnly(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"query":%q,"results":[{"title":"Go","url":"https://go.dev","engine":"test"}]}`, r.URL.Query().Get("q"))
+	}))
+	defer server.Close()
+
+	client := NewCachedClient(searxng.NewClientWithTimeout(server.URL, 5*time.Second), 10, time.Minute)
+	if _, err := client.Search(searxng.NewSearchRequest("cached")); err != nil {
+		t.Fatal(err)
+	}
+
+	// A hit is still served from the cache
+	resp, err := client.SearchReadOnly(searxng.NewSearchRequest("cached"))
+	if err != nil || resp.Query != "cached" {
+		t.Fatalf("SearchReadOnly() = %v, %v", resp, err)
+	}
+	if hits != 1 {
+		t.Errorf("expected the hit to be served from the cache, got %d requests", hits)
+	}
+
+	// A miss is fetched but leaves the cache unchanged
+	for i := 0; i < 2; i++ {
+		resp, err := client.SearchReadOnly(searxng.NewSearchRequest("uncached"))
+		if err != nil || resp.Query != "uncached" {
+			t.Fatalf("SearchReadOnly() = %v, %v", resp, err)
+		}
+	}
+	if hits != 3 {
+		t.Errorf("expected each miss to be fetched, got %d requests", hits)
+	}
+	if size := client.GetStats().Size; size != 1 {
+		t.Errorf("expected only the first entry in the cache, got %d", size)
+	}
+}
+
+// TestCachedClientConcurrentHits tests callers sharing one cached client,
+// as batch workers do: each hit gets its own copy to modify; run with -race.
+func TestCachedClientConcurrentHits(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"query":"golang","results":[{"title":"Go","url":"https://go.dev","engine":"test"},{"title":"Tour","url":"https://go.dev/tour/","engine":"test"}]}`)
+	}))
+	defer server.Close()
+
+	client := NewCachedClient(searxng.NewClientWithTimeout(server.URL, 5*time.Second), 10, time.Minute)
+	if _, err := client.Search(searxng.NewSearchRequest("golang")); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				resp, err := client.Search(searxng.NewSearchRequest("golang"))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				// Filtering in place, as the result pipeline does
+				resp.Results = resp.Results[:1]
+				resp.Results[0].Title = "changed"
+				resp.PagesFetched = 1
+			}
+		}()
+	}
+	wg.Wait()
+
+	if hits != 1 {
+		t.Errorf("expected every search after the first to hit the cache, got %d requests", hits)
+	}
+	resp, err := client.Search(searxng.NewSearchRequest("golang"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Results) != 2 || resp.Results[0].Title != "Go" {
+		t.Errorf("callers' changes should not reach the cache, got %+v", resp.Results)
+	}
+}
+
+func TestCachedClientSkipsPartial(t *testing.T) {
+	var hits int32
+	body := `{"query":"golang","results":[{"title":"Go","url":"https://go.dev","engine":"test"},{"title":"Sp`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", fmt.Sprint(len(body)+50))
+		fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+
+	searchClient := searxng.NewClientWithTimeout(server.URL, 5*time.Second)
+	searchClient.SetAllowPartial(true)
+	client := NewCachedClient(searchClient, 10, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Search(searxng.NewSearchRequest("golang"))
+		if err != nil || !resp.Partial {
+			t.Fatalf("search %d: expected a partial response, got %v", i, err)
+		}
+	}
+	if hits != 2 {
+		t.Errorf("expected a partial response to be refetched, got %d requests", hits)
+	}
+}
+
+func TestCachedClientNegativeTTLCapped(t *testing.T) {
+	client := NewCachedClient(searxng.NewClientWithTimeout(testInstance, time.Second), 10, 10*time.Millisecond)
+	client.SetNegativeTTL(time.Hour)
+
+	client.store("key", &searxng.SearchResponse{}, "")
+	time.Sleep(20 * time.Millisecond)
+	if _, found := client.GetCache().Get("key"); found {
+		t.Error("expected the negative TTL to be capped at the cache TTL")
+	}
+}
+
+func TestCacheGetStaleAndTouch(t *testing.T) {
+	cache := NewCache(10, 10*time.Millisecond)
+	cache.SetWithETag("key", "value", `"abc"`)
+	time.Sleep(20 * time.Millisecond)
+
+	if _, found := cache.Get("key"); found {
+		t.Fatal("expected the entry to have expired")
+	}
+	value, etag, found := cache.GetStale("key")
+	if !found || value != "value" || etag != `"abc"` {
+		t.Fatalf("GetStale() = %v, %q, %v", value, etag, found)
+	}
+
+	if !cache.Touch("key") {
+		t.Fatal("Touch() should report an existing entry")
+	}
+	if _, found := cache.Get("key"); !found {
+		t.Error("expected the entry to be fresh after Touch")
+	}
+	if cache.Touch("missing") {
+		t.Error("Touch() should report a missing entry")
+	}
+}
+
 // TestCacheMoveToFront tests LRU list management.
 func TestCacheMoveToFront(t *testing.T) {
 	cache := NewCache(5, 5*time.Minute)
@@ -314,6 +653,35 @@ func TestCacheMoveToFront(t *testing.T) {
 	}
 }
 
+// TestCacheConcurrentGet tests that hits, which reorder the LRU list, are
+// safe from many goroutines; run with -race.
+func TestCacheConcurrentGet(t *testing.T) {
+	cache := NewCache(10, 5*time.Minute)
+	for i := 0; i < 5; i++ {
+		cache.Set(fmt.Sprintf("key%d", i), &searxng.SearchResponse{Query: fmt.Sprintf("query%d", i)})
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				key := fmt.Sprintf("key%d", (g+i)%5)
+				if _, found := cache.Get(key); !found {
+					t.Errorf("expected %s to be cached", key)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if cache.Size() != 5 {
+		t.Errorf("expected 5 entries, got %d", cache.Size())
+	}
+}
+
 // TestCacheCleanup tests expired entry cleanup.
 func TestCacheCleanup(t *testing.T) {
 	cache := NewCache(10, 10*time.Millisecond)
@@ -1,18 +1,27 @@
 package cache
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"slices"
+	"sort"
 	"time"
 
 	"github.com/mule-ai/search/internal/searxng"
 )
 
+// DefaultNegativeTTL is how long a response without results is cached by
+// default. It is kept short since an empty response is often a blip, such
+// as every engine timing out, that shouldn't hide results for a full TTL.
+const DefaultNegativeTTL = 30 * time.Second
+
 // CachedClient wraps a SearXNG client with caching functionality.
 type CachedClient struct {
-	client *searxng.Client
-	cache  *Cache
+	client      *searxng.Client
+	cache       *Cache
+	negativeTTL time.Duration // 0 uses DefaultNegativeTTL
 }
 
 // NewCachedClient creates a new cached SearXNG client.
@@ -32,19 +41,70 @@ func NewCachedClient(client *searxng.Client, maxCache int, ttl time.Duration) *C
 	}
 }
 
+// NewCachedClientWithCache creates a cached SearXNG client that stores
+// results in an existing cache, so several clients can share one cache.
+// Entries are keyed by instance, so clients for different instances never
+// see each other's results.
+//
+// Example:
+//
+//	shared := cache.NewCache(100, 5*time.Minute)
+//	primary := cache.NewCachedClientWithCache(searxng.NewClient(cfg), shared)
+func NewCachedClientWithCache(client *searxng.Client, c *Cache) *CachedClient {
+	return &CachedClient{
+		client: client,
+		cache:  c,
+	}
+}
+
 // Search executes a search query, using the cache if available.
 //
-// The cache key is generated from the search request parameters.
-// Cached results are returned immediately without an API call.
+// The cache key is generated from the instance URL and the search
+// request parameters. Responses without results are kept only for the
+// negative TTL (see SetNegativeTTL).
+// Cached results are returned immediately without an API call. Once an
+// entry expires, it is revalidated with If-None-Match if the instance
+// sent an ETag, and served again on 304 Not Modified.
 func (cc *CachedClient) Search(req *searxng.SearchRequest) (*searxng.SearchResponse, error) {
+	return cc.SearchContext(context.Background(), req)
+}
+
+// SearchContext is like Search but aborts an uncached request when ctx
+// is canceled.
+func (cc *CachedClient) SearchContext(ctx context.Context, req *searxng.SearchRequest) (*searxng.SearchResponse, error) {
+	return cc.search(ctx, req, true)
+}
+
+// SearchReadOnly is like Search but never writes to the cache: hits are
+// served as usual, while a miss is fetched from the instance and left
+// uncached. An expired entry is still revalidated, and served again on
+// 304 Not Modified, but not renewed.
+//
+// Example:
+//
+//	// Use what an earlier search cached without adding to it
+//	resp, err := cached.SearchReadOnly(req)
+func (cc *CachedClient) SearchReadOnly(req *searxng.SearchRequest) (*searxng.SearchResponse, error) {
+	return cc.SearchReadOnlyContext(context.Background(), req)
+}
+
+// SearchReadOnlyContext is like SearchReadOnly but aborts an uncached
+// request when ctx is canceled.
+func (cc *CachedClient) SearchReadOnlyContext(ctx context.Context, req *searxng.SearchRequest) (*searxng.SearchResponse, error) {
+	return cc.search(ctx, req, false)
+}
+
+// search serves req from the cache or the instance, storing what the
+// instance returns only if write is set.
+func (cc *CachedClient) search(ctx context.Context, req *searxng.SearchRequest, write bool) (*searxng.SearchResponse, error) {
 	// Generate cache key
-	key := cacheKey(req)
+	key := cacheKey(cc.client.GetInstance(), req)
 
 	// Try to get from cache
 	if cached, found := cc.cache.Get(key); found {
 		recordHit()
 		if resp, ok := cached.(*searxng.SearchResponse); ok {
-			return resp, nil
+			return copyResponse(resp), nil
 		}
 		// If type assertion fails, treat as cache miss
 		recordMiss()
@@ -52,41 +112,118 @@ func (cc *CachedClient) Search(req *searxng.SearchRequest) (*searxng.SearchRespo
 		recordMiss()
 	}
 
+	// An expired entry with an ETag can be revalidated instead of refetched
+	stale, etag, _ := cc.cache.GetStale(key)
+	staleResp, _ := stale.(*searxng.SearchResponse)
+	if staleResp != nil && etag != "" {
+		conditional := *req
+		conditional.IfNoneMatch = etag
+		req = &conditional
+	}
+
 	// Execute search - bypass cache and call client directly
-	resp, err := cc.client.Search(req)
+	resp, err := cc.client.SearchContext(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
-	// Store in cache
-	cc.cache.Set(key, resp)
+	// Not modified: serve the cached body and keep it for another TTL
+	if resp.NotModified && staleResp != nil {
+		if write {
+			cc.store(key, staleResp, etag)
+		}
+		return copyResponse(staleResp), nil
+	}
+
+	// Store in cache; responses without results expire sooner, and one
+	// cut off is left for the next search to fetch in full
+	if write && !resp.Partial {
+		cc.store(key, resp, resp.ETag)
+	}
 
 	return resp, nil
 }
 
-// cacheKey generates a unique cache key from a search request.
-func cacheKey(req *searxng.SearchRequest) string {
-	// Create a hash of the request parameters
+// cacheKey generates a unique cache key from the instance URL and a
+// search request. Engines are sorted since their order doesn't change
+// the results.
+func cacheKey(instance string, req *searxng.SearchRequest) string {
+	engines := append([]string(nil), req.Engines...)
+	sort.Strings(engines)
+
+	// Create a hash of the request parameters. Fields are NUL-separated
+	// so that, for example, "ab"+"c" and "a"+"bc" hash differently.
 	h := sha256.New()
-	h.Write([]byte(req.Query))
-	h.Write([]byte(fmt.Sprintf("%d", req.Page)))
-	h.Write([]byte(req.Format))
+	write := func(s string) {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	write(instance)
+	write(req.Query)
+	write(fmt.Sprintf("%d", req.Page))
+	write(req.Format)
 
 	for _, cat := range req.Categories {
-		h.Write([]byte(cat))
+		write(cat)
 	}
+	write("")
 
 	for _, lang := range req.Languages {
-		h.Write([]byte(lang))
+		write(lang)
+	}
+	write("")
+
+	for _, engine := range engines {
+		write(engine)
 	}
+	write("")
 
-	h.Write([]byte(fmt.Sprintf("%d", req.SafeSearch)))
-	h.Write([]byte(req.TimeRange))
+	write(fmt.Sprintf("%d", req.SafeSearch))
+	write(req.TimeRange)
 
 	// Return hex string (first 16 chars is enough for uniqueness)
 	return hex.EncodeToString(h.Sum(nil))[:16]
 }
 
+// SetNegativeTTL sets how long responses without results are cached. It
+// is capped at the cache's TTL; 0 restores DefaultNegativeTTL.
+//
+// Example:
+//
+//	cached := cache.NewCachedClient(client, 100, 5*time.Minute)
+//	cached.SetNegativeTTL(10 * time.Second)
+func (cc *CachedClient) SetNegativeTTL(ttl time.Duration) {
+	cc.negativeTTL = ttl
+}
+
+// store caches resp under key, for a shorter time when it has no results.
+func (cc *CachedClient) store(key string, resp *searxng.SearchResponse, etag string) {
+	resp = copyResponse(resp)
+	if len(resp.Results) > 0 {
+		cc.cache.SetWithETag(key, resp, etag)
+		return
+	}
+	ttl := cc.negativeTTL
+	if ttl == 0 {
+		ttl = DefaultNegativeTTL
+	}
+	cc.cache.SetWithTTL(key, resp, etag, min(ttl, cc.cache.ttl))
+}
+
+// copyResponse returns a copy of resp whose lists can be filtered or
+// appended to without changing the cached entry. Callers, such as the
+// workers of a batch, may share one cache and hit the same entry at once.
+func copyResponse(resp *searxng.SearchResponse) *searxng.SearchResponse {
+	c := *resp
+	c.Results = slices.Clone(resp.Results)
+	c.Answers = slices.Clone(resp.Answers)
+	c.Infoboxes = slices.Clone(resp.Infoboxes)
+	c.Suggestions = slices.Clone(resp.Suggestions)
+	c.Corrections = slices.Clone(resp.Corrections)
+	c.Instances = slices.Clone(resp.Instances)
+	return &c
+}
+
 // GetCache returns the underlying cache for direct access.
 //
 // This allows you to clear the cache, get stats, or perform other operations.
@@ -161,7 +161,7 @@ func TestCacheKeyDifferentRequests(t *testing.T) {
 
 	keys := make(map[string]bool)
 	for _, req := range requests {
-		key := cacheKey(req)
+		key := cacheKey(testInstance, req)
 		if keys[key] {
 			t.Errorf("duplicate key generated for different request: %s", key)
 		}
@@ -225,7 +225,7 @@ func TestCacheKeyConsistency(t *testing.T) {
 	// Generate keys multiple times
 	keys := make([]string, 10)
 	for i := 0; i < 10; i++ {
-		keys[i] = cacheKey(req)
+		keys[i] = cacheKey(testInstance, req)
 	}
 
 	// All keys should be the same
@@ -244,7 +244,7 @@ func TestCacheKeyHashLength(t *testing.T) {
 		Format: "json",
 	}
 
-	key := cacheKey(req)
+	key := cacheKey(testInstance, req)
 
 	// cacheKey returns first 16 chars of SHA256 hash
 	if len(key) != 16 {
@@ -273,6 +273,6 @@ func BenchmarkCacheKey(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_ = cacheKey(req)
+		_ = cacheKey(testInstance, req)
 	}
 }
\ No newline at end of file
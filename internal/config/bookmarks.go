@@ -0,0 +1,99 @@
+package config
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// bookmarksFileName is the file in ConfigDir holding saved results,
+// one JSON object per line.
+const bookmarksFileName = "bookmarks.jsonl"
+
+// Bookmark is a search result saved with --bookmark.
+type Bookmark struct {
+	Title string    `json:"title"`
+	URL   string    `json:"url"`
+	Query string    `json:"query"`
+	Time  time.Time `json:"time"`
+}
+
+// BookmarksPath returns the bookmarks file path
+// (~/.config/search/bookmarks.jsonl).
+func BookmarksPath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, bookmarksFileName), nil
+}
+
+// AppendBookmark adds b to the end of the bookmarks file, creating
+// ConfigDir and the file if needed. Existing bookmarks are never
+// rewritten.
+func AppendBookmark(b Bookmark) error {
+	path, err := BookmarksPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	line, err := json.Marshal(b)
+	if err != nil {
+		return fmt.Errorf("failed to marshal bookmark: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open bookmarks file: %w", err)
+	}
+	// One write per bookmark keeps concurrent appends from interleaving
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write bookmark: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write bookmark: %w", err)
+	}
+	return nil
+}
+
+// LoadBookmarks reads the bookmarks file, oldest first. A missing file
+// yields no bookmarks; blank lines are skipped.
+func LoadBookmarks() ([]Bookmark, error) {
+	path, err := BookmarksPath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Bookmark{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bookmarks file: %w", err)
+	}
+	defer f.Close()
+
+	bookmarks := []Bookmark{}
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var b Bookmark
+		if err := json.Unmarshal(line, &b); err != nil {
+			return nil, fmt.Errorf("failed to parse bookmarks file %s, line %d: %w", path, n, err)
+		}
+		bookmarks = append(bookmarks, b)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read bookmarks file: %w", err)
+	}
+	return bookmarks, nil
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBookmarks(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	bookmarks, err := LoadBookmarks()
+	if err != nil || len(bookmarks) != 0 {
+		t.Fatalf("LoadBookmarks() without a file = %v, %v; want none", bookmarks, err)
+	}
+
+	saved := []Bookmark{
+		{Title: "Go", URL: "https://go.dev", Query: "golang", Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{Title: "Tour", URL: "https://go.dev/tour/", Query: "go tour", Time: time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC)},
+	}
+	for _, b := range saved {
+		if err := AppendBookmark(b); err != nil {
+			t.Fatalf("AppendBookmark() error = %v", err)
+		}
+	}
+
+	bookmarks, err = LoadBookmarks()
+	if err != nil {
+		t.Fatalf("LoadBookmarks() error = %v", err)
+	}
+	if len(bookmarks) != len(saved) {
+		t.Fatalf("got %d bookmarks, want %d", len(bookmarks), len(saved))
+	}
+	for i, b := range bookmarks {
+		if b.Title != saved[i].Title || b.URL != saved[i].URL || b.Query != saved[i].Query || !b.Time.Equal(saved[i].Time) {
+			t.Errorf("bookmark %d = %+v, want %+v", i, b, saved[i])
+		}
+	}
+
+	path := filepath.Join(home, ".config", "search", "bookmarks.jsonl")
+	if got, _ := BookmarksPath(); got != path {
+		t.Errorf("BookmarksPath() = %q, want %q", got, path)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("\nnot json\n")
+	f.Close()
+	if _, err := LoadBookmarks(); err == nil {
+		t.Error("expected an error for a malformed line")
+	}
+}
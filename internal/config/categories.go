@@ -0,0 +1,99 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// categoriesFileName is the file in CacheDir caching the categories
+// instances report.
+const categoriesFileName = "categories.json"
+
+// InstanceCategories is an instance's category list as last fetched from
+// its /config endpoint.
+type InstanceCategories struct {
+	Categories []string  `json:"categories"`
+	Fetched    time.Time `json:"fetched"`
+}
+
+// CategoriesPath returns the category cache path
+// (~/.cache/search/categories.json).
+func CategoriesPath() (string, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, categoriesFileName), nil
+}
+
+// categoriesKey is the cache key for instance, so that
+// "https://searx.example/" and "https://searx.example" share an entry.
+func categoriesKey(instance string) string {
+	return strings.TrimSuffix(strings.TrimSpace(instance), "/")
+}
+
+// loadCategoryCache reads the category cache, keyed by instance URL. A
+// missing file yields an empty cache.
+func loadCategoryCache() (map[string]InstanceCategories, error) {
+	path, err := CategoriesPath()
+	if err != nil {
+		return nil, err
+	}
+	cache := make(map[string]InstanceCategories)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read categories file: %w", err)
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, fmt.Errorf("failed to parse categories file %s: %w", path, err)
+	}
+	return cache, nil
+}
+
+// LoadCachedCategories returns the categories cached for instance, or nil
+// when none are cached.
+func LoadCachedCategories(instance string) (*InstanceCategories, error) {
+	cache, err := loadCategoryCache()
+	if err != nil {
+		return nil, err
+	}
+	entry, ok := cache[categoriesKey(instance)]
+	if !ok {
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+// SaveCachedCategories records categories as instance's list, fetched
+// now, creating CacheDir if needed. Other instances' entries are kept.
+func SaveCachedCategories(instance string, categories []string) error {
+	cache, err := loadCategoryCache()
+	if err != nil {
+		return err
+	}
+	cache[categoriesKey(instance)] = InstanceCategories{Categories: categories, Fetched: time.Now().UTC()}
+
+	path, err := CategoriesPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal categories: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write categories file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCachedCategories(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cached, err := LoadCachedCategories("https://a.example")
+	if err != nil || cached != nil {
+		t.Fatalf("LoadCachedCategories() without a file = %v, %v; want nil", cached, err)
+	}
+
+	if err := SaveCachedCategories("https://a.example/", []string{"general", "q&a"}); err != nil {
+		t.Fatalf("SaveCachedCategories() error = %v", err)
+	}
+	if err := SaveCachedCategories("https://b.example", []string{"images"}); err != nil {
+		t.Fatalf("SaveCachedCategories() error = %v", err)
+	}
+
+	cached, err = LoadCachedCategories("https://a.example")
+	if err != nil || cached == nil {
+		t.Fatalf("LoadCachedCategories() = %v, %v", cached, err)
+	}
+	if got := strings.Join(cached.Categories, ","); got != "general,q&a" {
+		t.Errorf("cached categories = %q, want general,q&a", got)
+	}
+	if cached.Fetched.IsZero() {
+		t.Error("fetch time was not recorded")
+	}
+
+	cached, err = LoadCachedCategories("https://b.example/")
+	if err != nil || cached == nil || strings.Join(cached.Categories, ",") != "images" {
+		t.Errorf("LoadCachedCategories(b) = %v, %v; want images", cached, err)
+	}
+
+	path, _ := CategoriesPath()
+	if err := os.WriteFile(path, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCachedCategories("https://a.example"); err == nil {
+		t.Error("expected an error for a malformed categories file")
+	}
+}
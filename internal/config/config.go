@@ -2,7 +2,7 @@
 //
 // It handles loading configuration from files, environment variables, and CLI flags,
 // with proper precedence handling and validation. Configuration is stored in YAML format
-// at ~/.search/config.yaml by default.
+// at ~/.config/search/config.yaml by default.
 package config
 
 import (
@@ -10,16 +10,103 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
 )
 
 const (
-	defaultConfigDir = ".search"
-	configFileName   = "config.yaml"
+	// legacyConfigDir is the directory under home used before the XDG
+	// directories; see ConfigDir
+	legacyConfigDir = ".search"
+	configFileName  = "config.yaml"
 )
 
+// ConfigPathEnv is the environment variable naming the config file to use
+// instead of the default config file when --config is not given.
+const ConfigPathEnv = "SEARCH_CONFIG"
+
+// validFormats lists the output formats accepted in the config file.
+var validFormats = []string{"json", "ndjson", "jsonl-pretty", "json-lines-with-meta", "markdown", "text", "table"}
+
+// isValidFormat reports whether format is one of validFormats.
+func isValidFormat(format string) bool {
+	for _, f := range validFormats {
+		if format == f {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultInstances are the built-in public instances, in order of
+// preference. The first is the default instance; when it can't be reached
+// and no instance was chosen, searches fall back to the others in turn.
+var DefaultInstances = []string{
+	"https://search.butler.ooo",
+	"https://searx.be",
+	"https://priv.au",
+}
+
+// DefaultInstance returns the instance used when none is configured, the
+// first of DefaultInstances.
+func DefaultInstance() string {
+	return DefaultInstances[0]
+}
+
+// Auth types for requests to the instance. Bearer sends APIKey in the
+// Authorization header; HMAC signs each request with AuthSecret.
+const (
+	AuthTypeBearer = "bearer"
+	AuthTypeHMAC   = "hmac"
+)
+
+// DefaultAuthHeader is the header that carries the HMAC signature when
+// auth_header is not set.
+const DefaultAuthHeader = "X-Signature"
+
+// DefaultCacheNegativeTTL is how long, in seconds, a response without
+// results is cached by default, so a transient empty response soon
+// expires.
+const DefaultCacheNegativeTTL = 30
+
+// DefaultMaxResponseBytes is the default upper bound on the size of a
+// SearXNG response body (5 MB).
+const DefaultMaxResponseBytes int64 = 5 << 20
+
+// DefaultAutoCorrectThreshold is the result count below which --auto-correct
+// retries the search with SearXNG's first suggestion.
+const DefaultAutoCorrectThreshold = 3
+
+// DefaultOpenAllMax is the most results --open-all opens without
+// confirmation.
+const DefaultOpenAllMax = 10
+
+// DefaultMaxIdleConns is the default number of idle keep-alive
+// connections kept open to the instance.
+const DefaultMaxIdleConns = 10
+
+// DefaultIdleConnTimeout is how long, in seconds, an idle keep-alive
+// connection stays open by default.
+const DefaultIdleConnTimeout = 90
+
+// DefaultConcurrency is the default number of searches run at once by
+// commands that query in parallel.
+const DefaultConcurrency = 4
+
+// DefaultRateLimit is the default number of requests per second sent to
+// an instance; public instances often block clients that go faster.
+const DefaultRateLimit = 1
+
+// DefaultMaxRedirects is the default number of redirects followed by
+// requests to an instance.
+const DefaultMaxRedirects = 3
+
+// MaxRetries bounds Retries; every attempt shares the one timeout, so
+// more would leave each too little time to succeed.
+const MaxRetries = 10
+
 // Config holds the application configuration.
 //
 // The configuration includes settings for the SearXNG instance, search parameters,
@@ -39,6 +126,51 @@ type Config struct {
 	CacheEnabled bool `yaml:"cache_enabled,omitempty" mapstructure:"cache_enabled"`
 	CacheSize    int  `yaml:"cache_size,omitempty" mapstructure:"cache_size"`
 	CacheTTL     int  `yaml:"cache_ttl,omitempty" mapstructure:"cache_ttl"` // in seconds
+	// CacheNegativeTTL is how long, in seconds, responses without results are cached
+	CacheNegativeTTL int `yaml:"cache_negative_ttl,omitempty" mapstructure:"cache_negative_ttl"`
+	// MaxResponseBytes caps the size of a response body read from the instance
+	MaxResponseBytes int64 `yaml:"max_response_bytes,omitempty" mapstructure:"max_response_bytes"`
+	// AppendQuery is appended to every query (e.g. "-site:spam.example")
+	AppendQuery string `yaml:"append_query,omitempty" mapstructure:"append_query"`
+	// AutoCorrectThreshold is the result count below which --auto-correct reruns the search
+	AutoCorrectThreshold int `yaml:"auto_correct_threshold,omitempty" mapstructure:"auto_correct_threshold"`
+	// AcceptLanguage is the Accept-Language header sent to the instance,
+	// which sets its UI language; empty sends the search language
+	AcceptLanguage string `yaml:"accept_language,omitempty" mapstructure:"accept_language"`
+	// Browser is the command (with optional arguments) used by --open instead of the OS default
+	Browser string `yaml:"browser,omitempty" mapstructure:"browser"`
+	// Spinner is the style of the --verbose search spinner: dots (default), line or none
+	Spinner string `yaml:"spinner,omitempty" mapstructure:"spinner"`
+	// OpenAllMax is the most results --open-all opens without confirmation
+	OpenAllMax int `yaml:"open_all_max,omitempty" mapstructure:"open_all_max"`
+	// Connection reuse: idle keep-alive connections and how long they stay open (seconds)
+	MaxIdleConns    int `yaml:"max_idle_conns,omitempty" mapstructure:"max_idle_conns"`
+	IdleConnTimeout int `yaml:"idle_conn_timeout,omitempty" mapstructure:"idle_conn_timeout"`
+	// Concurrency caps how many searches run at once
+	Concurrency int `yaml:"concurrency,omitempty" mapstructure:"concurrency"`
+	// Retries is how many times a failed search is retried within Timeout
+	Retries int `yaml:"retries,omitempty" mapstructure:"retries"`
+	// RateLimit is the most requests per second sent to an instance (0 = unlimited)
+	RateLimit float64 `yaml:"rate_limit" mapstructure:"rate_limit"`
+	// MaxRedirects is how many redirects a request follows (0 = none)
+	MaxRedirects int `yaml:"max_redirects" mapstructure:"max_redirects"`
+	// ExtraParams are added to the search query string, e.g. theme or
+	// enabled_plugins; parameters the client sets itself win
+	ExtraParams map[string]string `yaml:"extra_params,omitempty" mapstructure:"extra_params"`
+	// Insecure skips TLS certificate verification for the instance, for
+	// self-hosted instances with self-signed certificates
+	Insecure bool `yaml:"insecure,omitempty" mapstructure:"insecure"`
+	// AuthType is how requests are authenticated: bearer (the default,
+	// with APIKey) or hmac, which signs each request's path and query
+	// with AuthSecret and sends the signature in AuthHeader
+	AuthType   string `yaml:"auth_type,omitempty" mapstructure:"auth_type"`
+	AuthSecret string `yaml:"auth_secret,omitempty" mapstructure:"auth_secret"`
+	AuthHeader string `yaml:"auth_header,omitempty" mapstructure:"auth_header"`
+	// TimeRange limits results to the last day, week, month or year; it is
+	// set by --time or a preset rather than the config file
+	TimeRange string `yaml:"-" mapstructure:"-"`
+	// Presets are named search shapes selected with --preset
+	Presets map[string]Preset `yaml:"presets,omitempty" mapstructure:"presets"`
 }
 
 // NewConfig creates a new Config with default values.
@@ -68,6 +200,16 @@ func NewConfig() *Config {
 //   - CacheEnabled: true
 //   - CacheSize: 100
 //   - CacheTTL: 300 (5 minutes)
+//   - CacheNegativeTTL: 30 seconds
+//   - MaxResponseBytes: 5 MB
+//   - AutoCorrectThreshold: 3
+//   - OpenAllMax: 10
+//   - MaxIdleConns: 10
+//   - IdleConnTimeout: 90 seconds
+//   - Concurrency: 4
+//   - Retries: 0
+//   - RateLimit: 1 request per second
+//   - MaxRedirects: 3
 //
 // Example:
 //
@@ -76,7 +218,7 @@ func NewConfig() *Config {
 //	// cfg.Results == 10
 func DefaultConfig() *Config {
 	return &Config{
-		Instance:     "https://search.butler.ooo",
+		Instance:     DefaultInstance(),
 		Results:      10,
 		Format:       "text",
 		Timeout:      30,
@@ -86,6 +228,15 @@ func DefaultConfig() *Config {
 		CacheEnabled: true,
 		CacheSize:    100,
 		CacheTTL:     300,
+		CacheNegativeTTL: DefaultCacheNegativeTTL,
+		MaxResponseBytes: DefaultMaxResponseBytes,
+		AutoCorrectThreshold: DefaultAutoCorrectThreshold,
+		OpenAllMax:           DefaultOpenAllMax,
+		MaxIdleConns:         DefaultMaxIdleConns,
+		IdleConnTimeout:      DefaultIdleConnTimeout,
+		Concurrency:          DefaultConcurrency,
+		RateLimit:            DefaultRateLimit,
+		MaxRedirects:         DefaultMaxRedirects,
 	}
 }
 
@@ -122,36 +273,32 @@ func WriteConfig(path string, cfg Config) error {
 	return nil
 }
 
-// Load loads the configuration from the default location (~/.search/config.yaml).
+// Load loads the configuration from the default location
+// (~/.config/search/config.yaml, see ConfigDir).
 //
 // If the config file doesn't exist, it will be created with default values.
 // If the file exists but can't be read, an error is returned.
 // After loading, default values are applied to any empty fields.
 func (c *Config) Load() error {
-	v := viper.New()
-
-	// Set config name and type
-	v.SetConfigName(configFileName)
-
-	// Find home directory
-	home, err := os.UserHomeDir()
+	configDir, err := ConfigDir()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return err
 	}
 
-	configDir := filepath.Join(home, defaultConfigDir)
-
 	// Create config directory if it doesn't exist
 	if err := os.MkdirAll(configDir, 0o755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	v.AddConfigPath(configDir)
+	// The file is named explicitly: viper's config name excludes the
+	// extension, so "config.yaml" would never be found
+	v := viper.New()
+	v.SetConfigFile(filepath.Join(configDir, configFileName))
 
 	// Try to read config file
 	if err := v.ReadInConfig(); err != nil {
 		// If config doesn't exist, create default
-		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
+		if errors.Is(err, os.ErrNotExist) {
 			// Apply defaults to current config before saving
 			c.applyDefaults()
 			return c.Save()
@@ -170,18 +317,26 @@ func (c *Config) Load() error {
 	return nil
 }
 
-// Save saves the configuration to the default location (~/.search/config.yaml).
+// DefaultConfigPath returns the default config file path
+// (~/.config/search/config.yaml, see ConfigDir).
+func DefaultConfigPath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, configFileName), nil
+}
+
+// Save saves the configuration to the default location (see DefaultConfigPath).
 //
 // The config directory will be created if it doesn't exist.
 // Returns an error if the directory can't be created or the file can't be written.
 func (c *Config) Save() error {
-	home, err := os.UserHomeDir()
+	configDir, err := ConfigDir()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return err
 	}
 
-	configDir := filepath.Join(home, defaultConfigDir)
-
 	if err := os.MkdirAll(configDir, 0o755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
@@ -224,6 +379,68 @@ func (c *Config) SaveTo(path string) error {
 	return nil
 }
 
+// SetInstanceInFile sets the instance key of the config file at path,
+// leaving its other keys and comments as they are, and creates the file
+// if it doesn't exist. Unlike SaveTo, it writes nothing from the defaults
+// or the environment, and the file is left with mode 0600 since it may
+// hold an API key.
+//
+// Example:
+//
+//	err := config.SetInstanceInFile("/home/user/.config/search/config.yaml", "https://searx.example")
+func SetInstanceInFile(path, instance string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse config: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("failed to parse config: %s is not a YAML mapping", path)
+	}
+
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: instance}
+	found := false
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "instance" {
+			value.LineComment = root.Content[i+1].LineComment
+			root.Content[i+1] = value
+			found = true
+		}
+	}
+	if !found {
+		key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "instance"}
+		root.Content = append([]*yaml.Node{key, value}, root.Content...)
+	}
+
+	var buf strings.Builder
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	enc.Close()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(buf.String()), 0600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	// WriteFile keeps the mode of a file that already exists
+	if err := os.Chmod(path, 0600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
 // Validate validates the configuration fields.
 //
 // It checks that:
@@ -231,7 +448,16 @@ func (c *Config) SaveTo(path string) error {
 //   - Results is between 1 and 100
 //   - Timeout is between 1 and 300
 //   - SafeSearch is between 0 and 2
-//   - Format is one of: json, markdown, text
+//   - Format is one of: json, ndjson, jsonl-pretty, json-lines-with-meta, markdown, text, table
+//   - MaxResponseBytes is not negative
+//   - AutoCorrectThreshold is not negative
+//   - OpenAllMax is not negative
+//   - MaxIdleConns and IdleConnTimeout are not negative
+//   - Concurrency is not negative
+//   - Retries is between 0 and MaxRetries
+//   - RateLimit is not negative
+//   - MaxRedirects is not negative
+//   - AuthType is empty, bearer or hmac, and hmac has an AuthSecret
 //
 // Returns an error describing the validation failure, or nil if valid.
 func (c *Config) Validate() error {
@@ -247,12 +473,81 @@ func (c *Config) Validate() error {
 	if c.SafeSearch < 0 || c.SafeSearch > 2 {
 		return fmt.Errorf("safe search level must be between 0 and 2, got %d", c.SafeSearch)
 	}
-	if c.Format != "" && c.Format != "json" && c.Format != "markdown" && c.Format != "text" {
-		return fmt.Errorf("invalid format '%s', must be json, markdown, or text", c.Format)
+	if c.Format != "" && !isValidFormat(c.Format) {
+		return fmt.Errorf("invalid format '%s', must be one of: %s", c.Format, strings.Join(validFormats, ", "))
+	}
+	if c.CacheNegativeTTL < 0 {
+		return fmt.Errorf("cache negative TTL cannot be negative, got %d", c.CacheNegativeTTL)
+	}
+	if c.MaxResponseBytes < 0 {
+		return fmt.Errorf("max response bytes cannot be negative, got %d", c.MaxResponseBytes)
+	}
+	if c.AutoCorrectThreshold < 0 {
+		return fmt.Errorf("auto correct threshold cannot be negative, got %d", c.AutoCorrectThreshold)
+	}
+	if c.OpenAllMax < 0 {
+		return fmt.Errorf("open all max cannot be negative, got %d", c.OpenAllMax)
+	}
+	if c.MaxIdleConns < 0 {
+		return fmt.Errorf("max idle conns cannot be negative, got %d", c.MaxIdleConns)
+	}
+	if c.IdleConnTimeout < 0 {
+		return fmt.Errorf("idle conn timeout cannot be negative, got %d", c.IdleConnTimeout)
+	}
+	if c.Concurrency < 0 {
+		return fmt.Errorf("concurrency cannot be negative, got %d", c.Concurrency)
+	}
+	if c.Retries < 0 || c.Retries > MaxRetries {
+		return fmt.Errorf("retries must be between 0 and %d, got %d", MaxRetries, c.Retries)
+	}
+	if c.RateLimit < 0 {
+		return fmt.Errorf("rate limit cannot be negative, got %g", c.RateLimit)
+	}
+	if c.MaxRedirects < 0 {
+		return fmt.Errorf("max redirects cannot be negative, got %d", c.MaxRedirects)
+	}
+	switch c.AuthType {
+	case "", AuthTypeBearer:
+	case AuthTypeHMAC:
+		if c.AuthSecret == "" {
+			return fmt.Errorf("auth type hmac requires auth_secret")
+		}
+	default:
+		return fmt.Errorf("invalid auth type '%s', must be one of: %s, %s", c.AuthType, AuthTypeBearer, AuthTypeHMAC)
 	}
 	return nil
 }
 
+// RedactSecret masks a secret for display, keeping only its last 4
+// characters. Secrets of 4 characters or fewer are masked entirely.
+//
+// Example:
+//
+//	config.RedactSecret("sk-1234567890abcd") // "****abcd"
+func RedactSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	if len(secret) <= 4 {
+		return "****"
+	}
+	return "****" + secret[len(secret)-4:]
+}
+
+// RedactedCopy returns a copy of the config that is safe to print, with
+// the API key and HMAC secret masked by RedactSecret.
+//
+// Example:
+//
+//	fmt.Fprintf(os.Stderr, "Config: %+v\n", *cfg.RedactedCopy())
+func (c *Config) RedactedCopy() *Config {
+	cp := *c
+	cp.Categories = append([]string(nil), c.Categories...)
+	cp.APIKey = RedactSecret(c.APIKey)
+	cp.AuthSecret = RedactSecret(c.AuthSecret)
+	return &cp
+}
+
 // ApplyDefaults sets default values for empty fields.
 //
 // This is a public version of applyDefaults that can be called externally.
@@ -267,7 +562,7 @@ func (c *Config) ApplyDefaults() {
 // It should be set to 1 only in NewConfig().
 func (c *Config) applyDefaults() {
 	if c.Instance == "" {
-		c.Instance = "https://search.butler.ooo"
+		c.Instance = DefaultInstance()
 	}
 	if c.Results == 0 {
 		c.Results = 10
@@ -290,33 +585,86 @@ func (c *Config) applyDefaults() {
 	if c.CacheTTL == 0 {
 		c.CacheTTL = 300
 	}
+	if c.CacheNegativeTTL == 0 {
+		c.CacheNegativeTTL = DefaultCacheNegativeTTL
+	}
+	if c.MaxResponseBytes == 0 {
+		c.MaxResponseBytes = DefaultMaxResponseBytes
+	}
+	if c.AutoCorrectThreshold == 0 {
+		c.AutoCorrectThreshold = DefaultAutoCorrectThreshold
+	}
+	if c.OpenAllMax == 0 {
+		c.OpenAllMax = DefaultOpenAllMax
+	}
+	if c.MaxIdleConns == 0 {
+		c.MaxIdleConns = DefaultMaxIdleConns
+	}
+	if c.IdleConnTimeout == 0 {
+		c.IdleConnTimeout = DefaultIdleConnTimeout
+	}
+	if c.Concurrency == 0 {
+		c.Concurrency = DefaultConcurrency
+	}
 	// Note: CacheEnabled defaults to false here so that it must be explicitly enabled
 	// Note: We don't set a default for SafeSearch here because 0 is a valid value
 	// It should be set to 1 only in NewConfig()
 }
 
+// ExpandPath expands a leading "~/" to the user's home directory and
+// $VAR or ${VAR} references to their environment values.
+//
+// Example:
+//
+//	path, err := config.ExpandPath("~/work/search.yaml")
+//	// path == "/home/user/work/search.yaml"
+func ExpandPath(path string) (string, error) {
+	path = os.ExpandEnv(path)
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		path = filepath.Join(home, path[1:])
+	}
+	return path, nil
+}
+
 // LoadConfig loads configuration with proper priority handling.
 //
 // Priority order (highest to lowest):
 // 1. CLI flags
-// 2. Environment variables
-// 3. Config file
-// 4. Default values
+// 2. The preset named by cliCfg.Preset
+// 3. Environment variables
+// 4. Config file
+// 5. Default values
 //
-// If cliCfg.ConfigPath is set, that file will be used instead of the default.
-// Returns a validated Config or an error if loading/validating fails.
+// If cliCfg.ConfigPath is set, that file will be used instead of the default,
+// and otherwise the file named by the SEARCH_CONFIG environment variable.
+// Returns a validated Config or an error if loading/validating fails, or if
+// the preset isn't configured.
 func LoadConfig(cliCfg *CliConfig) (*Config, error) {
 	// Start with defaults
 	cfg := NewConfig()
 
+	// --config wins over SEARCH_CONFIG
+	configPath := cliCfg.ConfigPath
+	if configPath == "" {
+		configPath = os.Getenv(ConfigPathEnv)
+	}
+
 	// Load from config file (unless --config is specified with non-existent file)
-	if cliCfg.ConfigPath == "" {
+	if configPath == "" {
 		if err := cfg.Load(); err != nil {
 			return nil, fmt.Errorf("failed to load config: %w", err)
 		}
 	} else {
+		path, err := ExpandPath(configPath)
+		if err != nil {
+			return nil, err
+		}
 		v := viper.New()
-		v.SetConfigFile(cliCfg.ConfigPath)
+		v.SetConfigFile(path)
 		if err := v.ReadInConfig(); err != nil {
 			return nil, fmt.Errorf("failed to read config file: %w", err)
 		}
@@ -329,6 +677,13 @@ func LoadConfig(cliCfg *CliConfig) (*Config, error) {
 	// Apply environment variables (override config file)
 	cfg.applyEnvironmentVariables()
 
+	// Apply the preset, which shapes this query but yields to explicit flags
+	if cliCfg.Preset != "" {
+		if err := cfg.ApplyPreset(cliCfg.Preset); err != nil {
+			return nil, err
+		}
+	}
+
 	// Apply CLI flags (highest priority)
 	cliCfg.ApplyToConfig(cfg)
 
@@ -343,6 +698,11 @@ func LoadConfig(cliCfg *CliConfig) (*Config, error) {
 func LoadConfigFromFile(path string) (*Config, error) {
 	cfg := NewConfig()
 
+	path, err := ExpandPath(path)
+	if err != nil {
+		return nil, err
+	}
+
 	v := viper.New()
 	v.SetConfigFile(path)
 
@@ -397,6 +757,15 @@ func (c *Config) applyEnvironmentVariables() {
 	if v := os.Getenv("SEARCH_API_KEY"); v != "" {
 		c.APIKey = v
 	}
+	if v := os.Getenv("SEARCH_AUTH_SECRET"); v != "" {
+		c.AuthSecret = v
+	}
+	if v := os.Getenv("SEARCH_APPEND_QUERY"); v != "" {
+		c.AppendQuery = v
+	}
+	if v := os.Getenv("SEARCH_BROWSER"); v != "" {
+		c.Browser = v
+	}
 }
 
 // CliConfig holds CLI-specific configuration overrides.
@@ -423,6 +792,30 @@ type CliConfig struct {
 	NoCache      bool  // Shortcut for --no-cache to disable caching
 	CacheSize    *int  // Pointer to distinguish between not set and 0
 	CacheTTL     *int  // Pointer to distinguish between not set and 0
+	// Response size limit in bytes (0 means not set)
+	MaxResponseBytes int64
+	// NoAppendQuery disables the configured append_query for this search
+	NoAppendQuery bool
+	// Browser overrides the configured browser command
+	Browser string
+	// Spinner overrides the configured spinner style
+	Spinner string
+	// AcceptLanguage overrides the configured Accept-Language header
+	AcceptLanguage string
+	// Concurrency overrides the configured number of parallel searches
+	Concurrency int
+	// Retries overrides the configured retry count
+	Retries *int // Pointer to distinguish between not set and 0
+	// RateLimit overrides the configured requests per second
+	RateLimit *float64 // Pointer to distinguish between not set and 0
+	// MaxRedirects overrides the configured redirect limit
+	MaxRedirects *int // Pointer to distinguish between not set and 0
+	// ExtraParams are merged over the configured extra parameters
+	ExtraParams map[string]string
+	// Insecure overrides the configured TLS verification setting
+	Insecure *bool // Pointer to distinguish between not set, false, and true
+	// Preset names the configured preset to apply below the flags
+	Preset string
 }
 
 // ApplyToConfig applies CLI config values to the main Config.
@@ -454,6 +847,9 @@ func (c *CliConfig) ApplyToConfig(cfg *Config) {
 	if c.APIKey != "" {
 		cfg.APIKey = c.APIKey
 	}
+	if c.TimeRange != "" {
+		cfg.TimeRange = c.TimeRange
+	}
 	cfg.Verbose = c.Verbose
 	// Handle cache settings
 	if c.CacheEnabled != nil {
@@ -471,6 +867,46 @@ func (c *CliConfig) ApplyToConfig(cfg *Config) {
 	if c.NoCache {
 		cfg.CacheEnabled = false
 	}
+	if c.MaxResponseBytes > 0 {
+		cfg.MaxResponseBytes = c.MaxResponseBytes
+	}
+	if c.NoAppendQuery {
+		cfg.AppendQuery = ""
+	}
+	if c.Spinner != "" {
+		cfg.Spinner = c.Spinner
+	}
+	if c.Browser != "" {
+		cfg.Browser = c.Browser
+	}
+	if c.AcceptLanguage != "" {
+		cfg.AcceptLanguage = c.AcceptLanguage
+	}
+	if c.Concurrency > 0 {
+		cfg.Concurrency = c.Concurrency
+	}
+	if c.Retries != nil {
+		cfg.Retries = *c.Retries
+	}
+	if c.RateLimit != nil {
+		cfg.RateLimit = *c.RateLimit
+	}
+	if c.MaxRedirects != nil {
+		cfg.MaxRedirects = *c.MaxRedirects
+	}
+	if c.Insecure != nil {
+		cfg.Insecure = *c.Insecure
+	}
+	if len(c.ExtraParams) > 0 {
+		merged := make(map[string]string, len(cfg.ExtraParams)+len(c.ExtraParams))
+		for k, v := range cfg.ExtraParams {
+			merged[k] = v
+		}
+		for k, v := range c.ExtraParams {
+			merged[k] = v
+		}
+		cfg.ExtraParams = merged
+	}
 }
 
 func parseIntEnv(v string) int {
@@ -98,7 +98,7 @@ func TestSave(t *testing.T) {
 		}
 
 		// Verify file exists at expected location
-		configPath := filepath.Join(tmpHome, defaultConfigDir, configFileName)
+		configPath := filepath.Join(tmpHome, ".config", appDirName, configFileName)
 		if _, err := os.Stat(configPath); os.IsNotExist(err) {
 			t.Errorf("Config file not found at %s", configPath)
 		}
@@ -483,7 +483,7 @@ func TestLoadWithErrorHandling(t *testing.T) {
 		}
 
 		// Config file should be created
-		configPath := filepath.Join(tmpHome, defaultConfigDir, configFileName)
+		configPath := filepath.Join(tmpHome, ".config", appDirName, configFileName)
 		if _, err := os.Stat(configPath); os.IsNotExist(err) {
 			t.Error("Config file should be created")
 		}
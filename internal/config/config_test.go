@@ -1,8 +1,10 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/spf13/viper"
@@ -377,3 +379,426 @@ func TestCliConfigApplyToConfig(t *testing.T) {
 		t.Errorf("Expected CLI API key 'cli-api-key', got '%s'", cfg.APIKey)
 	}
 }
+
+func TestCliConfigNoAppendQuery(t *testing.T) {
+	cfg := NewConfig()
+	cfg.AppendQuery = "-site:spam.example"
+
+	(&CliConfig{SafeSearch: -1}).ApplyToConfig(cfg)
+	if cfg.AppendQuery != "-site:spam.example" {
+		t.Errorf("Expected append query to be kept, got '%s'", cfg.AppendQuery)
+	}
+
+	(&CliConfig{SafeSearch: -1, NoAppendQuery: true}).ApplyToConfig(cfg)
+	if cfg.AppendQuery != "" {
+		t.Errorf("Expected append query to be cleared, got '%s'", cfg.AppendQuery)
+	}
+}
+
+func TestAppendQueryEnvironmentVariable(t *testing.T) {
+	t.Setenv("SEARCH_APPEND_QUERY", "-site:spam.example")
+
+	cfg := &Config{}
+	cfg.applyEnvironmentVariables()
+	if cfg.AppendQuery != "-site:spam.example" {
+		t.Errorf("Expected append query from env, got '%s'", cfg.AppendQuery)
+	}
+}
+
+func TestBrowserOverride(t *testing.T) {
+	t.Setenv("SEARCH_BROWSER", "firefox")
+
+	cfg := &Config{}
+	cfg.applyEnvironmentVariables()
+	if cfg.Browser != "firefox" {
+		t.Errorf("Expected browser from env, got '%s'", cfg.Browser)
+	}
+
+	(&CliConfig{SafeSearch: -1}).ApplyToConfig(cfg)
+	if cfg.Browser != "firefox" {
+		t.Errorf("Expected browser to be kept, got '%s'", cfg.Browser)
+	}
+
+	(&CliConfig{SafeSearch: -1, Browser: "chromium --incognito"}).ApplyToConfig(cfg)
+	if cfg.Browser != "chromium --incognito" {
+		t.Errorf("Expected browser from flag, got '%s'", cfg.Browser)
+	}
+}
+
+func TestAutoCorrectThresholdDefault(t *testing.T) {
+	if cfg := DefaultConfig(); cfg.AutoCorrectThreshold != DefaultAutoCorrectThreshold {
+		t.Errorf("Expected default threshold %d, got %d", DefaultAutoCorrectThreshold, cfg.AutoCorrectThreshold)
+	}
+
+	cfg := &Config{}
+	cfg.applyDefaults()
+	if cfg.AutoCorrectThreshold != DefaultAutoCorrectThreshold {
+		t.Errorf("Expected applyDefaults to set threshold %d, got %d", DefaultAutoCorrectThreshold, cfg.AutoCorrectThreshold)
+	}
+
+	cfg = DefaultConfig()
+	cfg.AutoCorrectThreshold = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for negative auto correct threshold")
+	}
+}
+
+func TestRedactSecret(t *testing.T) {
+	tests := map[string]string{
+		"":                  "",
+		"abc":               "****",
+		"abcd":              "****",
+		"sk-1234567890abcd": "****abcd",
+	}
+	for in, want := range tests {
+		if got := RedactSecret(in); got != want {
+			t.Errorf("RedactSecret(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRedactedCopy(t *testing.T) {
+	cfg := NewConfig()
+	cfg.APIKey = "sk-supersecretkey-9876"
+
+	redacted := cfg.RedactedCopy()
+	if strings.Contains(fmt.Sprintf("%+v", *redacted), cfg.APIKey) {
+		t.Errorf("Redacted config still contains the API key: %+v", *redacted)
+	}
+	if redacted.APIKey != "****9876" {
+		t.Errorf("Expected masked key '****9876', got '%s'", redacted.APIKey)
+	}
+	if cfg.APIKey != "sk-supersecretkey-9876" {
+		t.Error("RedactedCopy should not modify the original config")
+	}
+
+	cfg.AuthSecret = "hmac-secret-4321"
+	if got := cfg.RedactedCopy().AuthSecret; got != "****4321" {
+		t.Errorf("Expected masked HMAC secret '****4321', got '%s'", got)
+	}
+
+	redacted.Categories[0] = "changed"
+	if cfg.Categories[0] == "changed" {
+		t.Error("RedactedCopy should not share the categories slice")
+	}
+}
+
+func TestExpandPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("SEARCH_TEST_DIR", "/etc/search")
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"~/foo", filepath.Join(home, "foo")},
+		{"~", home},
+		{"$HOME/foo", home + "/foo"},
+		{"${SEARCH_TEST_DIR}/config.yaml", "/etc/search/config.yaml"},
+		{"/abs/path/config.yaml", "/abs/path/config.yaml"},
+		{"relative/config.yaml", "relative/config.yaml"},
+		{"~user/foo", "~user/foo"},
+	}
+
+	for _, tt := range tests {
+		got, err := ExpandPath(tt.in)
+		if err != nil {
+			t.Fatalf("ExpandPath(%q) error = %v", tt.in, err)
+		}
+		if got != tt.want {
+			t.Errorf("ExpandPath(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLoadConfigExpandsConfigPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("SEARCH_INSTANCE", "")
+
+	dir := filepath.Join(home, "work")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "search.yaml"), []byte("instance: https://tilde.example.com\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{"~/work/search.yaml", "$HOME/work/search.yaml"} {
+		cfg, err := LoadConfig(&CliConfig{ConfigPath: path, SafeSearch: -1})
+		if err != nil {
+			t.Fatalf("LoadConfig(%q) error = %v", path, err)
+		}
+		if cfg.Instance != "https://tilde.example.com" {
+			t.Errorf("LoadConfig(%q) instance = %q, want config file value", path, cfg.Instance)
+		}
+
+		cfg, err = LoadConfigFromFile(path)
+		if err != nil {
+			t.Fatalf("LoadConfigFromFile(%q) error = %v", path, err)
+		}
+		if cfg.Instance != "https://tilde.example.com" {
+			t.Errorf("LoadConfigFromFile(%q) instance = %q, want config file value", path, cfg.Instance)
+		}
+	}
+}
+
+func TestOpenAllMaxDefault(t *testing.T) {
+	if cfg := DefaultConfig(); cfg.OpenAllMax != DefaultOpenAllMax {
+		t.Errorf("Expected default open all max %d, got %d", DefaultOpenAllMax, cfg.OpenAllMax)
+	}
+
+	cfg := &Config{}
+	cfg.applyDefaults()
+	if cfg.OpenAllMax != DefaultOpenAllMax {
+		t.Errorf("Expected applyDefaults to set open all max %d, got %d", DefaultOpenAllMax, cfg.OpenAllMax)
+	}
+
+	cfg = DefaultConfig()
+	cfg.OpenAllMax = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for negative open all max")
+	}
+}
+
+func TestConcurrencyDefault(t *testing.T) {
+	if cfg := DefaultConfig(); cfg.Concurrency != DefaultConcurrency {
+		t.Errorf("Expected default concurrency %d, got %d", DefaultConcurrency, cfg.Concurrency)
+	}
+
+	cfg := DefaultConfig()
+	(&CliConfig{Concurrency: 8}).ApplyToConfig(cfg)
+	if cfg.Concurrency != 8 {
+		t.Errorf("Expected concurrency override 8, got %d", cfg.Concurrency)
+	}
+
+	cfg.Concurrency = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for negative concurrency")
+	}
+}
+
+func TestRetries(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.Retries != 0 {
+		t.Errorf("Expected retries off by default, got %d", cfg.Retries)
+	}
+
+	three := 3
+	(&CliConfig{Retries: &three}).ApplyToConfig(cfg)
+	if cfg.Retries != 3 {
+		t.Errorf("Expected retries override 3, got %d", cfg.Retries)
+	}
+
+	zero := 0
+	(&CliConfig{Retries: &zero}).ApplyToConfig(cfg)
+	if cfg.Retries != 0 {
+		t.Errorf("Expected --retries 0 to turn retries off, got %d", cfg.Retries)
+	}
+
+	for _, n := range []int{-1, MaxRetries + 1} {
+		cfg.Retries = n
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Expected error for retries %d", n)
+		}
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.RateLimit != DefaultRateLimit {
+		t.Errorf("Expected default rate limit %v, got %v", float64(DefaultRateLimit), cfg.RateLimit)
+	}
+
+	zero := 0.0
+	(&CliConfig{RateLimit: &zero}).ApplyToConfig(cfg)
+	if cfg.RateLimit != 0 {
+		t.Errorf("Expected --rate-limit 0 to turn the limit off, got %v", cfg.RateLimit)
+	}
+
+	cfg.RateLimit = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for negative rate limit")
+	}
+}
+
+func TestMaxRedirects(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.MaxRedirects != DefaultMaxRedirects {
+		t.Errorf("Expected default max redirects %d, got %d", DefaultMaxRedirects, cfg.MaxRedirects)
+	}
+
+	zero := 0
+	(&CliConfig{MaxRedirects: &zero}).ApplyToConfig(cfg)
+	if cfg.MaxRedirects != 0 {
+		t.Errorf("Expected --max-redirects 0 to follow no redirects, got %d", cfg.MaxRedirects)
+	}
+
+	cfg.MaxRedirects = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for negative max redirects")
+	}
+}
+
+func TestValidateAuthType(t *testing.T) {
+	cfg := DefaultConfig()
+	for _, authType := range []string{"", AuthTypeBearer} {
+		cfg.AuthType = authType
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("auth type %q: unexpected error %v", authType, err)
+		}
+	}
+
+	cfg.AuthType = AuthTypeHMAC
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for hmac auth without a secret")
+	}
+	cfg.AuthSecret = "s3cret"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("hmac with a secret: unexpected error %v", err)
+	}
+
+	cfg.AuthType = "digest"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for an unknown auth type")
+	}
+}
+
+func TestSetInstanceInFile(t *testing.T) {
+	t.Setenv("SEARCH_API_KEY", "sk-from-the-environment")
+	path := filepath.Join(t.TempDir(), "search", "config.yaml")
+
+	// A missing file is created with only the instance
+	if err := SetInstanceInFile(path, "https://a.example"); err != nil {
+		t.Fatalf("SetInstanceInFile() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "instance: https://a.example\n" {
+		t.Errorf("new config = %q, want only the instance", data)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("config mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	// Other keys stay as they are, and a file that isn't a mapping is refused
+	if err := os.WriteFile(path, []byte("results: 20\ninstance: https://a.example # primary\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetInstanceInFile(path, "https://b.example"); err != nil {
+		t.Fatalf("SetInstanceInFile() error = %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	if string(data) != "results: 20\ninstance: https://b.example # primary\n" {
+		t.Errorf("updated config = %q", data)
+	}
+	if err := os.WriteFile(path, []byte("- not\n- a mapping\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetInstanceInFile(path, "https://b.example"); err == nil {
+		t.Error("expected an error for a config that is not a mapping")
+	}
+}
+
+func TestLoadConfigRateLimit(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		name string
+		yaml string
+		want float64
+	}{
+		{"unset keeps the default", "instance: https://searx.example.com\n", DefaultRateLimit},
+		{"zero disables the limit", "rate_limit: 0\n", 0},
+		{"fractional", "rate_limit: 0.5\n", 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(home, "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := LoadConfig(&CliConfig{ConfigPath: path, SafeSearch: -1})
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if cfg.RateLimit != tt.want {
+				t.Errorf("rate limit = %v, want %v", cfg.RateLimit, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigExtraParams(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	path := filepath.Join(home, "config.yaml")
+	yaml := "extra_params:\n  theme: simple\n  enabled_plugins: Hash_plugin\n"
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(&CliConfig{
+		ConfigPath:  path,
+		SafeSearch:  -1,
+		ExtraParams: map[string]string{"theme": "contrast", "image_proxy": "true"},
+	})
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	want := map[string]string{"theme": "contrast", "enabled_plugins": "Hash_plugin", "image_proxy": "true"}
+	if len(cfg.ExtraParams) != len(want) {
+		t.Fatalf("extra params = %v, want %v", cfg.ExtraParams, want)
+	}
+	for k, v := range want {
+		if cfg.ExtraParams[k] != v {
+			t.Errorf("extra params[%q] = %q, want %q", k, cfg.ExtraParams[k], v)
+		}
+	}
+}
+
+func TestLoadConfigFromEnvPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("SEARCH_CONFIG_DIR", home)
+
+	writeConfig := func(name, instance string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(home, name), []byte("instance: "+instance+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeConfig("env.yaml", "https://env.example")
+	writeConfig("flag.yaml", "https://flag.example")
+
+	// ~ and $VAR are expanded
+	for _, path := range []string{"~/env.yaml", "$SEARCH_CONFIG_DIR/env.yaml"} {
+		t.Setenv("SEARCH_CONFIG", path)
+		cfg, err := LoadConfig(&CliConfig{SafeSearch: -1})
+		if err != nil {
+			t.Fatalf("LoadConfig() with SEARCH_CONFIG=%s error = %v", path, err)
+		}
+		if cfg.Instance != "https://env.example" {
+			t.Errorf("SEARCH_CONFIG=%s: instance = %q, want https://env.example", path, cfg.Instance)
+		}
+	}
+
+	cfg, err := LoadConfig(&CliConfig{ConfigPath: filepath.Join(home, "flag.yaml"), SafeSearch: -1})
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.Instance != "https://flag.example" {
+		t.Errorf("--config with SEARCH_CONFIG set: instance = %q, want https://flag.example", cfg.Instance)
+	}
+
+	t.Setenv("SEARCH_CONFIG", filepath.Join(home, "missing.yaml"))
+	if _, err := LoadConfig(&CliConfig{SafeSearch: -1}); err == nil {
+		t.Error("expected an error for a missing SEARCH_CONFIG file")
+	}
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// stateFileName is the file in ConfigDir holding state kept between
+// runs, such as the round-robin position in an instance list.
+const stateFileName = "state"
+
+// Instance selection modes for an instance list.
+const (
+	InstanceSelectRoundRobin = "roundrobin"
+	InstanceSelectRandom     = "random"
+)
+
+// State is the data kept in the state file between runs.
+type State struct {
+	// InstanceRotation maps an instance list's absolute path to the index
+	// of the instance the next round-robin run uses
+	InstanceRotation map[string]int `yaml:"instance_rotation,omitempty"`
+}
+
+// StatePath returns the state file path (~/.config/search/state).
+func StatePath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, stateFileName), nil
+}
+
+// LoadState reads the state file. A missing file yields an empty State.
+func LoadState() (*State, error) {
+	path, err := StatePath()
+	if err != nil {
+		return nil, err
+	}
+	state := &State{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+	if err := yaml.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", path, err)
+	}
+	return state, nil
+}
+
+// Save writes the state file, creating ConfigDir if needed.
+func (s *State) Save() error {
+	path, err := StatePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	return nil
+}
+
+// ReadInstanceList reads an instance list file with one URL per line.
+// Blank lines and lines starting with "#" are skipped. The URLs are not
+// validated.
+//
+// Example:
+//
+//	instances, err := config.ReadInstanceList("~/.config/search/instances.txt")
+func ReadInstanceList(path string) ([]string, error) {
+	path, err := ExpandPath(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read instance list: %w", err)
+	}
+	defer f.Close()
+
+	var instances []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		instances = append(instances, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read instance list: %w", err)
+	}
+	if len(instances) == 0 {
+		return nil, fmt.Errorf("instance list %s has no instances", path)
+	}
+	return instances, nil
+}
+
+// SelectInstances orders the instances in the list at path for one run:
+// the chosen instance first, then the others in file order after it
+// (wrapping around) as fallbacks.
+//
+// With InstanceSelectRandom the first instance is picked at random. With
+// InstanceSelectRoundRobin it is the one after the instance the previous
+// run started with, tracked per list in the state file.
+func SelectInstances(path string, instances []string, mode string) ([]string, error) {
+	start := 0
+	switch mode {
+	case InstanceSelectRandom:
+		start = rand.Intn(len(instances))
+	case InstanceSelectRoundRobin, "":
+		key, err := ExpandPath(path)
+		if err != nil {
+			return nil, err
+		}
+		if abs, err := filepath.Abs(key); err == nil {
+			key = abs
+		}
+		state, err := LoadState()
+		if err != nil {
+			return nil, err
+		}
+		if state.InstanceRotation == nil {
+			state.InstanceRotation = make(map[string]int)
+		}
+		start = state.InstanceRotation[key] % len(instances)
+		if start < 0 {
+			start = 0
+		}
+		state.InstanceRotation[key] = (start + 1) % len(instances)
+		if err := state.Save(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown instance selection mode %q", mode)
+	}
+
+	ordered := make([]string, 0, len(instances))
+	for i := range instances {
+		ordered = append(ordered, instances[(start+i)%len(instances)])
+	}
+	return ordered, nil
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeInstanceList(t *testing.T, lines ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "instances.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadInstanceList(t *testing.T) {
+	path := writeInstanceList(t, "# public instances", "https://a.example", "", "  https://b.example  ", "#https://c.example")
+	instances, err := ReadInstanceList(path)
+	if err != nil {
+		t.Fatalf("ReadInstanceList() error = %v", err)
+	}
+	if got := strings.Join(instances, ","); got != "https://a.example,https://b.example" {
+		t.Errorf("ReadInstanceList() = %q", got)
+	}
+
+	if _, err := ReadInstanceList(writeInstanceList(t, "# nothing here", "")); err == nil {
+		t.Error("expected an error for a list without instances")
+	}
+	if _, err := ReadInstanceList(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestSelectInstancesRoundRobin(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	path := writeInstanceList(t)
+	instances := []string{"https://a.example", "https://b.example", "https://c.example"}
+
+	want := []string{
+		"https://a.example,https://b.example,https://c.example",
+		"https://b.example,https://c.example,https://a.example",
+		"https://c.example,https://a.example,https://b.example",
+		"https://a.example,https://b.example,https://c.example",
+	}
+	for i, w := range want {
+		got, err := SelectInstances(path, instances, InstanceSelectRoundRobin)
+		if err != nil {
+			t.Fatalf("run %d: SelectInstances() error = %v", i, err)
+		}
+		if strings.Join(got, ",") != w {
+			t.Errorf("run %d: SelectInstances() = %v, want %s", i, got, w)
+		}
+	}
+
+	statePath, _ := StatePath()
+	if _, err := os.Stat(statePath); err != nil {
+		t.Errorf("state file was not written: %v", err)
+	}
+
+	// Each list rotates on its own
+	other := writeInstanceList(t)
+	got, err := SelectInstances(other, instances, InstanceSelectRoundRobin)
+	if err != nil || got[0] != "https://a.example" {
+		t.Errorf("a new list should start at its first instance, got %v (%v)", got, err)
+	}
+}
+
+func TestSelectInstancesRandom(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	instances := []string{"https://a.example", "https://b.example", "https://c.example"}
+
+	got, err := SelectInstances("instances.txt", instances, InstanceSelectRandom)
+	if err != nil {
+		t.Fatalf("SelectInstances() error = %v", err)
+	}
+	if len(got) != len(instances) {
+		t.Fatalf("SelectInstances() returned %d instances, want %d", len(got), len(instances))
+	}
+	seen := make(map[string]bool)
+	for _, instance := range got {
+		seen[instance] = true
+	}
+	if len(seen) != len(instances) {
+		t.Errorf("SelectInstances() should return each instance once, got %v", got)
+	}
+
+	if _, err := SelectInstances("instances.txt", instances, "weighted"); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// appDirName is the directory for search under the XDG config and cache
+// homes.
+const appDirName = "search"
+
+// ConfigDir returns the directory holding the config file, bookmarks,
+// templates and state: $XDG_CONFIG_HOME/search, or ~/.config/search when
+// XDG_CONFIG_HOME is unset.
+//
+// When that directory doesn't exist yet but the legacy ~/.search does, the
+// legacy directory is moved there on first use so existing config,
+// bookmarks and templates carry over. If it can't be moved, e.g. because
+// it is on another filesystem, ~/.search keeps being used.
+//
+// Example:
+//
+//	dir, err := config.ConfigDir()
+//	// dir == "/home/user/.config/search"
+func ConfigDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	dir := filepath.Join(xdgHome("XDG_CONFIG_HOME", home, ".config"), appDirName)
+	legacy := filepath.Join(home, legacyConfigDir)
+	if isDir(dir) || !isDir(legacy) {
+		return dir, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err == nil {
+		if err := os.Rename(legacy, dir); err == nil {
+			return dir, nil
+		}
+	}
+	// Another run may have moved it first
+	if isDir(dir) {
+		return dir, nil
+	}
+	return legacy, nil
+}
+
+// CacheDir returns the directory for files that can be recreated, such as
+// instances' category lists: $XDG_CACHE_HOME/search, or ~/.cache/search
+// when XDG_CACHE_HOME is unset. While the legacy ~/.search is in use, see
+// ConfigDir, it holds the cache too.
+func CacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	configDir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	if configDir == filepath.Join(home, legacyConfigDir) {
+		return configDir, nil
+	}
+	return filepath.Join(xdgHome("XDG_CACHE_HOME", home, ".cache"), appDirName), nil
+}
+
+// xdgHome returns the base directory named by the XDG environment
+// variable env, or home/fallback when it is unset. The spec says relative
+// paths are invalid, so they are ignored too.
+func xdgHome(env, home, fallback string) string {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(home, fallback)
+}
+
+// isDir reports whether path is an existing directory.
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestMain keeps the tests, which point HOME at temporary directories,
+// from finding the developer's XDG directories.
+func TestMain(m *testing.M) {
+	os.Unsetenv("XDG_CONFIG_HOME")
+	os.Unsetenv("XDG_CACHE_HOME")
+	os.Exit(m.Run())
+}
+
+func TestConfigAndCacheDirs(t *testing.T) {
+	tests := []struct {
+		name       string
+		configHome string // XDG_CONFIG_HOME relative to HOME, "" for unset
+		cacheHome  string
+		legacy     bool // ~/.search exists
+		wantConfig string
+		wantCache  string
+	}{
+		{"defaults", "", "", false, ".config/search", ".cache/search"},
+		{"XDG config home", "xdg/config", "", false, "xdg/config/search", ".cache/search"},
+		{"XDG cache home", "", "xdg/cache", false, ".config/search", "xdg/cache/search"},
+		{"both XDG homes", "xdg/config", "xdg/cache", false, "xdg/config/search", "xdg/cache/search"},
+		{"relative XDG home ignored", "relative", "", false, ".config/search", ".cache/search"},
+		{"legacy moved", "", "", true, ".config/search", ".cache/search"},
+		{"legacy moved to XDG config home", "xdg/config", "xdg/cache", true, "xdg/config/search", "xdg/cache/search"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			for env, dir := range map[string]string{"XDG_CONFIG_HOME": tt.configHome, "XDG_CACHE_HOME": tt.cacheHome} {
+				switch {
+				case dir == "relative":
+					t.Setenv(env, dir)
+				case dir != "":
+					t.Setenv(env, filepath.Join(home, dir))
+				}
+			}
+			legacyFile := filepath.Join(home, ".search", "config.yaml")
+			if tt.legacy {
+				if err := os.MkdirAll(filepath.Dir(legacyFile), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(legacyFile, []byte("results: 42\n"), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			configDir, err := ConfigDir()
+			if err != nil {
+				t.Fatalf("ConfigDir() error = %v", err)
+			}
+			if want := filepath.Join(home, tt.wantConfig); configDir != want {
+				t.Errorf("ConfigDir() = %q, want %q", configDir, want)
+			}
+			cacheDir, err := CacheDir()
+			if err != nil {
+				t.Fatalf("CacheDir() error = %v", err)
+			}
+			if want := filepath.Join(home, tt.wantCache); cacheDir != want {
+				t.Errorf("CacheDir() = %q, want %q", cacheDir, want)
+			}
+
+			if !tt.legacy {
+				return
+			}
+			if _, err := os.Stat(legacyFile); !os.IsNotExist(err) {
+				t.Errorf("legacy config was not moved: %v", err)
+			}
+			cfg, err := LoadConfig(&CliConfig{SafeSearch: -1})
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if cfg.Results != 42 {
+				t.Errorf("results = %d, want 42 from the migrated config", cfg.Results)
+			}
+		})
+	}
+}
+
+func TestConfigDirKeepsLegacyWhenNotMovable(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	legacy := filepath.Join(home, ".search")
+	if err := os.MkdirAll(legacy, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// A file where the XDG config home should be stops the move
+	blocker := filepath.Join(home, "blocker")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("XDG_CONFIG_HOME", blocker)
+
+	if dir, err := ConfigDir(); err != nil || dir != legacy {
+		t.Errorf("ConfigDir() = %q, %v; want %q", dir, err, legacy)
+	}
+	if dir, err := CacheDir(); err != nil || dir != legacy {
+		t.Errorf("CacheDir() = %q, %v; want %q", dir, err, legacy)
+	}
+}
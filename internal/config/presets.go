@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Preset is a named search shape from the config file, selected with
+// --preset. Empty fields leave the setting alone.
+//
+// Example config:
+//
+//	presets:
+//	  news-de:
+//	    category: news
+//	    language: de
+//	    time: day
+type Preset struct {
+	Category   string `yaml:"category,omitempty" mapstructure:"category"`
+	Language   string `yaml:"language,omitempty" mapstructure:"language"`
+	TimeRange  string `yaml:"time,omitempty" mapstructure:"time"`
+	Results    int    `yaml:"results,omitempty" mapstructure:"results"`
+	Format     string `yaml:"format,omitempty" mapstructure:"format"`
+	SafeSearch *int   `yaml:"safe_search,omitempty" mapstructure:"safe_search"` // nil leaves it alone, as 0 is a level
+}
+
+// PresetNames returns the sorted names of the configured presets.
+func (c *Config) PresetNames() []string {
+	names := make([]string, 0, len(c.Presets))
+	for name := range c.Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyPreset applies the named preset's settings to the config. An
+// unknown name is an error listing the configured presets.
+func (c *Config) ApplyPreset(name string) error {
+	preset, ok := c.Presets[name]
+	if !ok {
+		if len(c.Presets) == 0 {
+			return fmt.Errorf("unknown preset %q: no presets are configured", name)
+		}
+		return fmt.Errorf("unknown preset %q, available presets: %s", name, strings.Join(c.PresetNames(), ", "))
+	}
+
+	if preset.Category != "" {
+		c.Categories = []string{preset.Category}
+	}
+	if preset.Language != "" {
+		c.Language = preset.Language
+	}
+	if preset.TimeRange != "" {
+		c.TimeRange = preset.TimeRange
+	}
+	if preset.Results > 0 {
+		c.Results = preset.Results
+	}
+	if preset.Format != "" {
+		c.Format = preset.Format
+	}
+	if preset.SafeSearch != nil {
+		c.SafeSearch = *preset.SafeSearch
+	}
+	return nil
+}
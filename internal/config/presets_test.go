@@ -0,0 +1,94 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfigPresetPrecedence(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `language: en
+results: 15
+format: json
+presets:
+  news-de:
+    category: news
+    language: de
+    time: day
+    safe_search: 0
+  code:
+    category: it
+`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		cli          CliConfig
+		env          map[string]string
+		wantCategory string
+		wantLanguage string
+		wantTime     string
+		wantSafe     int
+		wantFormat   string
+	}{
+		{"no preset", CliConfig{}, nil, "general", "en", "", 1, "json"},
+		{"preset over config", CliConfig{Preset: "news-de"}, nil, "news", "de", "day", 0, "json"},
+		{"preset over environment", CliConfig{Preset: "news-de"}, map[string]string{"SEARCH_LANGUAGE": "fr"}, "news", "de", "day", 0, "json"},
+		{"flags over preset", CliConfig{Preset: "news-de", Language: "nl", TimeRange: "week", Category: "science", SafeSearch: 2}, nil, "science", "nl", "week", 2, "json"},
+		{"unset preset fields keep config", CliConfig{Preset: "code"}, nil, "it", "en", "", 1, "json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			cli := tt.cli
+			cli.ConfigPath = path
+			if cli.SafeSearch == 0 {
+				cli.SafeSearch = -1
+			}
+			cfg, err := LoadConfig(&cli)
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if !reflect.DeepEqual(cfg.Categories, []string{tt.wantCategory}) {
+				t.Errorf("categories = %v, want [%s]", cfg.Categories, tt.wantCategory)
+			}
+			if cfg.Language != tt.wantLanguage {
+				t.Errorf("language = %q, want %q", cfg.Language, tt.wantLanguage)
+			}
+			if cfg.TimeRange != tt.wantTime {
+				t.Errorf("time range = %q, want %q", cfg.TimeRange, tt.wantTime)
+			}
+			if cfg.SafeSearch != tt.wantSafe {
+				t.Errorf("safe search = %d, want %d", cfg.SafeSearch, tt.wantSafe)
+			}
+			if cfg.Format != tt.wantFormat || cfg.Results != 15 {
+				t.Errorf("format, results = %q, %d; want %q, 15", cfg.Format, cfg.Results, tt.wantFormat)
+			}
+		})
+	}
+}
+
+func TestApplyPresetUnknown(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := cfg.ApplyPreset("news"); err == nil || !strings.Contains(err.Error(), "no presets are configured") {
+		t.Errorf("ApplyPreset() error = %v, want no presets configured", err)
+	}
+
+	cfg.Presets = map[string]Preset{"news-de": {Category: "news"}, "code": {Category: "it"}}
+	err := cfg.ApplyPreset("news")
+	if err == nil || !strings.Contains(err.Error(), "available presets: code, news-de") {
+		t.Errorf("ApplyPreset() error = %v, want the available presets listed", err)
+	}
+	if cfg.Categories[0] != "general" {
+		t.Errorf("categories = %v, want them unchanged", cfg.Categories)
+	}
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	templatesDirName  = "templates"
+	templateExtension = ".tmpl"
+)
+
+// TemplatesDir returns the directory holding named output templates
+// (~/.config/search/templates).
+func TemplatesDir() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, templatesDirName), nil
+}
+
+// ResolveTemplate resolves a --template argument to a file path.
+//
+// A bare name like "news" resolves to news.tmpl in TemplatesDir when
+// that file exists. Anything else is treated as a literal path, with
+// "~/" and environment variables expanded.
+//
+// Example:
+//
+//	path, err := config.ResolveTemplate("news")
+//	// path == "/home/user/.config/search/templates/news.tmpl"
+func ResolveTemplate(name string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("template name cannot be empty")
+	}
+
+	if !strings.ContainsRune(name, filepath.Separator) && !strings.ContainsRune(name, '/') {
+		if dir, err := TemplatesDir(); err == nil {
+			path := filepath.Join(dir, strings.TrimSuffix(name, templateExtension)+templateExtension)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path, nil
+			}
+		}
+	}
+
+	return ExpandPath(name)
+}
+
+// ListTemplates returns the sorted names of the templates in TemplatesDir,
+// without the .tmpl extension.
+//
+// A missing templates directory is not an error; it yields an empty list.
+func ListTemplates() ([]string, error) {
+	dir, err := TemplatesDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("failed to read templates directory: %w", err)
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != templateExtension {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(entry.Name(), templateExtension))
+	}
+	sort.Strings(names)
+	return names, nil
+}
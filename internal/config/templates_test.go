@@ -0,0 +1,80 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestResolveTemplate(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	dir := filepath.Join(home, ".config", "search", "templates")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	named := filepath.Join(dir, "news.tmpl")
+	if err := os.WriteFile(named, []byte("{{.Query}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		arg  string
+		want string
+	}{
+		{"named template", "news", named},
+		{"named template with extension", "news.tmpl", named},
+		{"unknown name falls back to literal", "missing", "missing"},
+		{"path is used as-is", "./layouts/news.tmpl", "./layouts/news.tmpl"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveTemplate(tt.arg)
+			if err != nil {
+				t.Fatalf("ResolveTemplate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveTemplate(%q) = %q, want %q", tt.arg, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ResolveTemplate(""); err == nil {
+		t.Error("ResolveTemplate(\"\") expected error")
+	}
+}
+
+func TestListTemplates(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	names, err := ListTemplates()
+	if err != nil {
+		t.Fatalf("ListTemplates() with no directory error = %v", err)
+	}
+	if len(names) != 0 {
+		t.Errorf("ListTemplates() = %v, want empty", names)
+	}
+
+	dir := filepath.Join(home, ".config", "search", "templates")
+	if err := os.MkdirAll(filepath.Join(dir, "subdir.tmpl"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []string{"news.tmpl", "brief.tmpl", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, f), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names, err = ListTemplates()
+	if err != nil {
+		t.Fatalf("ListTemplates() error = %v", err)
+	}
+	if want := []string{"brief", "news"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("ListTemplates() = %v, want %v", names, want)
+	}
+}
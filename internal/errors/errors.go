@@ -5,6 +5,7 @@
 package errors
 
 import (
+	stderrors "errors"
 	"fmt"
 	"io"
 	"net"
@@ -12,6 +13,7 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"time"
 )
 
 // ErrorCode represents a unique error code for scripting.
@@ -30,11 +32,17 @@ const (
 	ErrCodeNetworkUnreachable ErrorCode = "NETWORK_UNREACHABLE"
 	ErrCodeConnectionRefused ErrorCode = "CONNECTION_REFUSED"
 	ErrCodeDNSFailed         ErrorCode = "DNS_FAILED"
+	ErrCodeCanceled          ErrorCode = "CANCELED"
 
 	// API errors
 	ErrCodeAPIError          ErrorCode = "API_ERROR"
 	ErrCodeAPIUnavailable    ErrorCode = "API_UNAVAILABLE"
 	ErrCodeInvalidResponse   ErrorCode = "INVALID_RESPONSE"
+	ErrCodeResponseTooLarge  ErrorCode = "RESPONSE_TOO_LARGE"
+	ErrCodeCircuitOpen       ErrorCode = "CIRCUIT_OPEN"
+	ErrCodeTooManyRedirects  ErrorCode = "TOO_MANY_REDIRECTS"
+	ErrCodeAntiBotChallenge  ErrorCode = "ANTI_BOT_CHALLENGE"
+	ErrCodeAutocompleteUnsupported ErrorCode = "AUTOCOMPLETE_UNSUPPORTED"
 
 	// Input errors
 	ErrCodeEmptyQuery        ErrorCode = "EMPTY_QUERY"
@@ -140,6 +148,16 @@ func ConfigParseError(line int, err error) *SearchError {
 	}
 }
 
+// Canceled creates an error for a search aborted by its context,
+// e.g. when the user presses Ctrl-C.
+func Canceled(err error) *SearchError {
+	return &SearchError{
+		Code:    ErrCodeCanceled,
+		Message: "Search was canceled",
+		Err:     err,
+	}
+}
+
 // Network errors
 func NetworkError(err error) *SearchError {
 	code := ErrCodeNetworkUnreachable
@@ -203,6 +221,65 @@ func InvalidResponse(err error) *SearchError {
 	}
 }
 
+// UnexpectedFormat creates an error for a response in a different format
+// than the one requested, such as an HTML page instead of JSON.
+func UnexpectedFormat(requested, got, contentType string) *SearchError {
+	return &SearchError{
+		Code:       ErrCodeInvalidResponse,
+		Message:    fmt.Sprintf("SearXNG instance returned %s (%s) instead of %s", got, contentType, requested),
+		Suggestion: fmt.Sprintf("The instance may have the %s format disabled (search.formats in settings.yml) or sit behind a login or captcha page. Try a different instance", requested),
+	}
+}
+
+// ResponseTooLarge creates an error for responses exceeding the size limit.
+func ResponseTooLarge(limit int64) *SearchError {
+	return &SearchError{
+		Code:       ErrCodeResponseTooLarge,
+		Message:    fmt.Sprintf("Response from SearXNG instance exceeded %d bytes", limit),
+		Suggestion: "The instance may be broken or malicious. Raise --max-response-size or try a different instance",
+	}
+}
+
+// TooManyRedirects creates an error for a request stopped after limit
+// redirects, at the redirect to location.
+func TooManyRedirects(limit int, location string) *SearchError {
+	return &SearchError{
+		Code:       ErrCodeTooManyRedirects,
+		Message:    fmt.Sprintf("SearXNG instance redirected more than %d times (next: %s)", limit, location),
+		Suggestion: "Use the URL the instance redirects to with --instance, or raise --max-redirects if the redirects are expected",
+	}
+}
+
+// AntiBotChallenge creates an error for an instance that answered with a
+// Cloudflare or similar anti-bot challenge page instead of results.
+func AntiBotChallenge(statusCode int) *SearchError {
+	return &SearchError{
+		Code:       ErrCodeAntiBotChallenge,
+		Message:    fmt.Sprintf("SearXNG instance is behind an anti-bot challenge (%d)", statusCode),
+		Suggestion: "The instance only serves browsers that pass its challenge page. Use a different instance, or ask its operator to allow API access",
+	}
+}
+
+// CircuitOpen creates an error for a request skipped because the instance
+// has failed repeatedly.
+func CircuitOpen(instance string, retryAt time.Time) *SearchError {
+	return &SearchError{
+		Code:       ErrCodeCircuitOpen,
+		Message:    fmt.Sprintf("Skipping %s after repeated failures", instance),
+		Suggestion: fmt.Sprintf("The instance will be retried after %s. Use a different instance with --instance", retryAt.Format("15:04:05")),
+	}
+}
+
+// AutocompleteUnsupported creates an error for an instance that has no
+// autocomplete endpoint.
+func AutocompleteUnsupported(instance string) *SearchError {
+	return &SearchError{
+		Code:       ErrCodeAutocompleteUnsupported,
+		Message:    fmt.Sprintf("%s does not support autocomplete", instance),
+		Suggestion: "Use a different instance with --instance",
+	}
+}
+
 func APIError(message string) *SearchError {
 	return &SearchError{
 		Code:       ErrCodeAPIError,
@@ -225,7 +302,7 @@ func InvalidFormat(format string) *SearchError {
 	return &SearchError{
 		Code:       ErrCodeInvalidFormat,
 		Message:    fmt.Sprintf("Invalid output format: %s", format),
-		Suggestion: "Valid formats are: json, markdown, text",
+		Suggestion: "Valid formats are: json, ndjson, markdown, text, table",
 	}
 }
 
@@ -254,6 +331,15 @@ func NoResults(query string) *SearchError {
 	}
 }
 
+// EmptyResults creates an error for a search that succeeded but returned
+// no results, used when the caller asked to treat that as a failure.
+func EmptyResults(query string) *SearchError {
+	return &SearchError{
+		Code:    ErrCodeEmptyResults,
+		Message: fmt.Sprintf("Search returned no results: %s", query),
+	}
+}
+
 // IsSearchError checks if an error is a SearchError
 func IsSearchError(err error) (*SearchError, bool) {
 	if err == nil {
@@ -273,6 +359,81 @@ func GetErrorCode(err error) ErrorCode {
 	return ""
 }
 
+// Process exit codes, so scripts can tell transient failures from
+// permanent ones.
+const (
+	ExitOK           = 0
+	ExitError        = 1   // Unclassified error
+	ExitNoResults    = 2   // No results with --fail-on-empty
+	ExitNetwork      = 3   // Network failure; retrying may help
+	ExitInvalidInput = 4   // Bad flags, query or configuration
+	ExitInstance     = 5   // The instance returned an error or bad response
+	ExitCanceled     = 130 // Interrupted, as for SIGINT
+)
+
+// exitCodes maps error codes to process exit codes.
+var exitCodes = map[ErrorCode]int{
+	ErrCodeConfigNotFound:     ExitInvalidInput,
+	ErrCodeConfigInvalid:      ExitInvalidInput,
+	ErrCodeConfigParseError:   ExitInvalidInput,
+	ErrCodeNetworkTimeout:     ExitNetwork,
+	ErrCodeNetworkUnreachable: ExitNetwork,
+	ErrCodeConnectionRefused:  ExitNetwork,
+	ErrCodeDNSFailed:          ExitNetwork,
+	ErrCodeCircuitOpen:        ExitNetwork,
+	ErrCodeCanceled:           ExitCanceled,
+	ErrCodeAPIError:           ExitInstance,
+	ErrCodeAPIUnavailable:     ExitInstance,
+	ErrCodeInvalidResponse:    ExitInstance,
+	ErrCodeResponseTooLarge:   ExitInstance,
+	ErrCodeTooManyRedirects:   ExitInstance,
+	ErrCodeAntiBotChallenge:   ExitInstance,
+	ErrCodeAutocompleteUnsupported: ExitInstance,
+	ErrCodeEmptyQuery:         ExitInvalidInput,
+	ErrCodeInvalidFormat:      ExitInvalidInput,
+	ErrCodeInvalidURL:         ExitInvalidInput,
+	ErrCodeInvalidRange:       ExitInvalidInput,
+	ErrCodeNoResults:          ExitNoResults,
+	ErrCodeEmptyResults:       ExitNoResults,
+}
+
+// ExitCoder is implemented by errors from other packages that carry
+// their own exit code, such as validation errors.
+type ExitCoder interface {
+	ExitCode() int
+}
+
+// ExitCode returns the process exit code for err, looking through wrapped
+// errors for a SearchError or ExitCoder.
+//
+// It returns ExitOK for nil and ExitError for unrecognized errors.
+//
+// Example:
+//
+//	if err := cli.Execute(); err != nil {
+//	    os.Exit(errors.ExitCode(err))
+//	}
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var searchErr *SearchError
+	if stderrors.As(err, &searchErr) {
+		if code, ok := exitCodes[searchErr.Code]; ok {
+			return code
+		}
+		return ExitError
+	}
+
+	var coder ExitCoder
+	if stderrors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+
+	return ExitError
+}
+
 // HandleError prints error to stderr in a user-friendly format
 //
 // Example:
@@ -2,8 +2,10 @@ package errors
 
 import (
 	"errors"
+	"fmt"
 	"net"
 	"testing"
+	"time"
 )
 
 func TestSearchError_Error(t *testing.T) {
@@ -361,6 +363,53 @@ func TestGetErrorCode(t *testing.T) {
 	}
 }
 
+type testExitCoder struct{}
+
+func (testExitCoder) Error() string { return "custom" }
+func (testExitCoder) ExitCode() int { return 42 }
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil error", nil, ExitOK},
+		{"standard error", errors.New("standard error"), ExitError},
+		{"config not found", ConfigNotFound("/tmp/x.yaml"), ExitInvalidInput},
+		{"config invalid", ConfigInvalid(errors.New("bad")), ExitInvalidInput},
+		{"config parse error", ConfigParseError(3, errors.New("bad")), ExitInvalidInput},
+		{"network error", NetworkError(errors.New("unreachable")), ExitNetwork},
+		{"connection refused", NetworkError(errors.New("connection refused")), ExitNetwork},
+		{"dns failure", NetworkError(errors.New("no such host")), ExitNetwork},
+		{"circuit open", CircuitOpen("https://a.example", time.Now()), ExitNetwork},
+		{"canceled", Canceled(errors.New("context canceled")), ExitCanceled},
+		{"http 500", HTTPStatusError(500, "500 Internal Server Error"), ExitInstance},
+		{"http 404", HTTPStatusError(404, "404 Not Found"), ExitInstance},
+		{"invalid response", InvalidResponse(errors.New("bad json")), ExitInstance},
+		{"response too large", ResponseTooLarge(10), ExitInstance},
+		{"api error", APIError("boom"), ExitInstance},
+		{"empty query", EmptyQuery(), ExitInvalidInput},
+		{"invalid format", InvalidFormat("xml"), ExitInvalidInput},
+		{"invalid url", InvalidURL("ftp://x"), ExitInvalidInput},
+		{"invalid range", InvalidRange("results", 1, 100, 0), ExitInvalidInput},
+		{"no results", NoResults("q"), ExitNoResults},
+		{"empty results", EmptyResults("q"), ExitNoResults},
+		{"unknown code", New("SOMETHING_ELSE", "odd"), ExitError},
+		{"wrapped search error", fmt.Errorf("search failed: %w", EmptyQuery()), ExitInvalidInput},
+		{"exit coder", testExitCoder{}, 42},
+		{"wrapped exit coder", fmt.Errorf("wrapped: %w", testExitCoder{}), 42},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestHTTPErrorType(t *testing.T) {
 	err := &HTTPResponseError{
 		StatusCode: 404,
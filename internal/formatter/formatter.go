@@ -7,6 +7,7 @@ package formatter
 import (
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 
 	"github.com/mule-ai/search/internal/searxng"
@@ -20,21 +21,138 @@ type Formatter interface {
 	Format(result *searxng.SearchResponse) (string, error)
 }
 
+// StreamFormatter is implemented by formatters that can write output
+// incrementally instead of building the whole string first.
+//
+// Only the formatted output is streamed: the response is already decoded
+// in full. Callers should still prefer StreamFormat when a formatter
+// supports it, as the formatted output is never held in memory.
+type StreamFormatter interface {
+	Formatter
+	StreamFormat(w io.Writer, result *searxng.SearchResponse) error
+}
+
+// Compile-time checks that the concrete formatters satisfy the interfaces.
+var (
+	_ Formatter       = (*JSONFormatter)(nil)
+	_ Formatter       = (*MarkdownFormatter)(nil)
+	_ Formatter       = (*ImageFormatter)(nil)
+	_ Formatter       = (*OptimizedFormatter)(nil)
+	_ Formatter       = (*imageMarkdownFormatter)(nil)
+	_ Formatter       = (*TableFormatter)(nil)
+	_ StreamFormatter = (*TextFormatter)(nil)
+	_ StreamFormatter = (*NDJSONFormatter)(nil)
+	_ StreamFormatter = (*TemplateFormatter)(nil)
+)
+
+// WriteTo formats resp with f and writes the output to w.
+//
+// Formatters implementing StreamFormatter write directly to w; all others
+// are formatted to a string first. This lets library consumers send output
+// to any sink without caring which kind of formatter they hold.
+//
+// Example:
+//
+//	f, _ := formatter.NewFormatter("markdown")
+//	if err := formatter.WriteTo(file, f, response); err != nil {
+//	    log.Fatal(err)
+//	}
+func WriteTo(w io.Writer, f Formatter, resp *searxng.SearchResponse) error {
+	if sf, ok := f.(StreamFormatter); ok {
+		return sf.StreamFormat(w, resp)
+	}
+
+	output, err := f.Format(resp)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, output)
+	return err
+}
+
+// flush writes the contents of buf to w and resets buf for reuse.
+func flush(w io.Writer, buf *strings.Builder) error {
+	_, err := io.WriteString(w, buf.String())
+	buf.Reset()
+	return err
+}
+
 // BaseFormatter contains common formatting functionality.
 //
 // It provides text wrapping, truncation, and utility methods used by
 // specific formatter implementations.
 type BaseFormatter struct {
 	Width int
+	// MaxLines limits wrapped content to this many lines; 0 means unlimited
+	MaxLines int
+	// GroupByCategory renders results under a heading per category
+	GroupByCategory bool
+	// ThousandsSep groups the digits of result counts; empty means no grouping
+	ThousandsSep string
+	// StartIndex is added to result numbers, so later pages continue the
+	// numbering of earlier ones; 0 numbers from 1
+	StartIndex int
+}
+
+// resultSection is a run of results rendered under one heading.
+type resultSection struct {
+	Heading string
+	Results []searxng.SearchResult
+}
+
+// resultSections splits results into sections for rendering. Without
+// GroupByCategory there is a single section with no heading; otherwise
+// there is one section per category in searxng.SortedCategories order.
+func (f *BaseFormatter) resultSections(results []searxng.SearchResult) []resultSection {
+	if !f.GroupByCategory {
+		return []resultSection{{Results: results}}
+	}
+
+	groups := searxng.GroupByCategory(results)
+	sections := make([]resultSection, 0, len(groups))
+	for _, name := range searxng.SortedCategories(groups) {
+		sections = append(sections, resultSection{
+			Heading: searxng.CategoryDisplayName(name),
+			Results: groups[name],
+		})
+	}
+	return sections
 }
 
 // NewBaseFormatter creates a new base formatter with default width.
 //
-// The default width is 80 characters, suitable for most terminal displays.
+// The default width is 80 characters, suitable for most terminal displays,
+// and result counts are grouped with commas.
 func NewBaseFormatter() *BaseFormatter {
 	return &BaseFormatter{
-		Width: 80,
+		Width:        80,
+		ThousandsSep: ",",
+	}
+}
+
+// formatInt formats n with its digits grouped in threes by ThousandsSep.
+func (f *BaseFormatter) formatInt(n int) string {
+	s := strconv.Itoa(n)
+	if f.ThousandsSep == "" {
+		return s
+	}
+
+	sign := ""
+	if n < 0 {
+		sign, s = "-", s[1:]
+	}
+	var b strings.Builder
+	b.WriteString(sign)
+	head := len(s) % 3
+	if head == 0 {
+		head = 3
 	}
+	b.WriteString(s[:head])
+	for i := head; i < len(s); i += 3 {
+		b.WriteString(f.ThousandsSep)
+		b.WriteString(s[i : i+3])
+	}
+	return b.String()
 }
 
 // Truncate truncates a string to the specified length.
@@ -64,6 +182,19 @@ func (f *BaseFormatter) TruncateWithEllipsis(s string, length int) string {
 	return s[:length-3] + "..."
 }
 
+// LimitLines truncates lines to MaxLines, appending an ellipsis line
+// when anything was dropped.
+//
+// If MaxLines is 0 or negative, lines are returned unchanged.
+func (f *BaseFormatter) LimitLines(lines []string) []string {
+	if f.MaxLines <= 0 || len(lines) <= f.MaxLines {
+		return lines
+	}
+	limited := make([]string, f.MaxLines, f.MaxLines+1)
+	copy(limited, lines)
+	return append(limited, "...")
+}
+
 // MaxWidth wraps text to the specified width.
 //
 // It splits text into multiple lines, ensuring no line exceeds the width.
@@ -100,7 +231,7 @@ func (f *BaseFormatter) MaxWidth(s string, width int) []string {
 
 // NewFormatter creates a formatter based on the format string.
 //
-// Supported formats: "json", "markdown" (or "md"), "text" (or "plaintext").
+// Supported formats: "json", "ndjson", "jsonl-pretty", "json-lines-with-meta", "markdown" (or "md"), "text" (or "plaintext"), "table".
 // Returns an error if the format is not recognized.
 //
 // Example:
@@ -129,26 +260,164 @@ func NewFormatter(format string) (Formatter, error) {
 //	}
 //	output, err := f.Format(response)
 func NewFormatterForCategory(format string, category string, noColor bool) (Formatter, error) {
+	return NewFormatterWithOptions(format, category, Options{NoColor: noColor})
+}
+
+// Options holds optional settings applied to the formatters that support them.
+//
+// The zero value gives the default output for every format.
+type Options struct {
+	// NoColor disables colored output for text formatters.
+	NoColor bool
+	// FieldSeparator switches text output to one line per result with
+	// fields joined by this separator. Empty means the normal layout.
+	FieldSeparator string
+	// Compact disables pretty printing for JSON output. NDJSON is
+	// always compact.
+	Compact bool
+	// ContentMaxLines limits each result's wrapped content in text
+	// output. 0 means unlimited.
+	ContentMaxLines int
+	// GroupByCategory groups results under category headings in text
+	// and markdown output, and nests them under category keys in JSON.
+	GroupByCategory bool
+	// NoMetadata strips the query and metadata wrapper from JSON output,
+	// leaving a bare results array, and drops the markdown header and
+	// result count.
+	NoMetadata bool
+	// Raw emits every field the instance sent for each result in JSON
+	// output instead of the curated subset.
+	Raw bool
+	// Locale picks the thousands separator for result counts in text and
+	// markdown output, e.g. "de_DE.UTF-8". Empty means commas.
+	Locale string
+	// PrettyURLs shows shortened URLs in text and table output and lists
+	// the full URLs after the results.
+	PrettyURLs bool
+	// StartIndex offsets result numbers in text and table output, e.g. 10
+	// to number page 2 of 10-result pages from [11].
+	StartIndex int
+	// Explain adds a line per result in text output with its raw score,
+	// the engines that returned it and its category.
+	Explain bool
+	// Summary puts answers and infoboxes first in text and markdown
+	// output, followed by only the top few results. Responses without
+	// answers or infoboxes are shown as usual.
+	Summary bool
+	// NoWrap leaves result content in text output unwrapped and
+	// untruncated, one output line per line of content.
+	NoWrap bool
+	// EngineStats adds an engine_stats array to JSON output with each
+	// engine's result count and average score.
+	EngineStats bool
+	// Width is the line width text and markdown output wraps and
+	// truncates to. 0 means the default of 80 columns.
+	Width int
+}
+
+// summaryResults is how many results follow the answers and infoboxes in
+// summary mode.
+const summaryResults = 3
+
+// hasSummary reports whether result has answers or infoboxes to lead a
+// summary with.
+func hasSummary(result *searxng.SearchResponse) bool {
+	return len(result.Answers) > 0 || len(result.Infoboxes) > 0
+}
+
+// NewFormatterWithOptions creates a formatter based on format and category,
+// applying opts to formatters that support them.
+//
+// Options that don't apply to the chosen format are ignored. Image results
+// in text and markdown get their own layout, which honors StartIndex,
+// Width, NoWrap and Explain in text. An option only the regular layout
+// has, such as FieldSeparator, Summary or GroupByCategory, selects the
+// regular formatter instead, so it is never silently dropped.
+//
+// Example:
+//
+//	f, err := formatter.NewFormatterWithOptions("text", "general", formatter.Options{
+//	    FieldSeparator: "\t",
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	output, err := f.Format(response)
+func NewFormatterWithOptions(format string, category string, opts Options) (Formatter, error) {
 	// Check if category needs special formatting
 	if searxng.NeedsSpecialFormatting(category) {
-		// For image category with markdown, use special image markdown formatter
-		if format == "markdown" || format == "md" {
-			return &imageMarkdownFormatter{}, nil
-		}
-		// For image category with text, use image formatter
-		if format == "text" || format == "plaintext" {
-			return NewImageFormatter(), nil
+		switch strings.ToLower(format) {
+		case "markdown", "md":
+			// For image category with markdown, use special image markdown formatter
+			if !opts.NoMetadata && !opts.GroupByCategory && !opts.Summary {
+				return &imageMarkdownFormatter{}, nil
+			}
+		case "text", "plaintext":
+			// For image category with text, use image formatter
+			if opts.FieldSeparator == "" && opts.ContentMaxLines <= 0 && !opts.GroupByCategory &&
+				!opts.PrettyURLs && !opts.Summary {
+				imf := NewImageFormatter()
+				imf.StartIndex = opts.StartIndex
+				imf.Width = opts.Width
+				imf.NoWrap = opts.NoWrap
+				imf.Explain = opts.Explain
+				return imf, nil
+			}
 		}
 	}
 
 	// Default formatting
 	switch strings.ToLower(format) {
 	case "json":
-		return NewJSONFormatter(), nil
+		jf := NewJSONFormatter()
+		if opts.Compact {
+			jf.DisablePretty()
+		}
+		jf.ResultsOnly = opts.NoMetadata
+		jf.GroupByCategory = opts.GroupByCategory
+		jf.Raw = opts.Raw
+		jf.EngineStats = opts.EngineStats
+		return jf, nil
+	case "ndjson":
+		return NewNDJSONFormatter(), nil
+	case "jsonl-pretty":
+		return &NDJSONFormatter{Pretty: true}, nil
+	case "json-lines-with-meta":
+		return &NDJSONFormatter{Meta: true}, nil
 	case "markdown", "md":
-		return NewMarkdownFormatter(), nil
+		mf := NewMarkdownFormatter()
+		mf.NoMetadata = opts.NoMetadata
+		mf.GroupByCategory = opts.GroupByCategory
+		mf.ThousandsSep = ThousandsSeparator(opts.Locale)
+		mf.Summary = opts.Summary
+		if opts.Width > 0 {
+			mf.Width = opts.Width
+		}
+		return mf, nil
 	case "text", "plaintext":
-		return NewTextFormatter(noColor), nil
+		tf := NewTextFormatter(opts.NoColor)
+		tf.FieldSeparator = opts.FieldSeparator
+		tf.MaxLines = opts.ContentMaxLines
+		tf.GroupByCategory = opts.GroupByCategory
+		tf.ThousandsSep = ThousandsSeparator(opts.Locale)
+		tf.PrettyURLs = opts.PrettyURLs
+		tf.StartIndex = opts.StartIndex
+		tf.Explain = opts.Explain
+		tf.Summary = opts.Summary
+		tf.NoWrap = opts.NoWrap
+		if opts.Width > 0 {
+			tf.Width = opts.Width
+		}
+		return tf, nil
+	case "html":
+		hf := NewHTMLFormatter()
+		hf.ThousandsSep = ThousandsSeparator(opts.Locale)
+		return hf, nil
+	case "table":
+		tf := NewTableFormatter(opts.NoColor)
+		tf.PrettyURLs = opts.PrettyURLs
+		tf.StartIndex = opts.StartIndex
+		return tf, nil
 	default:
 		return nil, fmt.Errorf("unknown format: %s", format)
 	}
@@ -173,7 +442,7 @@ func (f *BaseFormatter) FormatResults(results []searxng.SearchResult, width int)
 			lines = append(lines, fmt.Sprintf("    %s", result.URL))
 		}
 		if len(result.Content) > 0 {
-			wrapped := f.MaxWidth(f.TruncateWithEllipsis(result.Content, width-8), width-8)
+			wrapped := f.LimitLines(f.MaxWidth(f.TruncateWithEllipsis(result.Content, width-8), width-8))
 			for _, line := range wrapped {
 				lines = append(lines, fmt.Sprintf("    %s", line))
 			}
@@ -203,7 +472,7 @@ func (f *BaseFormatter) FormatResultWithSource(result searxng.SearchResult, inde
 		buf.WriteString("\n")
 	}
 	if len(result.Content) > 0 {
-		wrapped := f.MaxWidth(f.TruncateWithEllipsis(result.Content, width-8), width-8)
+		wrapped := f.LimitLines(f.MaxWidth(f.TruncateWithEllipsis(result.Content, width-8), width-8))
 		for _, line := range wrapped {
 			buf.WriteString(fmt.Sprintf("    %s\n", line))
 		}
@@ -1,7 +1,12 @@
 package formatter
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/mule-ai/search/internal/searxng"
 )
@@ -489,3 +494,906 @@ func TestNewFormatterForCategory(t *testing.T) {
 		})
 	}
 }
+
+// writeCounter records how many separate writes it receives.
+type writeCounter struct {
+	buf    bytes.Buffer
+	writes int
+}
+
+func (w *writeCounter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.buf.Write(p)
+}
+
+func (w *writeCounter) String() string {
+	return w.buf.String()
+}
+
+func TestTextFormatterStreamFormat(t *testing.T) {
+	response := &searxng.SearchResponse{
+		Query: "golang",
+		Results: []searxng.SearchResult{
+			{Title: "First", URL: "https://example.com/1", Engine: "google", Content: "first snippet"},
+			{Title: "Second", URL: "https://example.com/2", Engine: "bing", Content: "second snippet"},
+			{Title: "Third", URL: "https://example.com/3", Engine: "ddg"},
+		},
+		Suggestions: []string{"golang tutorial"},
+	}
+
+	f := NewTextFormatter(true)
+	want, err := f.Format(response)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	var w writeCounter
+	if err := f.StreamFormat(&w, response); err != nil {
+		t.Fatalf("StreamFormat() error = %v", err)
+	}
+
+	if w.String() != want {
+		t.Errorf("StreamFormat() output differs from Format()\ngot:\n%s\nwant:\n%s", w.String(), want)
+	}
+	// Header, one write per result, and the trailing sections
+	if w.writes < len(response.Results)+2 {
+		t.Errorf("StreamFormat() made %d writes, want at least %d", w.writes, len(response.Results)+2)
+	}
+
+	if err := f.StreamFormat(&w, nil); err == nil {
+		t.Error("StreamFormat(nil) expected error")
+	}
+}
+
+func TestFormatterInterfaceCompliance(t *testing.T) {
+	formatters := map[string]Formatter{
+		"json":           NewJSONFormatter(),
+		"ndjson":         NewNDJSONFormatter(),
+		"markdown":       NewMarkdownFormatter(),
+		"text":           NewTextFormatter(true),
+		"image":          NewImageFormatter(),
+		"optimized":      NewOptimizedFormatter("json"),
+		"image-markdown": &imageMarkdownFormatter{},
+		"table":          NewTableFormatter(true),
+	}
+
+	response := &searxng.SearchResponse{
+		Query:   "golang",
+		Results: []searxng.SearchResult{{Title: "Go", URL: "https://go.dev", Engine: "google"}},
+	}
+
+	for name, f := range formatters {
+		t.Run(name, func(t *testing.T) {
+			want, err := f.Format(response)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+
+			var buf bytes.Buffer
+			if err := WriteTo(&buf, f, response); err != nil {
+				t.Fatalf("WriteTo() error = %v", err)
+			}
+			if buf.String() != want {
+				t.Errorf("WriteTo() wrote %q, want %q", buf.String(), want)
+			}
+		})
+	}
+}
+
+func TestWriteToNilResponse(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteTo(&buf, NewJSONFormatter(), nil); err == nil {
+		t.Error("WriteTo() expected error for nil response with buffered formatter")
+	}
+	if err := WriteTo(&buf, NewTextFormatter(true), nil); err == nil {
+		t.Error("WriteTo() expected error for nil response with streaming formatter")
+	}
+}
+
+func TestTextFormatterFieldSeparator(t *testing.T) {
+	response := &searxng.SearchResponse{
+		Query:           "golang",
+		NumberOfResults: 2,
+		Results: []searxng.SearchResult{
+			{Title: "First", URL: "https://example.com/1", Score: 0.9, Content: "snippet"},
+			{Title: "Second", URL: "https://example.com/2", Score: 0.25},
+		},
+		Suggestions: []string{"golang tutorial"},
+	}
+
+	t.Run("tab separated lines", func(t *testing.T) {
+		f, err := NewFormatterWithOptions("text", "general", Options{NoColor: true, FieldSeparator: "\t"})
+		if err != nil {
+			t.Fatalf("NewFormatterWithOptions() error = %v", err)
+		}
+		output, err := f.Format(response)
+		if err != nil {
+			t.Fatalf("Format() error = %v", err)
+		}
+		want := "1\tFirst\thttps://example.com/1\t0.90\n2\tSecond\thttps://example.com/2\t0.25\n"
+		if output != want {
+			t.Errorf("Format() = %q, want %q", output, want)
+		}
+	})
+
+	t.Run("custom separator with color", func(t *testing.T) {
+		tf := NewTextFormatter(false)
+		tf.FieldSeparator = "|"
+		output, _ := tf.Format(response)
+		if !containsString(output, "1|\033[1mFirst\033[0m|https://example.com/1|0.90") {
+			t.Errorf("Format() should colorize the title, got %q", output)
+		}
+	})
+
+	t.Run("empty separator keeps normal layout", func(t *testing.T) {
+		output, _ := NewTextFormatter(true).Format(response)
+		if !containsString(output, "[1] First") {
+			t.Errorf("Format() should use normal layout, got %q", output)
+		}
+	})
+}
+
+func TestResolveFieldSeparator(t *testing.T) {
+	tests := map[string]string{
+		"tab":   "\t",
+		"TAB":   "\t",
+		"pipe":  "|",
+		"comma": ",",
+		"space": " ",
+		";":     ";",
+		":":     ":",
+	}
+	for in, want := range tests {
+		if got := ResolveFieldSeparator(in); got != want {
+			t.Errorf("ResolveFieldSeparator(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFormattersShowQueryRewrite(t *testing.T) {
+	response := &searxng.SearchResponse{
+		Query:         "golang",
+		OriginalQuery: "golanf",
+		Results:       []searxng.SearchResult{{Title: "Go", URL: "https://go.dev"}},
+	}
+
+	text, _ := NewTextFormatter(true).Format(response)
+	if !containsString(text, "Showing results for golanf (searched: golang)") {
+		t.Errorf("text output missing rewrite note:\n%s", text)
+	}
+
+	md, _ := NewMarkdownFormatter().Format(response)
+	if !containsString(md, "Showing results for **golanf** (searched: golang)") {
+		t.Errorf("markdown output missing rewrite note:\n%s", md)
+	}
+
+	js, _ := NewJSONFormatter().Format(response)
+	if !containsString(js, `"original_query": "golanf"`) {
+		t.Errorf("json output missing original_query:\n%s", js)
+	}
+
+	// An unchanged query, set or not, gets no note
+	for _, original := range []string{"", "golang"} {
+		response.OriginalQuery = original
+		text, _ = NewTextFormatter(true).Format(response)
+		if containsString(text, "Showing results for") {
+			t.Errorf("text output should not mention a rewrite of %q:\n%s", original, text)
+		}
+		md, _ = NewMarkdownFormatter().Format(response)
+		if containsString(md, "Showing results for") {
+			t.Errorf("markdown output should not mention a rewrite of %q:\n%s", original, md)
+		}
+		js, _ = NewJSONFormatter().Format(response)
+		if containsString(js, "original_query") {
+			t.Errorf("json output should leave out original_query for %q:\n%s", original, js)
+		}
+	}
+}
+
+func TestNewFormatterWithOptionsCompact(t *testing.T) {
+	response := &searxng.SearchResponse{
+		Query:   "golang",
+		Results: []searxng.SearchResult{{Title: "Go", URL: "https://go.dev"}},
+	}
+
+	f, err := NewFormatterWithOptions("json", "general", Options{Compact: true})
+	if err != nil {
+		t.Fatalf("NewFormatterWithOptions() error = %v", err)
+	}
+	output, _ := f.Format(response)
+	if strings.Count(strings.TrimSuffix(output, "\n"), "\n") != 0 {
+		t.Errorf("compact JSON should be a single line, got:\n%s", output)
+	}
+
+	f, _ = NewFormatterWithOptions("json", "general", Options{})
+	output, _ = f.Format(response)
+	if !strings.Contains(output, "\n  ") {
+		t.Errorf("JSON should be indented by default, got:\n%s", output)
+	}
+}
+
+func TestBaseFormatterLimitLines(t *testing.T) {
+	lines := []string{"one", "two", "three", "four"}
+
+	tests := []struct {
+		maxLines int
+		want     []string
+	}{
+		{0, lines},
+		{4, lines},
+		{10, lines},
+		{2, []string{"one", "two", "..."}},
+		{1, []string{"one", "..."}},
+	}
+
+	for _, tt := range tests {
+		f := &BaseFormatter{Width: 80, MaxLines: tt.maxLines}
+		got := f.LimitLines(lines)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("LimitLines() with MaxLines=%d = %v, want %v", tt.maxLines, got, tt.want)
+		}
+	}
+}
+
+func TestTextFormatterContentMaxLines(t *testing.T) {
+	response := &searxng.SearchResponse{
+		Query: "golang",
+		Results: []searxng.SearchResult{
+			{Title: "Go", URL: "https://go.dev", Content: "line one\nline two\nline three"},
+		},
+	}
+
+	f, _ := NewFormatterWithOptions("text", "general", Options{NoColor: true, ContentMaxLines: 2})
+	output, _ := f.Format(response)
+	if !strings.Contains(output, "    line one\n    line two\n    ...\n") {
+		t.Errorf("content should be limited to 2 lines plus ellipsis, got:\n%s", output)
+	}
+	if strings.Contains(output, "line three") {
+		t.Errorf("content beyond the limit should be dropped, got:\n%s", output)
+	}
+
+	f, _ = NewFormatterWithOptions("text", "general", Options{NoColor: true})
+	output, _ = f.Format(response)
+	if !strings.Contains(output, "line three") {
+		t.Errorf("unlimited content should keep every line, got:\n%s", output)
+	}
+}
+
+func TestNewFormatterWithOptionsNoMetadata(t *testing.T) {
+	response := &searxng.SearchResponse{
+		Query:           "golang",
+		NumberOfResults: 1,
+		Results:         []searxng.SearchResult{{Title: "Go", URL: "https://go.dev"}},
+	}
+
+	f, _ := NewFormatterWithOptions("json", "general", Options{NoMetadata: true})
+	output, _ := f.Format(response)
+	var arr []map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &arr); err != nil {
+		t.Fatalf("JSON without metadata should be a bare array: %v\n%s", err, output)
+	}
+	if len(arr) != 1 || arr[0]["title"] != "Go" {
+		t.Errorf("unexpected array contents: %v", arr)
+	}
+
+	output, _ = f.Format(&searxng.SearchResponse{Query: "golang"})
+	if strings.TrimSpace(output) != "[]" {
+		t.Errorf("empty results should give an empty array, got %q", output)
+	}
+
+	f, _ = NewFormatterWithOptions("markdown", "general", Options{NoMetadata: true})
+	output, _ = f.Format(response)
+	if strings.Contains(output, "# Search Results") || strings.Contains(output, "Found **") {
+		t.Errorf("markdown without metadata should drop the header, got:\n%s", output)
+	}
+	if !strings.Contains(output, "[Go](https://go.dev)") {
+		t.Errorf("markdown without metadata should keep results, got:\n%s", output)
+	}
+}
+
+func TestNewFormatterWithOptionsRaw(t *testing.T) {
+	var response searxng.SearchResponse
+	data := `{"query":"golang","results":[{"title":"Go","url":"https://go.dev","engine":"google","engines":["google","bing"],"positions":[1,4],"score":2.5}]}`
+	if err := json.Unmarshal([]byte(data), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	resultsOf := func(opts Options) []map[string]interface{} {
+		t.Helper()
+		f, _ := NewFormatterWithOptions("json", "general", opts)
+		output, err := f.Format(&response)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out struct {
+			Results []map[string]interface{} `json:"results"`
+		}
+		if err := json.Unmarshal([]byte(output), &out); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, output)
+		}
+		return out.Results
+	}
+
+	raw := resultsOf(Options{Raw: true})
+	if len(raw) != 1 || raw[0]["positions"] == nil || raw[0]["engines"] == nil {
+		t.Errorf("raw JSON should keep every instance field, got %v", raw)
+	}
+
+	curated := resultsOf(Options{})
+	if len(curated) != 1 || curated[0]["positions"] != nil || curated[0]["engines"] != nil {
+		t.Errorf("default JSON should only have curated fields, got %v", curated)
+	}
+
+	f, _ := NewFormatterWithOptions("markdown", "general", Options{Raw: true})
+	output, _ := f.Format(&response)
+	if strings.Contains(output, "positions") {
+		t.Errorf("markdown should ignore raw, got:\n%s", output)
+	}
+}
+
+func TestNewFormatterWithOptionsGroupByCategory(t *testing.T) {
+	response := &searxng.SearchResponse{
+		Query: "golang",
+		Results: []searxng.SearchResult{
+			{Title: "News One", URL: "https://n1.example", Category: "news"},
+			{Title: "Web One", URL: "https://w1.example", Category: "general"},
+			{Title: "News Two", URL: "https://n2.example", Category: "news"},
+		},
+	}
+
+	f, _ := NewFormatterWithOptions("text", "general", Options{NoColor: true, GroupByCategory: true})
+	output, _ := f.Format(response)
+	general := strings.Index(output, "## General")
+	news := strings.Index(output, "## News")
+	if general < 0 || news < 0 || general > news {
+		t.Fatalf("text output should have General then News headings, got:\n%s", output)
+	}
+	if !strings.Contains(output[news:], "[2] News One") || !strings.Contains(output[news:], "[3] News Two") {
+		t.Errorf("news results should follow their heading in order, got:\n%s", output)
+	}
+
+	f, _ = NewFormatterWithOptions("markdown", "general", Options{GroupByCategory: true})
+	output, _ = f.Format(response)
+	if !strings.Contains(output, "## News\n\n### [News One](https://n1.example)") {
+		t.Errorf("markdown should nest results under category headings, got:\n%s", output)
+	}
+
+	f, _ = NewFormatterWithOptions("json", "general", Options{GroupByCategory: true})
+	output, _ = f.Format(response)
+	var parsed struct {
+		Results map[string][]map[string]interface{} `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(output), &parsed); err != nil {
+		t.Fatalf("grouped JSON should nest results by category: %v\n%s", err, output)
+	}
+	if len(parsed.Results["news"]) != 2 || len(parsed.Results["general"]) != 1 {
+		t.Errorf("unexpected grouped results: %v", parsed.Results)
+	}
+}
+
+func TestBaseFormatterFormatInt(t *testing.T) {
+	tests := []struct {
+		n    int
+		sep  string
+		want string
+	}{
+		{0, ",", "0"},
+		{999, ",", "999"},
+		{1000, ",", "1,000"},
+		{1250000, ",", "1,250,000"},
+		{1250000, ".", "1.250.000"},
+		{123456789, " ", "123 456 789"},
+		{-45000, ",", "-45,000"},
+		{1250000, "", "1250000"},
+	}
+
+	for _, tt := range tests {
+		f := &BaseFormatter{ThousandsSep: tt.sep}
+		if got := f.formatInt(tt.n); got != tt.want {
+			t.Errorf("formatInt(%d) with %q = %q, want %q", tt.n, tt.sep, got, tt.want)
+		}
+	}
+}
+
+func TestThousandsSeparator(t *testing.T) {
+	tests := map[string]string{
+		"":            ",",
+		"C":           ",",
+		"en_US.UTF-8": ",",
+		"de_DE.UTF-8": ".",
+		"pt-BR":       ".",
+		"fr_FR":       " ",
+		"sv":          " ",
+	}
+	for locale, want := range tests {
+		if got := ThousandsSeparator(locale); got != want {
+			t.Errorf("ThousandsSeparator(%q) = %q, want %q", locale, got, want)
+		}
+	}
+}
+
+func TestNewFormatterWithOptionsLocale(t *testing.T) {
+	response := &searxng.SearchResponse{
+		Query:           "golang",
+		NumberOfResults: 1250000,
+		Results:         []searxng.SearchResult{{Title: "Go", URL: "https://go.dev"}},
+	}
+
+	tests := []struct {
+		format string
+		locale string
+		want   string
+	}{
+		{"text", "", "Found 1,250,000 results"},
+		{"text", "de_DE.UTF-8", "Found 1.250.000 results"},
+		{"markdown", "", "Found **1,250,000** results"},
+		{"markdown", "it_IT", "Found **1.250.000** results"},
+	}
+	for _, tt := range tests {
+		f, _ := NewFormatterWithOptions(tt.format, "general", Options{NoColor: true, Locale: tt.locale})
+		output, _ := f.Format(response)
+		if !strings.Contains(output, tt.want) {
+			t.Errorf("%s with locale %q should contain %q, got:\n%s", tt.format, tt.locale, tt.want, output)
+		}
+	}
+
+	f, _ := NewFormatterWithOptions("json", "general", Options{Locale: "de_DE"})
+	output, _ := f.Format(response)
+	if !strings.Contains(output, `"total_results": 1250000`) {
+		t.Errorf("JSON counts should stay numeric, got:\n%s", output)
+	}
+}
+
+func TestPrettyURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		maxLen int
+		want   string
+	}{
+		{"fits", "https://go.dev/doc", 30, "https://go.dev/doc"},
+		{"no limit", "https://go.dev/doc/tutorial/getting-started", 0, "https://go.dev/doc/tutorial/getting-started"},
+		{"path cut", "https://go.dev/doc/tutorial/getting-started", 30, "https://go.dev/doc/tutorial..."},
+		{"query dropped", "https://go.dev/doc?utm_source=newsletter&utm_medium=email", 30, "https://go.dev/doc..."},
+		{"host kept whole", "https://a-very-long-subdomain.example.com/path", 20, "https://a-very-long-subdomain.example.com..."},
+		{"not a URL", strings.Repeat("x", 40), 20, strings.Repeat("x", 17) + "..."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PrettyURL(tt.url, tt.maxLen); got != tt.want {
+				t.Errorf("PrettyURL(%q, %d) = %q, want %q", tt.url, tt.maxLen, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextFormatterPrettyURLs(t *testing.T) {
+	long := "https://go.dev/doc/tutorial/getting-started/with/a/very/long/path?utm_source=x"
+	response := &searxng.SearchResponse{
+		Query:   "golang",
+		Results: []searxng.SearchResult{{Title: "Go", URL: long, Engine: "google"}},
+	}
+
+	f, _ := NewFormatterWithOptions("text", "general", Options{NoColor: true, PrettyURLs: true})
+	output, _ := f.Format(response)
+	if !strings.Contains(output, "    "+PrettyURL(long, prettyURLWidth)+"\n") {
+		t.Errorf("result should show the shortened URL, got:\n%s", output)
+	}
+	if !strings.Contains(output, "## Links\n\n[1] "+long+"\n") {
+		t.Errorf("full URL should be listed after the results, got:\n%s", output)
+	}
+
+	f, _ = NewFormatterWithOptions("json", "general", Options{PrettyURLs: true})
+	output, _ = f.Format(response)
+	if !strings.Contains(output, `"url": "https://go.dev/doc/tutorial/getting-started/with/a/very/long/path?utm_source=x"`) {
+		t.Errorf("JSON should keep the full URL, got:\n%s", output)
+	}
+}
+
+func TestFormatterStartIndex(t *testing.T) {
+	response := &searxng.SearchResponse{
+		Query: "golang",
+		Page:  2,
+		Results: []searxng.SearchResult{
+			{Title: "Eleventh", URL: "https://a.example", Engine: "google"},
+			{Title: "Twelfth", URL: "https://b.example", Engine: "bing"},
+		},
+	}
+
+	tests := []struct {
+		format string
+		opts   Options
+		want   []string
+	}{
+		{"text", Options{StartIndex: 10}, []string{"[11] Eleventh", "[12] Twelfth"}},
+		{"text", Options{StartIndex: 10, FieldSeparator: "\t"}, []string{"11\tEleventh", "12\tTwelfth"}},
+		{"text", Options{StartIndex: 10, PrettyURLs: true}, []string{"[11] https://a.example", "[12] https://b.example"}},
+		{"table", Options{StartIndex: 10}, []string{"11  Eleventh", "12  Twelfth"}},
+		{"text", Options{}, []string{"[1] Eleventh", "[2] Twelfth"}},
+	}
+
+	for _, tt := range tests {
+		tt.opts.NoColor = true
+		f, err := NewFormatterWithOptions(tt.format, "general", tt.opts)
+		if err != nil {
+			t.Fatalf("NewFormatterWithOptions(%s) error = %v", tt.format, err)
+		}
+		output, err := f.Format(response)
+		if err != nil {
+			t.Fatalf("Format() error = %v", err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(output, want) {
+				t.Errorf("%s %+v: output should contain %q, got:\n%s", tt.format, tt.opts, want, output)
+			}
+		}
+	}
+}
+
+func TestFormattersAgreeOnMissingTotal(t *testing.T) {
+	// Instances send 0 or leave the field out when engines give no estimate
+	for name, body := range map[string]string{
+		"omitted": `{"query":"golang","results":[{"title":"Go","url":"https://go.dev"},{"title":"Tour","url":"https://go.dev/tour/"}]}`,
+		"zero":    `{"query":"golang","number_of_results":0,"results":[{"title":"Go","url":"https://go.dev"},{"title":"Tour","url":"https://go.dev/tour/"}]}`,
+	} {
+		var response searxng.SearchResponse
+		if err := json.Unmarshal([]byte(body), &response); err != nil {
+			t.Fatal(err)
+		}
+
+		tests := []struct {
+			format string
+			want   string
+		}{
+			{"text", "Found 2 results"},
+			{"markdown", "Found **2** results"},
+			{"table", "Found 2 results"},
+			{"json", `"total_results": 2`},
+		}
+		for _, tt := range tests {
+			f, err := NewFormatterWithOptions(tt.format, "general", Options{NoColor: true})
+			if err != nil {
+				t.Fatalf("NewFormatterWithOptions(%s) error = %v", tt.format, err)
+			}
+			output, err := f.Format(&response)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if !strings.Contains(output, tt.want) {
+				t.Errorf("%s, %s: output should contain %q, got:\n%s", name, tt.format, tt.want, output)
+			}
+		}
+	}
+}
+
+func TestTextFormatterExplain(t *testing.T) {
+	var response searxng.SearchResponse
+	body := `{"query":"golang","results":[{"title":"Go","url":"https://go.dev","engine":"google","engines":["google","bing"],"category":"general","score":1.5}]}`
+	if err := json.Unmarshal([]byte(body), &response); err != nil {
+		t.Fatal(err)
+	}
+	want := "Explain: raw score 1.5000 | engines: google, bing | category: general"
+
+	for _, tt := range []struct {
+		format string
+		opts   Options
+		shown  bool
+	}{
+		{"text", Options{Explain: true}, true},
+		{"text", Options{}, false},
+		{"json", Options{Explain: true}, false},
+		{"markdown", Options{Explain: true}, false},
+	} {
+		tt.opts.NoColor = true
+		f, err := NewFormatterWithOptions(tt.format, "general", tt.opts)
+		if err != nil {
+			t.Fatalf("NewFormatterWithOptions(%s) error = %v", tt.format, err)
+		}
+		output, err := f.Format(&response)
+		if err != nil {
+			t.Fatalf("Format() error = %v", err)
+		}
+		if got := strings.Contains(output, want); got != tt.shown {
+			t.Errorf("%s %+v: explanation shown = %v, want %v; output:\n%s", tt.format, tt.opts, got, tt.shown, output)
+		}
+	}
+
+	// The raw score is the engine's, not the normalized one
+	original := 1.5
+	result := response.Results[0]
+	result.Score, result.OriginalScore = 1, &original
+	if got := explainResult(result); !strings.Contains(got, "raw score 1.5000") {
+		t.Errorf("explainResult() = %q, want the original score", got)
+	}
+}
+
+func TestFormatterSummary(t *testing.T) {
+	results := make([]searxng.SearchResult, 5)
+	for i := range results {
+		results[i] = searxng.SearchResult{Title: fmt.Sprintf("Result %d", i+1), URL: fmt.Sprintf("https://example.com/%d", i+1)}
+	}
+	withAnswers := &searxng.SearchResponse{
+		Query:     "what is go",
+		Results:   results,
+		Answers:   []searxng.Answer{{Answer: "Go is a programming language"}},
+		Infoboxes: []searxng.Infobox{{Infobox: "Go", Content: "Statically typed, compiled language"}},
+	}
+	withoutAnswers := &searxng.SearchResponse{Query: "what is go", Results: results}
+
+	for _, format := range []string{"text", "markdown"} {
+		f, err := NewFormatterWithOptions(format, "general", Options{NoColor: true, Summary: true})
+		if err != nil {
+			t.Fatalf("NewFormatterWithOptions(%s) error = %v", format, err)
+		}
+
+		output, err := f.Format(withAnswers)
+		if err != nil {
+			t.Fatalf("Format() error = %v", err)
+		}
+		answer := strings.Index(output, "Go is a programming language")
+		infobox := strings.Index(output, "Statically typed, compiled language")
+		first := strings.Index(output, "Result 1")
+		if answer < 0 || infobox < 0 || first < 0 || answer > first || infobox > first {
+			t.Errorf("%s: answers and infoboxes should precede the results; output:\n%s", format, output)
+		}
+		if !strings.Contains(output, "Result 3") || strings.Contains(output, "Result 4") {
+			t.Errorf("%s: summary should show only the top 3 results; output:\n%s", format, output)
+		}
+
+		// Without answers or infoboxes the output is unchanged
+		plain, err := NewFormatterWithOptions(format, "general", Options{NoColor: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := plain.Format(withoutAnswers)
+		if got, _ := f.Format(withoutAnswers); got != want {
+			t.Errorf("%s: summary without answers = %q, want normal output %q", format, got, want)
+		}
+	}
+}
+
+func TestTextFormatterFormatDiff(t *testing.T) {
+	added := []searxng.SearchResult{{Title: "New", URL: "https://example.com/new"}}
+	removed := []searxng.SearchResult{{Title: "Old", URL: "https://example.com/old"}}
+
+	plain := NewTextFormatter(true).FormatDiff(added, removed)
+	want := "+ New\n  https://example.com/new\n- Old\n  https://example.com/old\n"
+	if plain != want {
+		t.Errorf("FormatDiff() = %q, want %q", plain, want)
+	}
+	if got := NewTextFormatter(true).FormatDiff(added, nil); strings.Contains(got, "Old") {
+		t.Errorf("FormatDiff() without removed results = %q", got)
+	}
+	if got := NewTextFormatter(true).FormatDiff(nil, nil); got != "" {
+		t.Errorf("FormatDiff() without changes = %q, want empty", got)
+	}
+
+	colored := NewTextFormatter(false).FormatDiff(added, removed)
+	if !strings.Contains(colored, "\033[32m+\033[0m") || !strings.Contains(colored, "\033[31m-\033[0m") {
+		t.Errorf("FormatDiff() with color = %q, want a green + and a red -", colored)
+	}
+}
+
+func TestTextFormatterNoWrap(t *testing.T) {
+	long := strings.Repeat("lorem ipsum dolor sit amet ", 10)
+	content := long + "\nsecond line"
+	response := &searxng.SearchResponse{
+		Query:   "lorem",
+		Results: []searxng.SearchResult{{Title: "Lorem", URL: "https://example.com", Content: content}},
+	}
+
+	f := NewTextFormatter(true)
+	f.NoWrap = true
+	output, err := f.Format(response)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if !strings.Contains(output, "    "+long+"\n    second line\n") {
+		t.Errorf("content lines should be emitted whole; output:\n%s", output)
+	}
+
+	// Wrapping stays on by default
+	f.NoWrap = false
+	output, _ = f.Format(response)
+	if strings.Contains(output, long) {
+		t.Errorf("content should be truncated without NoWrap; output:\n%s", output)
+	}
+}
+
+func TestTextFormatterWidth(t *testing.T) {
+	content := strings.Repeat("word ", 20) // 100 characters
+	response := &searxng.SearchResponse{
+		Query:   "words",
+		Results: []searxng.SearchResult{{Title: "Words", URL: "https://example.com", Content: content}},
+	}
+
+	for _, width := range []int{40, 80, 120} {
+		f, err := NewFormatterWithOptions("text", "general", Options{NoColor: true, Width: width})
+		if err != nil {
+			t.Fatal(err)
+		}
+		output, err := f.Format(response)
+		if err != nil {
+			t.Fatalf("Format() error = %v", err)
+		}
+		for _, line := range strings.Split(output, "\n") {
+			if len(line) > width {
+				t.Errorf("width %d: line %q is %d columns", width, line, len(line))
+			}
+		}
+		if whole := strings.Contains(output, strings.TrimSpace(content)); whole != (width == 120) {
+			t.Errorf("width %d: content shown whole = %v; output:\n%s", width, whole, output)
+		}
+	}
+}
+
+func TestTextFormatterNarrowWidth(t *testing.T) {
+	content := strings.Repeat("日本語のテキスト ", 10)
+	response := &searxng.SearchResponse{
+		Query:   "日本語",
+		Results: []searxng.SearchResult{{Title: "Japanese", URL: "https://example.jp", Content: content}},
+	}
+
+	// Widths below the ellipsis and indent must not panic
+	for _, width := range []int{1, 8, 10, 11, 12, 30} {
+		f := NewTextFormatter(true)
+		f.Width = width
+		output, err := f.Format(response)
+		if err != nil {
+			t.Fatalf("width %d: Format() error = %v", width, err)
+		}
+		if !utf8.ValidString(output) {
+			t.Errorf("width %d: output should be valid UTF-8, got %q", width, output)
+		}
+		if !strings.Contains(output, "...") {
+			t.Errorf("width %d: content should be truncated; output:\n%s", width, output)
+		}
+	}
+}
+
+func TestNewFormatterWithOptionsImages(t *testing.T) {
+	response := &searxng.SearchResponse{
+		Query: "cats",
+		Results: []searxng.SearchResult{{
+			Title:   "Cat",
+			URL:     "https://example.com/cat",
+			ImgSrc:  "https://example.com/cat.jpg",
+			Content: strings.Repeat("a cat on a mat ", 10),
+			Engine:  "google",
+			Score:   1,
+		}},
+	}
+	format := func(format string, opts Options) string {
+		t.Helper()
+		f, err := NewFormatterWithOptions(format, "images", opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		output, err := f.Format(response)
+		if err != nil {
+			t.Fatalf("Format() error = %v", err)
+		}
+		return output
+	}
+
+	// The image layout honors numbering, width and explain
+	output := format("text", Options{StartIndex: 10, Width: 40, Explain: true})
+	if !strings.Contains(output, "[11] Cat") || !strings.Contains(output, "Image: https://example.com/cat.jpg") {
+		t.Errorf("expected the image layout numbered from 11; output:\n%s", output)
+	}
+	if !strings.Contains(output, "    "+strings.Repeat("a cat on a mat ", 10)[:29]+"...") {
+		t.Errorf("expected the description cut to the width; output:\n%s", output)
+	}
+	if !strings.Contains(output, "    Explain: raw score 1.0000") {
+		t.Errorf("expected the explain line; output:\n%s", output)
+	}
+	if output := format("text", Options{Width: 40, NoWrap: true}); !strings.Contains(output, strings.TrimSpace(response.Results[0].Content)+"\n") {
+		t.Errorf("expected the whole description with NoWrap; output:\n%s", output)
+	}
+
+	// Options only the regular layout has select it instead of being dropped
+	if output := format("text", Options{NoColor: true, FieldSeparator: "\t"}); !strings.Contains(output, "Cat\thttps://example.com/cat") {
+		t.Errorf("expected single-line output with a field separator; output:\n%s", output)
+	}
+	if output := format("markdown", Options{GroupByCategory: true}); strings.Contains(output, "![Cat]") {
+		t.Errorf("expected the regular markdown layout when grouping; output:\n%s", output)
+	}
+	if output := format("markdown", Options{}); !strings.Contains(output, "![Cat](https://example.com/cat.jpg)") {
+		t.Errorf("expected embedded images by default; output:\n%s", output)
+	}
+}
+
+func TestJSONFormatterPagesFetched(t *testing.T) {
+	response := &searxng.SearchResponse{
+		Query:   "golang",
+		Results: []searxng.SearchResult{{Title: "Go", URL: "https://go.dev/"}},
+	}
+
+	output, err := NewJSONFormatter().Format(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(output, "pages_fetched") {
+		t.Errorf("a single page should leave pages_fetched out, got:\n%s", output)
+	}
+
+	response.PagesFetched = 3
+	output, err = NewJSONFormatter().Format(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output, `"pages_fetched": 3`) {
+		t.Errorf("expected pages_fetched in the metadata, got:\n%s", output)
+	}
+}
+
+func TestJSONFormatterPartial(t *testing.T) {
+	response := &searxng.SearchResponse{
+		Query:   "golang",
+		Results: []searxng.SearchResult{{Title: "Go", URL: "https://go.dev/"}},
+	}
+
+	output, err := NewJSONFormatter().Format(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(output, `"partial"`) {
+		t.Errorf("expected no partial flag for a whole response, got:\n%s", output)
+	}
+
+	response.Partial = true
+	output, err = NewJSONFormatter().Format(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output, `"partial": true`) {
+		t.Errorf("expected metadata.partial for a response cut off, got:\n%s", output)
+	}
+}
+
+func TestJSONFormatterSectionCounts(t *testing.T) {
+	response := &searxng.SearchResponse{
+		Query:   "golang",
+		Results: []searxng.SearchResult{{Title: "Go", URL: "https://go.dev/"}},
+		Answers: []searxng.Answer{
+			{Answer: "Go is a programming language"},
+			{Answer: "Go was released in 2009"},
+		},
+		Infoboxes: []searxng.Infobox{{Infobox: "Go", Content: "Programming language"}},
+	}
+
+	var decoded struct {
+		Metadata struct {
+			AnswersCount   *int `json:"answers_count"`
+			InfoboxesCount *int `json:"infoboxes_count"`
+		} `json:"metadata"`
+	}
+	decode := func() {
+		t.Helper()
+		output, err := NewJSONFormatter().Format(response)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal([]byte(output), &decoded); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, output)
+		}
+		if decoded.Metadata.AnswersCount == nil || decoded.Metadata.InfoboxesCount == nil {
+			t.Fatalf("expected both counts in the metadata, got:\n%s", output)
+		}
+	}
+
+	decode()
+	if got := *decoded.Metadata.AnswersCount; got != 2 {
+		t.Errorf("answers_count = %d, want 2", got)
+	}
+	if got := *decoded.Metadata.InfoboxesCount; got != 1 {
+		t.Errorf("infoboxes_count = %d, want 1", got)
+	}
+
+	// The arrays are left out when empty, but the counts stay at 0
+	response.Answers, response.Infoboxes = nil, nil
+	decode()
+	if *decoded.Metadata.AnswersCount != 0 || *decoded.Metadata.InfoboxesCount != 0 {
+		t.Errorf("expected zero counts, got %d and %d", *decoded.Metadata.AnswersCount, *decoded.Metadata.InfoboxesCount)
+	}
+}
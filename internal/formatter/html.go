@@ -0,0 +1,101 @@
+package formatter
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/mule-ai/search/internal/searxng"
+)
+
+// htmlPage is the standalone page HTMLFormatter renders. html/template
+// escapes every field and neutralizes unsafe URLs such as javascript:.
+var htmlPage = template.Must(template.New("html").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Search Results: {{.Response.Query}}</title>
+</head>
+<body>
+<h1>Search Results: {{.Response.Query}}</h1>
+<p>{{if .Response.Results}}Found {{.Total}} results in {{printf "%.2f" .Response.SearchTime}}s{{else}}No results found{{end}}</p>
+{{- if .Response.Answers}}
+<h2>Answers</h2>
+<ul>
+{{- range .Response.Answers}}
+<li>{{.Answer}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- range .Response.Infoboxes}}
+<h2>{{.Infobox}}</h2>
+{{- if .Content}}
+<p>{{.Content}}</p>
+{{- end}}
+{{- end}}
+{{- if .Response.Results}}
+<ol>
+{{- range .Response.Results}}
+<li>
+<h3><a href="{{.URL}}">{{.Title}}</a></h3>
+<p><cite>{{.URL}}</cite></p>
+{{- if .Content}}
+<p>{{.Content}}</p>
+{{- end}}
+<p><small>Source: {{.Engine}}</small></p>
+</li>
+{{- end}}
+</ol>
+{{- end}}
+{{- if .Response.Suggestions}}
+<h2>Suggestions</h2>
+<ul>
+{{- range .Response.Suggestions}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+</body>
+</html>
+`))
+
+// HTMLFormatter formats search results as a standalone HTML page, for
+// archiving or viewing in a browser.
+type HTMLFormatter struct {
+	BaseFormatter
+}
+
+// NewHTMLFormatter creates a new HTML formatter.
+//
+// Example:
+//
+//	hf := formatter.NewHTMLFormatter()
+//	page, err := hf.Format(response)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	os.WriteFile("results.html", []byte(page), 0o644)
+func NewHTMLFormatter() *HTMLFormatter {
+	return &HTMLFormatter{
+		BaseFormatter: *NewBaseFormatter(),
+	}
+}
+
+// Format renders the search results as an HTML page.
+//
+// Returns an error if the response is nil.
+func (f *HTMLFormatter) Format(result *searxng.SearchResponse) (string, error) {
+	if result == nil {
+		return "", fmt.Errorf("nil response provided")
+	}
+
+	var buf strings.Builder
+	err := htmlPage.Execute(&buf, struct {
+		Response *searxng.SearchResponse
+		Total    string
+	}{result, f.formatInt(searxng.EffectiveTotal(result))})
+	if err != nil {
+		return "", fmt.Errorf("failed to render HTML: %w", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,50 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mule-ai/search/internal/searxng"
+)
+
+func TestHTMLFormatter(t *testing.T) {
+	response := &searxng.SearchResponse{
+		Query:           "<go> & rust",
+		NumberOfResults: 1234,
+		Results: []searxng.SearchResult{
+			{Title: "Go <script>", URL: "https://go.dev/?a=1&b=2", Content: "Fast & simple", Engine: "google"},
+			{Title: "Bad link", URL: "javascript:alert(1)", Engine: "bing"},
+		},
+		Answers:     []searxng.Answer{{Answer: "Go is a language"}},
+		Suggestions: []string{"golang"},
+	}
+
+	f, err := NewFormatterWithOptions("html", "general", Options{})
+	if err != nil {
+		t.Fatalf("NewFormatterWithOptions(html) error = %v", err)
+	}
+	output, err := f.Format(response)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"<title>Search Results: &lt;go&gt; &amp; rust</title>",
+		"Found 1,234 results",
+		`<a href="https://go.dev/?a=1&amp;b=2">Go &lt;script&gt;</a>`,
+		"<p>Fast &amp; simple</p>",
+		"<li>Go is a language</li>",
+		"<li>golang</li>",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, `href="javascript:`) {
+		t.Errorf("unsafe URL should be neutralized:\n%s", output)
+	}
+
+	if _, err := f.Format(nil); err == nil {
+		t.Error("Format(nil) expected error")
+	}
+}
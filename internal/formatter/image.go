@@ -13,6 +13,16 @@ import (
 // making it easy to view and access image search results.
 type ImageFormatter struct {
 	plainFormatter *TextFormatter
+
+	// StartIndex is added to the result numbers, e.g. 10 on page 2
+	StartIndex int
+	// Width caps descriptions at Width-8 characters; 0 keeps the default
+	// of 200
+	Width int
+	// NoWrap leaves descriptions whole
+	NoWrap bool
+	// Explain adds a line to each result with the signals behind its rank
+	Explain bool
 }
 
 // NewImageFormatter creates a new image-specific formatter.
@@ -52,7 +62,7 @@ func (f *ImageFormatter) Format(response *searxng.SearchResponse) (string, error
 	
 	// Results
 	for i, result := range response.Results {
-		sb.WriteString(f.formatImageResult(i+1, result))
+		sb.WriteString(f.formatImageResult(f.StartIndex+i+1, result))
 		if i < len(response.Results)-1 {
 			sb.WriteString("\n")
 		}
@@ -77,8 +87,8 @@ func (f *ImageFormatter) formatHeader(response *searxng.SearchResponse) string {
 	sb.WriteString("\n")
 	sb.WriteString(strings.Repeat("=", len(query)))
 	
-	if response.NumberOfResults > 0 {
-		sb.WriteString(fmt.Sprintf("\nFound %d results", response.NumberOfResults))
+	if total := searxng.EffectiveTotal(response); total > 0 {
+		sb.WriteString(fmt.Sprintf("\nFound %d results", total))
 	}
 	
 	return sb.String()
@@ -110,12 +120,19 @@ func (f *ImageFormatter) formatImageResult(index int, result searxng.SearchResul
 		}
 		sb.WriteString(fmt.Sprintf("    Source: %s\n", strings.Join(metadata, " | ")))
 	}
+	if f.Explain {
+		sb.WriteString("    " + explainResult(result) + "\n")
+	}
 	
 	// Content (truncated description)
 	if result.Content != "" {
 		content := strings.TrimSpace(result.Content)
-		if len(content) > 200 {
-			content = content[:197] + "..."
+		if !f.NoWrap {
+			limit := 200
+			if f.Width > 0 {
+				limit = f.Width - 8
+			}
+			content = truncateContent(content, limit)
 		}
 		sb.WriteString(fmt.Sprintf("\n    %s\n", content))
 	}
@@ -166,8 +183,8 @@ func FormatAsMarkdown(response *searxng.SearchResponse) (string, error) {
 	// Header
 	sb.WriteString(fmt.Sprintf("# Search Results: %s\n\n", response.Query))
 	
-	if response.NumberOfResults > 0 {
-		sb.WriteString(fmt.Sprintf("Found **%d** results", response.NumberOfResults))
+	if total := searxng.EffectiveTotal(response); total > 0 {
+		sb.WriteString(fmt.Sprintf("Found **%d** results", total))
 		if response.SearchTime > 0 {
 			sb.WriteString(fmt.Sprintf(" in %.2fs\n\n", response.SearchTime))
 		} else {
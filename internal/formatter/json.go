@@ -9,7 +9,11 @@ import (
 
 // JSONFormatter formats search results as JSON.
 type JSONFormatter struct {
-	Pretty bool // Enable pretty-printed output with indentation
+	Pretty          bool // Enable pretty-printed output with indentation
+	ResultsOnly     bool // Output a bare results array without query or metadata
+	GroupByCategory bool // Nest results under their category
+	Raw             bool // Emit each result as the instance sent it
+	EngineStats     bool // Add each engine's result count and average score
 }
 
 // NewJSONFormatter creates a new JSON formatter with pretty-printing enabled.
@@ -52,41 +56,115 @@ func (f *JSONFormatter) Format(result *searxng.SearchResponse) (string, error) {
 		return "", fmt.Errorf("nil response provided")
 	}
 
-	// Create output structure matching SPEC
-	metadata := map[string]interface{}{
-		"search_time": fmt.Sprintf("%.2fs", result.SearchTime),
-		"instance":    result.Instance,
-	}
-
-	// Add pagination info if page > 1
-	if result.Page > 1 {
-		metadata["page"] = result.Page
-	}
-
-	output := map[string]interface{}{
-		"query":         result.Query,
-		"total_results": result.NumberOfResults,
-		"results":       f.formatResults(result.Results),
-		"metadata":      metadata,
+	if f.ResultsOnly {
+		if f.GroupByCategory {
+			return f.marshal(f.groupResults(result.Results))
+		}
+		return f.FormatResultsOnly(result.Results)
 	}
 
-	if len(result.Answers) > 0 {
-		output["answers"] = result.Answers
-	}
-	if len(result.Infoboxes) > 0 {
-		output["infoboxes"] = result.Infoboxes
+	// Create output structure matching SPEC
+	// original_query is only reported when the query was rewritten
+	originalQuery := result.OriginalQuery
+	if originalQuery == result.Query {
+		originalQuery = ""
 	}
-	if len(result.Suggestions) > 0 {
-		output["suggestions"] = result.Suggestions
+	metadata := jsonMetadata{
+		AnswersCount:   len(result.Answers),
+		InfoboxesCount: len(result.Infoboxes),
+		Instance:       result.Instance,
+		OriginalQuery:  originalQuery,
+		Partial:        result.Partial,
+		SearchTime:    fmt.Sprintf("%.2fs", result.SearchTime),
 	}
 
+	// Add pagination info if page > 1
+	if result.Page > 1 {
+		metadata.Page = result.Page
+	}
+	if result.PagesFetched > 1 {
+		metadata.PagesFetched = result.PagesFetched
+	}
+
+	var results interface{} = f.formatResults(result.Results)
+	if f.GroupByCategory {
+		results = f.groupResults(result.Results)
+	}
+
+	var stats []searxng.EngineStat
+	if f.EngineStats {
+		stats = searxng.EngineStats(result.Results)
+	}
+
+	return f.marshal(jsonOutput{
+		Answers:      result.Answers,
+		EngineStats:  stats,
+		Infoboxes:    result.Infoboxes,
+		Metadata:     metadata,
+		Query:        result.Query,
+		Results:      results,
+		Suggestions:  result.Suggestions,
+		TotalResults: searxng.EffectiveTotal(result),
+	})
+}
+
+// jsonOutput is the top-level layout of Format's output. Fields are
+// marshaled in the order declared here, which is alphabetical; scripts
+// may depend on it, so new fields go in their sorted place and
+// testdata/json_output.golden is updated with them. Empty sections are
+// left out.
+type jsonOutput struct {
+	Answers      []searxng.Answer  `json:"answers,omitempty"`
+	EngineStats  []searxng.EngineStat `json:"engine_stats,omitempty"`
+	Infoboxes    []searxng.Infobox `json:"infoboxes,omitempty"`
+	Metadata     jsonMetadata      `json:"metadata"`
+	Query        string            `json:"query"`
+	Results      interface{}       `json:"results"` // []interface{}, or a map of them by category
+	Suggestions  []string          `json:"suggestions,omitempty"`
+	TotalResults int               `json:"total_results"`
+}
+
+// jsonMetadata is the metadata object in Format's output. Page is only
+// set past the first page, and PagesFetched only when several pages were
+// merged into one results array, and Partial only when a response was cut
+// off. The answer and infobox counts are always present, so they are 0
+// when those sections are left out.
+type jsonMetadata struct {
+	AnswersCount   int    `json:"answers_count"`
+	InfoboxesCount int    `json:"infoboxes_count"`
+	Instance       string `json:"instance"`
+	OriginalQuery  string `json:"original_query,omitempty"`
+	Page           int    `json:"page,omitempty"`
+	PagesFetched   int    `json:"pages_fetched,omitempty"`
+	Partial        bool   `json:"partial,omitempty"`
+	SearchTime     string `json:"search_time"`
+}
+
+// jsonResult is the curated layout of a result in JSON and NDJSON
+// output, with fields in alphabetical order like jsonOutput.
+type jsonResult struct {
+	Category      string   `json:"category"`
+	Content       string   `json:"content"`
+	DetectedLanguage string `json:"detected_language,omitempty"`
+	Engine        string   `json:"engine"`
+	ImgSrc        string   `json:"img_src,omitempty"`
+	OriginalScore *float64 `json:"original_score,omitempty"`
+	ParsedURL     []string `json:"parsed_url,omitempty"`
+	Score         float64  `json:"score"`
+	Template      string   `json:"template,omitempty"`
+	Title         string   `json:"title"`
+	URL           string   `json:"url"`
+}
+
+// marshal encodes v, indented when Pretty is set.
+func (f *JSONFormatter) marshal(v interface{}) (string, error) {
 	var data []byte
 	var err error
 
 	if f.Pretty {
-		data, err = json.MarshalIndent(output, "", "  ")
+		data, err = json.MarshalIndent(v, "", "  ")
 	} else {
-		data, err = json.Marshal(output)
+		data, err = json.Marshal(v)
 	}
 
 	if err != nil {
@@ -96,31 +174,49 @@ func (f *JSONFormatter) Format(result *searxng.SearchResponse) (string, error) {
 	return string(data), nil
 }
 
-func (f *JSONFormatter) formatResults(results []searxng.SearchResult) []map[string]interface{} {
-	var formatted []map[string]interface{}
+// groupResults maps each category to its results in JSON field layout.
+func (f *JSONFormatter) groupResults(results []searxng.SearchResult) map[string][]interface{} {
+	grouped := make(map[string][]interface{})
+	for name, group := range searxng.GroupByCategory(results) {
+		grouped[name] = f.formatResults(group)
+	}
+	return grouped
+}
+
+func (f *JSONFormatter) formatResults(results []searxng.SearchResult) []interface{} {
+	var formatted []interface{}
 	for _, result := range results {
-		r := map[string]interface{}{
-			"title":   result.Title,
-			"url":     result.URL,
-			"content": result.Content,
-			"engine":  result.Engine,
-			"category": result.Category,
-			"score":   result.Score,
-		}
-		if result.ImgSrc != "" {
-			r["img_src"] = result.ImgSrc
-		}
-		if len(result.ParsedURL) > 0 {
-			r["parsed_url"] = result.ParsedURL
-		}
-		if result.Template != "" {
-			r["template"] = result.Template
-		}
-		formatted = append(formatted, r)
+		formatted = append(formatted, f.resultValue(result))
 	}
 	return formatted
 }
 
+// resultValue returns the result's raw fields when Raw is set and they
+// were captured, and the curated jsonResult layout otherwise.
+func (f *JSONFormatter) resultValue(result searxng.SearchResult) interface{} {
+	if f.Raw && result.Raw != nil {
+		return result.Raw
+	}
+	return toJSONResult(result)
+}
+
+// toJSONResult converts a result into the layout used by JSON output.
+func toJSONResult(result searxng.SearchResult) jsonResult {
+	return jsonResult{
+		Category:      result.Category,
+		Content:       result.Content,
+		DetectedLanguage: result.DetectedLanguage,
+		Engine:        result.Engine,
+		ImgSrc:        result.ImgSrc,
+		OriginalScore: result.OriginalScore,
+		ParsedURL:     result.ParsedURL,
+		Score:         result.Score,
+		Template:      result.Template,
+		Title:         result.Title,
+		URL:           result.URL,
+	}
+}
+
 // FormatWithQuery formats results with a custom query string
 func (f *JSONFormatter) FormatWithQuery(query string, results []searxng.SearchResult, searchTime float64, instance string) (string, error) {
 	output := map[string]interface{}{
@@ -168,20 +264,10 @@ func (f *JSONFormatter) IsPretty() bool {
 
 // FormatAsArray formats results as a JSON array (useful for piping to jq)
 func (f *JSONFormatter) FormatAsArray(results []searxng.SearchResult) (string, error) {
-	var arr []map[string]interface{}
+	// Always an array, even when empty, so consumers can iterate safely
+	arr := make([]interface{}, 0, len(results))
 	for _, result := range results {
-		r := map[string]interface{}{
-			"title":   result.Title,
-			"url":     result.URL,
-			"content": result.Content,
-			"engine":  result.Engine,
-			"category": result.Category,
-			"score":   result.Score,
-		}
-		if result.ImgSrc != "" {
-			r["img_src"] = result.ImgSrc
-		}
-		arr = append(arr, r)
+		arr = append(arr, f.resultValue(result))
 	}
 
 	var data []byte
@@ -842,7 +928,7 @@ func (f *JSONFormatter) FormatResponse(result *searxng.SearchResponse) string {
 		result.Answers,
 		result.Infoboxes,
 		result.Suggestions,
-		result.NumberOfResults,
+		searxng.EffectiveTotal(result),
 		result.SearchTime,
 	)
 	return s
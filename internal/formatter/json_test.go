@@ -0,0 +1,71 @@
+package formatter
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mule-ai/search/internal/searxng"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// TestJSONFormatterGolden pins the exact JSON layout, including key
+// order, so changes to it are deliberate. Run with -update to rewrite
+// testdata/json_output.golden after an intended change.
+func TestJSONFormatterGolden(t *testing.T) {
+	original := 12.5
+	response := &searxng.SearchResponse{
+		Query:           "golang",
+		NumberOfResults: 1250,
+		SearchTime:      0.42,
+		Page:            2,
+		Instance:        "https://search.example",
+		OriginalQuery:   "golnag",
+		Results: []searxng.SearchResult{
+			{
+				Title:         "The Go Programming Language",
+				URL:           "https://go.dev/",
+				Content:       "Go is an open source programming language.",
+				Engine:        "google",
+				Category:      "general",
+				Score:         1,
+				ParsedURL:     []string{"https", "go.dev", "/", "", "", ""},
+				OriginalScore: &original,
+			},
+			{
+				Title:    "Gopher",
+				URL:      "https://go.dev/blog/gopher",
+				Engine:   "bing",
+				Category: "images",
+				Score:    0.5,
+				ImgSrc:   "https://go.dev/blog/gopher/header.jpg",
+				Template: "images.html",
+			},
+		},
+		Answers:     []searxng.Answer{{Answer: "Go is a programming language", URL: "https://go.dev"}},
+		Infoboxes:   []searxng.Infobox{{Infobox: "Go", Content: "Programming language", URL: "https://go.dev"}},
+		Suggestions: []string{"golang tutorial"},
+	}
+
+	output, err := NewJSONFormatter().Format(response)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	output += "\n"
+
+	golden := filepath.Join("testdata", "json_output.golden")
+	if *update {
+		if err := os.WriteFile(golden, []byte(output), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("reading golden file: %v (run with -update to create it)", err)
+	}
+	if output != string(want) {
+		t.Errorf("JSON output differs from %s:\ngot:\n%s\nwant:\n%s", golden, output, want)
+	}
+}
@@ -0,0 +1,51 @@
+package formatter
+
+import (
+	"os"
+	"strings"
+)
+
+// periodGroupingLanguages write 1.250.000 rather than 1,250,000.
+var periodGroupingLanguages = map[string]bool{
+	"da": true, "de": true, "el": true, "es": true, "id": true,
+	"it": true, "nl": true, "pt": true, "ro": true, "tr": true,
+}
+
+// spaceGroupingLanguages write 1 250 000.
+var spaceGroupingLanguages = map[string]bool{
+	"cs": true, "fi": true, "fr": true, "nb": true, "pl": true,
+	"ru": true, "sk": true, "sv": true, "uk": true,
+}
+
+// ThousandsSeparator returns the digit group separator for locale, such
+// as "de_DE.UTF-8" or "fr-CA". Locales it doesn't know, and an empty
+// locale, use a comma.
+//
+// Example:
+//
+//	formatter.ThousandsSeparator("de_DE.UTF-8") // "."
+func ThousandsSeparator(locale string) string {
+	lang := strings.ToLower(locale)
+	if i := strings.IndexAny(lang, "_-.@"); i >= 0 {
+		lang = lang[:i]
+	}
+	switch {
+	case periodGroupingLanguages[lang]:
+		return "."
+	case spaceGroupingLanguages[lang]:
+		return " "
+	default:
+		return ","
+	}
+}
+
+// LocaleFromEnv returns the locale for number formatting from LC_ALL,
+// LC_NUMERIC or LANG, whichever is set first.
+func LocaleFromEnv() string {
+	for _, name := range []string{"LC_ALL", "LC_NUMERIC", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
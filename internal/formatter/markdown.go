@@ -10,6 +10,8 @@ import (
 // MarkdownFormatter formats search results as Markdown.
 type MarkdownFormatter struct {
 	BaseFormatter
+	NoMetadata bool // Omit the search header and result count
+	Summary    bool // Lead with answers and infoboxes, then the top few results
 }
 
 // NewMarkdownFormatter creates a new Markdown formatter.
@@ -53,36 +55,57 @@ func (f *MarkdownFormatter) Format(result *searxng.SearchResponse) (string, erro
 
 	var buf strings.Builder
 
-	// Header
-	buf.WriteString(fmt.Sprintf("# Search Results: %s\n\n", result.Query))
-	
-	// Display results count - use NumberOfResults if available, otherwise use count of returned results
-	totalResults := result.NumberOfResults
-	if totalResults == 0 {
-		totalResults = len(result.Results)
+	if !f.NoMetadata {
+		f.writeHeader(&buf, result)
 	}
-	
-	if totalResults > 0 {
-		buf.WriteString(fmt.Sprintf("Found **%d** results in %.2fs", totalResults, result.SearchTime))
-		
-		// Add page info if not on first page
-		if result.Page > 1 {
-			buf.WriteString(fmt.Sprintf(" (Page %d)", result.Page))
-		}
-		
-		buf.WriteString("\n\n")
-	} else {
-		buf.WriteString("No results found\n\n")
+
+	// In summary mode answers and infoboxes come first, and only the top
+	// results follow them
+	results := result.Results
+	summary := f.Summary && hasSummary(result)
+	if summary {
+		var lead strings.Builder
+		f.writeAnswers(&lead, result, true)
+		buf.WriteString(strings.TrimPrefix(lead.String(), "\n") + "\n")
+		results = results[:min(len(results), summaryResults)]
 	}
 
 	// Results
-	for i, res := range result.Results {
-		f.formatResult(&buf, res, i)
-		if i < len(result.Results)-1 {
-			buf.WriteString("\n---\n\n")
+	index := 0
+	for si, section := range f.resultSections(results) {
+		if section.Heading != "" {
+			if si > 0 {
+				buf.WriteString("\n")
+			}
+			buf.WriteString(fmt.Sprintf("## %s\n\n", section.Heading))
+		}
+		for i, res := range section.Results {
+			f.formatResult(&buf, res, index)
+			index++
+			if i < len(section.Results)-1 {
+				buf.WriteString("\n---\n\n")
+			}
+		}
+	}
+
+	if !summary {
+		f.writeAnswers(&buf, result, false)
+	}
+
+	// Suggestions
+	if len(result.Suggestions) > 0 {
+		buf.WriteString("\n## Suggestions\n\n")
+		for _, suggestion := range result.Suggestions {
+			buf.WriteString(fmt.Sprintf("- %s\n", suggestion))
 		}
 	}
 
+	return buf.String(), nil
+}
+
+// writeAnswers writes the answers and infoboxes sections. With detail,
+// each infobox is followed by its content.
+func (f *MarkdownFormatter) writeAnswers(buf *strings.Builder, result *searxng.SearchResponse, detail bool) {
 	// Answers
 	if len(result.Answers) > 0 {
 		buf.WriteString("\n## Answers\n\n")
@@ -96,23 +119,46 @@ func (f *MarkdownFormatter) Format(result *searxng.SearchResponse) (string, erro
 		buf.WriteString("\n## Infoboxes\n\n")
 		for _, infobox := range result.Infoboxes {
 			buf.WriteString(fmt.Sprintf("- %s\n", infobox.Infobox))
+			if detail && len(infobox.Content) > 0 {
+				buf.WriteString(fmt.Sprintf("\n  %s\n", infobox.Content))
+			}
 		}
 	}
+}
 
-	// Suggestions
-	if len(result.Suggestions) > 0 {
-		buf.WriteString("\n## Suggestions\n\n")
-		for _, suggestion := range result.Suggestions {
-			buf.WriteString(fmt.Sprintf("- %s\n", suggestion))
+// writeHeader writes the search header, any query correction note and the
+// result count.
+func (f *MarkdownFormatter) writeHeader(buf *strings.Builder, result *searxng.SearchResponse) {
+	// Header
+	buf.WriteString(fmt.Sprintf("# Search Results: %s\n\n", result.Query))
+
+	if result.OriginalQuery != "" && result.OriginalQuery != result.Query {
+		buf.WriteString(fmt.Sprintf("*Showing results for **%s** (searched: %s)*\n\n", result.OriginalQuery, result.Query))
+	}
+	
+	totalResults := searxng.EffectiveTotal(result)
+	
+	if totalResults > 0 {
+		buf.WriteString(fmt.Sprintf("Found **%s** results in %.2fs", f.formatInt(totalResults), result.SearchTime))
+		
+		// Add page info if not on first page
+		if result.Page > 1 {
+			buf.WriteString(fmt.Sprintf(" (Page %d)", result.Page))
 		}
+		
+		buf.WriteString("\n\n")
+	} else {
+		buf.WriteString("No results found\n\n")
 	}
-
-	return buf.String(), nil
 }
 
 func (f *MarkdownFormatter) formatResult(buf *strings.Builder, result searxng.SearchResult, index int) {
-	// Title as heading
-	buf.WriteString(fmt.Sprintf("## [%s](%s)\n", f.escapeMarkdown(result.Title), result.URL))
+	// Title as heading, one level below the category heading when grouped
+	heading := "##"
+	if f.GroupByCategory {
+		heading = "###"
+	}
+	buf.WriteString(fmt.Sprintf("%s [%s](%s)\n", heading, f.escapeMarkdown(result.Title), result.URL))
 
 	// Source and score
 	var sourceInfo strings.Builder
@@ -171,7 +217,7 @@ func (f *MarkdownFormatter) FormatResults(results []searxng.SearchResult, query
 
 	buf.WriteString(fmt.Sprintf("# Search Results: %s\n\n", query))
 	if total > 0 {
-		buf.WriteString(fmt.Sprintf("Found **%d** results in %.2fs\n\n", total, searchTime))
+		buf.WriteString(fmt.Sprintf("Found **%s** results in %.2fs\n\n", f.formatInt(total), searchTime))
 	} else {
 		buf.WriteString("No results found\n\n")
 	}
@@ -192,7 +238,7 @@ func (f *MarkdownFormatter) FormatWithQuery(query string, results []searxng.Sear
 
 	buf.WriteString(fmt.Sprintf("# Search Results: %s\n\n", query))
 	if total > 0 {
-		buf.WriteString(fmt.Sprintf("Found **%d** results in %.2fs\n\n", total, searchTime))
+		buf.WriteString(fmt.Sprintf("Found **%s** results in %.2fs\n\n", f.formatInt(total), searchTime))
 	} else {
 		buf.WriteString("No results found\n\n")
 	}
@@ -213,7 +259,7 @@ func (f *MarkdownFormatter) FormatWithAnswers(query string, results []searxng.Se
 
 	buf.WriteString(fmt.Sprintf("# Search Results: %s\n\n", query))
 	if len(results) > 0 {
-		buf.WriteString(fmt.Sprintf("Found **%d** results\n\n", len(results)))
+		buf.WriteString(fmt.Sprintf("Found **%s** results\n\n", f.formatInt(len(results))))
 	} else {
 		buf.WriteString("No results found\n\n")
 	}
@@ -241,7 +287,7 @@ func (f *MarkdownFormatter) FormatWithInfoboxes(query string, results []searxng.
 
 	buf.WriteString(fmt.Sprintf("# Search Results: %s\n\n", query))
 	if len(results) > 0 {
-		buf.WriteString(fmt.Sprintf("Found **%d** results\n\n", len(results)))
+		buf.WriteString(fmt.Sprintf("Found **%s** results\n\n", f.formatInt(len(results))))
 	} else {
 		buf.WriteString("No results found\n\n")
 	}
@@ -269,7 +315,7 @@ func (f *MarkdownFormatter) FormatWithSuggestions(query string, results []searxn
 
 	buf.WriteString(fmt.Sprintf("# Search Results: %s\n\n", query))
 	if len(results) > 0 {
-		buf.WriteString(fmt.Sprintf("Found **%d** results\n\n", len(results)))
+		buf.WriteString(fmt.Sprintf("Found **%s** results\n\n", f.formatInt(len(results))))
 	} else {
 		buf.WriteString("No results found\n\n")
 	}
@@ -297,7 +343,7 @@ func (f *MarkdownFormatter) FormatFull(query string, results []searxng.SearchRes
 
 	buf.WriteString(fmt.Sprintf("# Search Results: %s\n\n", query))
 	if len(results) > 0 {
-		buf.WriteString(fmt.Sprintf("Found **%d** results\n\n", len(results)))
+		buf.WriteString(fmt.Sprintf("Found **%s** results\n\n", f.formatInt(len(results))))
 	} else {
 		buf.WriteString("No results found\n\n")
 	}
@@ -339,7 +385,7 @@ func (f *MarkdownFormatter) FormatHeader(query string, total int, searchTime flo
 	var buf strings.Builder
 	buf.WriteString(fmt.Sprintf("# Search Results: %s\n\n", query))
 	if total > 0 {
-		buf.WriteString(fmt.Sprintf("Found **%d** results in %.2fs\n\n", total, searchTime))
+		buf.WriteString(fmt.Sprintf("Found **%s** results in %.2fs\n\n", f.formatInt(total), searchTime))
 	} else {
 		buf.WriteString("No results found\n\n")
 	}
@@ -358,7 +404,7 @@ func (f *MarkdownFormatter) FormatResultsTable(results []searxng.SearchResult, q
 	var buf strings.Builder
 	buf.WriteString(fmt.Sprintf("# Search Results: %s\n\n", query))
 	if total > 0 {
-		buf.WriteString(fmt.Sprintf("Found **%d** results\n\n", total))
+		buf.WriteString(fmt.Sprintf("Found **%s** results\n\n", f.formatInt(total)))
 	} else {
 		buf.WriteString("No results found\n\n")
 	}
@@ -383,7 +429,7 @@ func (f *MarkdownFormatter) FormatSimple(results []searxng.SearchResult, query s
 	var buf strings.Builder
 	buf.WriteString(fmt.Sprintf("# Search Results: %s\n\n", query))
 	if total > 0 {
-		buf.WriteString(fmt.Sprintf("Found **%d** results\n\n", total))
+		buf.WriteString(fmt.Sprintf("Found **%s** results\n\n", f.formatInt(total)))
 	}
 
 	for i, res := range results {
@@ -453,7 +499,7 @@ func (f *MarkdownFormatter) FormatWithAnswersAndInfoboxes(query string, results
 	var buf strings.Builder
 	buf.WriteString(fmt.Sprintf("# Search Results: %s\n\n", query))
 	if len(results) > 0 {
-		buf.WriteString(fmt.Sprintf("Found **%d** results\n\n", len(results)))
+		buf.WriteString(fmt.Sprintf("Found **%s** results\n\n", f.formatInt(len(results))))
 	} else {
 		buf.WriteString("No results found\n\n")
 	}
@@ -488,7 +534,7 @@ func (f *MarkdownFormatter) FormatWithAnswersAndSuggestions(query string, result
 	var buf strings.Builder
 	buf.WriteString(fmt.Sprintf("# Search Results: %s\n\n", query))
 	if len(results) > 0 {
-		buf.WriteString(fmt.Sprintf("Found **%d** results\n\n", len(results)))
+		buf.WriteString(fmt.Sprintf("Found **%s** results\n\n", f.formatInt(len(results))))
 	} else {
 		buf.WriteString("No results found\n\n")
 	}
@@ -522,7 +568,7 @@ func (f *MarkdownFormatter) FormatWithInfoboxesAndSuggestions(query string, resu
 	var buf strings.Builder
 	buf.WriteString(fmt.Sprintf("# Search Results: %s\n\n", query))
 	if len(results) > 0 {
-		buf.WriteString(fmt.Sprintf("Found **%d** results\n\n", len(results)))
+		buf.WriteString(fmt.Sprintf("Found **%s** results\n\n", f.formatInt(len(results))))
 	} else {
 		buf.WriteString("No results found\n\n")
 	}
@@ -557,7 +603,7 @@ func (f *MarkdownFormatter) FormatWithAllMetadata(query string, results []searxn
 	var buf strings.Builder
 	buf.WriteString(fmt.Sprintf("# Search Results: %s\n\n", query))
 	if len(results) > 0 {
-		buf.WriteString(fmt.Sprintf("Found **%d** results\n\n", len(results)))
+		buf.WriteString(fmt.Sprintf("Found **%s** results\n\n", f.formatInt(len(results))))
 	} else {
 		buf.WriteString("No results found\n\n")
 	}
@@ -610,7 +656,7 @@ func (f *MarkdownFormatter) FormatCompact(results []searxng.SearchResult, query
 	var buf strings.Builder
 	buf.WriteString(fmt.Sprintf("# Search Results: %s\n\n", query))
 	if total > 0 {
-		buf.WriteString(fmt.Sprintf("Found **%d** results\n\n", total))
+		buf.WriteString(fmt.Sprintf("Found **%s** results\n\n", f.formatInt(total)))
 	}
 
 	for i, res := range results {
@@ -626,7 +672,7 @@ func (f *MarkdownFormatter) FormatWithSearchTime(query string, results []searxng
 	var buf strings.Builder
 	buf.WriteString(fmt.Sprintf("# Search Results: %s\n\n", query))
 	if len(results) > 0 {
-		buf.WriteString(fmt.Sprintf("Found **%d** results in %.2fs\n\n", len(results), searchTime))
+		buf.WriteString(fmt.Sprintf("Found **%s** results in %.2fs\n\n", f.formatInt(len(results)), searchTime))
 	} else {
 		buf.WriteString("No results found\n\n")
 	}
@@ -646,7 +692,7 @@ func (f *MarkdownFormatter) FormatWithInstance(query string, results []searxng.S
 	var buf strings.Builder
 	buf.WriteString(fmt.Sprintf("# Search Results: %s\n\n", query))
 	if len(results) > 0 {
-		buf.WriteString(fmt.Sprintf("Found **%d** results\n\n", len(results)))
+		buf.WriteString(fmt.Sprintf("Found **%s** results\n\n", f.formatInt(len(results))))
 		buf.WriteString(fmt.Sprintf("*Instance: %s*\n\n", instance))
 	} else {
 		buf.WriteString("No results found\n\n")
@@ -667,7 +713,7 @@ func (f *MarkdownFormatter) FormatWithAllOptions(query string, results []searxng
 	var buf strings.Builder
 	buf.WriteString(fmt.Sprintf("# Search Results: %s\n\n", query))
 	if len(results) > 0 {
-		buf.WriteString(fmt.Sprintf("Found **%d** results in %.2fs\n\n", len(results), searchTime))
+		buf.WriteString(fmt.Sprintf("Found **%s** results in %.2fs\n\n", f.formatInt(len(results)), searchTime))
 	} else {
 		buf.WriteString("No results found\n\n")
 	}
@@ -709,7 +755,7 @@ func (f *MarkdownFormatter) FormatWithResultCount(query string, results []searxn
 	var buf strings.Builder
 	buf.WriteString(fmt.Sprintf("# Search Results: %s\n\n", query))
 	if total > 0 {
-		buf.WriteString(fmt.Sprintf("Found **%d** results\n\n", total))
+		buf.WriteString(fmt.Sprintf("Found **%s** results\n\n", f.formatInt(total)))
 	} else {
 		buf.WriteString("No results found\n\n")
 	}
@@ -729,7 +775,7 @@ func (f *MarkdownFormatter) FormatWithSearchTimeAndInstance(query string, result
 	var buf strings.Builder
 	buf.WriteString(fmt.Sprintf("# Search Results: %s\n\n", query))
 	if len(results) > 0 {
-		buf.WriteString(fmt.Sprintf("Found **%d** results in %.2fs\n\n", len(results), searchTime))
+		buf.WriteString(fmt.Sprintf("Found **%s** results in %.2fs\n\n", f.formatInt(len(results)), searchTime))
 		buf.WriteString(fmt.Sprintf("*Instance: %s*\n\n", instance))
 	} else {
 		buf.WriteString("No results found\n\n")
@@ -750,7 +796,7 @@ func (f *MarkdownFormatter) FormatWithAll(query string, results []searxng.Search
 	var buf strings.Builder
 	buf.WriteString(fmt.Sprintf("# Search Results: %s\n\n", query))
 	if total > 0 {
-		buf.WriteString(fmt.Sprintf("Found **%d** results in %.2fs\n\n", total, searchTime))
+		buf.WriteString(fmt.Sprintf("Found **%s** results in %.2fs\n\n", f.formatInt(total), searchTime))
 	} else {
 		buf.WriteString("No results found\n\n")
 	}
@@ -795,7 +841,7 @@ func (f *MarkdownFormatter) FormatResponseWithAll(result *searxng.SearchResponse
 		result.Answers,
 		result.Infoboxes,
 		result.Suggestions,
-		result.NumberOfResults,
+		searxng.EffectiveTotal(result),
 		result.SearchTime,
 	)
 }
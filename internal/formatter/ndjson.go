@@ -0,0 +1,131 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mule-ai/search/internal/searxng"
+)
+
+// NDJSONFormatter formats search results as newline-delimited JSON.
+//
+// Each result is emitted as a single compact JSON object on its own line,
+// which makes the output easy to consume with line-oriented tools.
+type NDJSONFormatter struct {
+	// Pretty indents each object over several lines and separates objects
+	// with a blank line, for reading streamed records by eye. The output
+	// is then not NDJSON: line-based parsers that expect one object per
+	// line can't read it, though a streaming JSON decoder can.
+	Pretty bool
+	// Meta starts the output with a {"type":"meta"} record carrying the
+	// query, total and instance, and tags every result record with
+	// "type":"result", so a consumer reading line by line can tell them
+	// apart. It is the json-lines-with-meta format.
+	Meta bool
+}
+
+// ndjsonMeta is the first record of json-lines-with-meta output. Type
+// comes first so consumers can dispatch on it; the rest are alphabetical
+// like jsonMetadata.
+type ndjsonMeta struct {
+	Type          string `json:"type"`
+	Instance      string `json:"instance"`
+	OriginalQuery string `json:"original_query,omitempty"`
+	Partial       bool   `json:"partial,omitempty"`
+	Query         string `json:"query"`
+	TotalResults  int    `json:"total_results"`
+}
+
+// ndjsonResult is a result record of json-lines-with-meta output: the
+// fields of jsonResult after a "type":"result" discriminator.
+type ndjsonResult struct {
+	Type string `json:"type"`
+	jsonResult
+}
+
+// NewNDJSONFormatter creates a new NDJSON formatter.
+//
+// Example:
+//
+//	nf := formatter.NewNDJSONFormatter()
+//	if err := nf.StreamFormat(os.Stdout, response); err != nil {
+//	    log.Fatal(err)
+//	}
+func NewNDJSONFormatter() *NDJSONFormatter {
+	return &NDJSONFormatter{}
+}
+
+// Format formats the search results as NDJSON.
+//
+// Returns one JSON object per line, or an error if the response is nil.
+func (f *NDJSONFormatter) Format(result *searxng.SearchResponse) (string, error) {
+	var buf strings.Builder
+	if err := f.StreamFormat(&buf, result); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// StreamFormat writes each result of the decoded response to w as a JSON
+// line as soon as it is encoded.
+// With Pretty, each result is an indented object followed by a blank line.
+// With Meta, a metadata record comes first, even when there are no results.
+func (f *NDJSONFormatter) StreamFormat(w io.Writer, result *searxng.SearchResponse) error {
+	if result == nil {
+		return fmt.Errorf("nil response provided")
+	}
+
+	if f.Meta {
+		// original_query is only reported when the query was rewritten
+		originalQuery := result.OriginalQuery
+		if originalQuery == result.Query {
+			originalQuery = ""
+		}
+		meta := ndjsonMeta{
+			Type:          "meta",
+			Instance:      result.Instance,
+			OriginalQuery: originalQuery,
+			Partial:       result.Partial,
+			Query:         result.Query,
+			TotalResults:  searxng.EffectiveTotal(result),
+		}
+		if err := f.writeRecord(w, meta); err != nil {
+			return fmt.Errorf("failed to marshal metadata: %w", err)
+		}
+	}
+
+	for _, res := range result.Results {
+		var record interface{} = toJSONResult(res)
+		if f.Meta {
+			record = ndjsonResult{Type: "result", jsonResult: toJSONResult(res)}
+		}
+		if err := f.writeRecord(w, record); err != nil {
+			return fmt.Errorf("failed to marshal result: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// writeRecord writes v to w as one line of JSON, or with Pretty as an
+// indented object followed by a blank line.
+func (f *NDJSONFormatter) writeRecord(w io.Writer, v interface{}) error {
+	var data []byte
+	var err error
+	if f.Pretty {
+		data, err = json.MarshalIndent(v, "", "  ")
+	} else {
+		data, err = json.Marshal(v)
+	}
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if f.Pretty {
+		data = append(data, '\n')
+	}
+	_, err = w.Write(data)
+	return err
+}
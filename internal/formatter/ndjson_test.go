@@ -0,0 +1,196 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mule-ai/search/internal/searxng"
+)
+
+func TestNDJSONFormatter(t *testing.T) {
+	response := &searxng.SearchResponse{
+		Query: "golang",
+		Results: []searxng.SearchResult{
+			{Title: "First", URL: "https://example.com/1", Engine: "google", Score: 0.9},
+			{Title: "Second", URL: "https://example.com/2", Engine: "bing", Score: 0.5},
+		},
+	}
+
+	t.Run("one object per line", func(t *testing.T) {
+		output, err := NewNDJSONFormatter().Format(response)
+		if err != nil {
+			t.Fatalf("Format() error = %v", err)
+		}
+
+		lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("Format() produced %d lines, want 2", len(lines))
+		}
+		for i, line := range lines {
+			var obj map[string]interface{}
+			if err := json.Unmarshal([]byte(line), &obj); err != nil {
+				t.Fatalf("line %d is not valid JSON: %v", i, err)
+			}
+			if obj["url"] != response.Results[i].URL {
+				t.Errorf("line %d url = %v, want %s", i, obj["url"], response.Results[i].URL)
+			}
+		}
+	})
+
+	t.Run("stream matches format", func(t *testing.T) {
+		f := NewNDJSONFormatter()
+		want, _ := f.Format(response)
+
+		var buf bytes.Buffer
+		if err := f.StreamFormat(&buf, response); err != nil {
+			t.Fatalf("StreamFormat() error = %v", err)
+		}
+		if buf.String() != want {
+			t.Errorf("StreamFormat() = %q, want %q", buf.String(), want)
+		}
+	})
+
+	t.Run("empty results", func(t *testing.T) {
+		output, err := NewNDJSONFormatter().Format(&searxng.SearchResponse{Query: "empty"})
+		if err != nil {
+			t.Fatalf("Format() error = %v", err)
+		}
+		if output != "" {
+			t.Errorf("Format() = %q, want empty output", output)
+		}
+	})
+
+	t.Run("nil response", func(t *testing.T) {
+		if _, err := NewNDJSONFormatter().Format(nil); err == nil {
+			t.Error("Format(nil) expected error")
+		}
+	})
+
+	t.Run("registered in factory", func(t *testing.T) {
+		f, err := NewFormatter("ndjson")
+		if err != nil {
+			t.Fatalf("NewFormatter(ndjson) error = %v", err)
+		}
+		if _, ok := f.(StreamFormatter); !ok {
+			t.Error("ndjson formatter should implement StreamFormatter")
+		}
+	})
+}
+
+func TestNDJSONFormatterPretty(t *testing.T) {
+	response := &searxng.SearchResponse{
+		Query: "golang",
+		Results: []searxng.SearchResult{
+			{Title: "First", URL: "https://example.com/1", Engine: "google", Score: 0.9},
+			{Title: "Second", URL: "https://example.com/2", Engine: "bing", Score: 0.5},
+		},
+	}
+
+	f, err := NewFormatter("jsonl-pretty")
+	if err != nil {
+		t.Fatalf("NewFormatter(jsonl-pretty) error = %v", err)
+	}
+	if _, ok := f.(StreamFormatter); !ok {
+		t.Error("jsonl-pretty formatter should implement StreamFormatter")
+	}
+	output, err := f.Format(response)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	records := strings.Split(output, "}\n\n")
+	if len(records) != 3 || records[2] != "" {
+		t.Fatalf("expected two records each followed by a blank line, got:\n%s", output)
+	}
+	if !strings.Contains(records[0], "\n  \"title\": \"First\"") {
+		t.Errorf("records should be indented, got:\n%s", records[0])
+	}
+
+	// The records still decode as a stream of JSON values
+	dec := json.NewDecoder(strings.NewReader(output))
+	for i := range response.Results {
+		var obj map[string]interface{}
+		if err := dec.Decode(&obj); err != nil {
+			t.Fatalf("record %d: %v", i, err)
+		}
+		if obj["url"] != response.Results[i].URL {
+			t.Errorf("record %d url = %v, want %s", i, obj["url"], response.Results[i].URL)
+		}
+	}
+}
+
+func TestNDJSONFormatterOriginalScore(t *testing.T) {
+	original := 42.0
+	response := &searxng.SearchResponse{
+		Results: []searxng.SearchResult{
+			{Title: "Normalized", Score: 1, OriginalScore: &original},
+			{Title: "Raw", Score: 0.5},
+		},
+	}
+
+	output, err := NewNDJSONFormatter().Format(response)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if !strings.Contains(lines[0], `"original_score":42`) {
+		t.Errorf("normalized result should include original_score, got %s", lines[0])
+	}
+	if strings.Contains(lines[1], "original_score") {
+		t.Errorf("raw result should not include original_score, got %s", lines[1])
+	}
+}
+
+func TestNDJSONFormatterMeta(t *testing.T) {
+	response := &searxng.SearchResponse{
+		Query:           "golang site:go.dev",
+		OriginalQuery:   "golang",
+		Instance:        "https://search.example.com",
+		NumberOfResults: 120,
+		Results: []searxng.SearchResult{
+			{Title: "First", URL: "https://example.com/1", Engine: "google", Score: 0.9},
+			{Title: "Second", URL: "https://example.com/2", Engine: "bing", Score: 0.5},
+		},
+	}
+
+	f, err := NewFormatter("json-lines-with-meta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := f.Format(response)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Format() produced %d lines, want a meta record and 2 results:\n%s", len(lines), output)
+	}
+	want := `{"type":"meta","instance":"https://search.example.com","original_query":"golang","query":"golang site:go.dev","total_results":120}`
+	if lines[0] != want {
+		t.Errorf("meta record = %s, want %s", lines[0], want)
+	}
+	for i, line := range lines[1:] {
+		var obj map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &obj); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v", i+1, err)
+		}
+		if obj["type"] != "result" || obj["url"] != response.Results[i].URL {
+			t.Errorf("line %d = %s, want result %s", i+1, line, response.Results[i].URL)
+		}
+		if !strings.HasPrefix(line, `{"type":"result",`) {
+			t.Errorf("line %d should start with its type, got %s", i+1, line)
+		}
+	}
+
+	// A search without results still reports its metadata
+	output, err = f.Format(&searxng.SearchResponse{Query: "empty"})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if output != `{"type":"meta","instance":"","query":"empty","total_results":0}`+"\n" {
+		t.Errorf("Format() without results = %q, want only the meta record", output)
+	}
+}
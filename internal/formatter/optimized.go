@@ -92,7 +92,7 @@ func (f *OptimizedFormatter) formatMarkdown(result *searxng.SearchResponse, buf
 
 	// Write result count
 	buf.WriteString("Found **")
-	buf.WriteString(formatNumber(result.NumberOfResults))
+	buf.WriteString(formatNumber(searxng.EffectiveTotal(result)))
 	buf.WriteString("** results")
 
 	if result.SearchTime > 0 {
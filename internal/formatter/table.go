@@ -0,0 +1,50 @@
+package formatter
+
+import (
+	"fmt"
+
+	"github.com/mule-ai/search/internal/searxng"
+)
+
+// TableFormatter formats search results as an aligned terminal table.
+//
+// It gives a compact, scannable view with one row per result showing the
+// index, title, source engine and score.
+type TableFormatter struct {
+	text *TextFormatter
+	// PrettyURLs adds a column of shortened URLs, with the full URLs
+	// listed below the table
+	PrettyURLs bool
+	// StartIndex offsets the row numbers, as in BaseFormatter
+	StartIndex int
+}
+
+// NewTableFormatter creates a new table formatter.
+//
+// The noColor parameter controls whether the bold header row is disabled.
+//
+// Example:
+//
+//	tf := formatter.NewTableFormatter(false)
+//	output, err := tf.Format(response)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(output)
+func NewTableFormatter(noColor bool) *TableFormatter {
+	return &TableFormatter{
+		text: NewTextFormatter(noColor),
+	}
+}
+
+// Format formats the search results as a table.
+//
+// Returns an error if the response is nil.
+func (f *TableFormatter) Format(result *searxng.SearchResponse) (string, error) {
+	if result == nil {
+		return "", fmt.Errorf("nil response provided")
+	}
+	f.text.PrettyURLs = f.PrettyURLs
+	f.text.StartIndex = f.StartIndex
+	return f.text.FormatResultsTable(result.Results, result.Query, searxng.EffectiveTotal(result)), nil
+}
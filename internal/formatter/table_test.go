@@ -0,0 +1,131 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mule-ai/search/internal/searxng"
+)
+
+func TestTableFormatter(t *testing.T) {
+	response := &searxng.SearchResponse{
+		Query:           "golang",
+		NumberOfResults: 2,
+		Results: []searxng.SearchResult{
+			{Title: "A Tour of Go", Engine: "google", Score: 0.95},
+			{Title: strings.Repeat("x", 80), Engine: "duckduckgo", Score: 12.5},
+		},
+	}
+
+	t.Run("aligned columns", func(t *testing.T) {
+		f, err := NewFormatterWithOptions("table", "general", Options{NoColor: true})
+		if err != nil {
+			t.Fatalf("NewFormatterWithOptions(table) error = %v", err)
+		}
+		output, err := f.Format(response)
+		if err != nil {
+			t.Fatalf("Format() error = %v", err)
+		}
+		if strings.Contains(output, "\033[") {
+			t.Error("NoColor table output should not contain color codes")
+		}
+
+		lines := strings.Split(output, "\n")
+		var table []string
+		for i, line := range lines {
+			if strings.HasPrefix(line, "#") {
+				table = lines[i : i+4]
+				break
+			}
+		}
+		if table == nil {
+			t.Fatalf("Format() missing header row:\n%s", output)
+		}
+
+		// Title column is sized to the longest (truncated) title
+		want := "1  A Tour of Go" + strings.Repeat(" ", 50-len("A Tour of Go")) + "  google       0.95"
+		if table[2] != want {
+			t.Errorf("row 1 = %q, want %q", table[2], want)
+		}
+		if !strings.Contains(table[3], strings.Repeat("x", 47)+"...") {
+			t.Errorf("row 2 should truncate title with ellipsis, got %q", table[3])
+		}
+		if !strings.HasSuffix(table[3], "12.50") || !strings.HasSuffix(table[2], " 0.95") {
+			t.Errorf("scores should be right-aligned, got %q and %q", table[2], table[3])
+		}
+		if len(table[1]) != len(table[3]) {
+			t.Errorf("separator width %d, want %d", len(table[1]), len(table[3]))
+		}
+	})
+
+	t.Run("narrow columns for short data", func(t *testing.T) {
+		small := &searxng.SearchResponse{
+			Query:   "go",
+			Results: []searxng.SearchResult{{Title: "Go", Engine: "bing", Score: 1}},
+		}
+		output, _ := NewTableFormatter(true).Format(small)
+		if !strings.Contains(output, "#  Title  Source  Score\n") {
+			t.Errorf("header should fit the data, got:\n%s", output)
+		}
+		if !strings.Contains(output, "1  Go     bing     1.00\n") {
+			t.Errorf("row should fit the data, got:\n%s", output)
+		}
+	})
+
+	t.Run("bold header with color", func(t *testing.T) {
+		output, _ := NewTableFormatter(false).Format(response)
+		if !strings.Contains(output, "\033[1m#") {
+			t.Errorf("header row should be bold, got:\n%s", output)
+		}
+	})
+
+	t.Run("nil response", func(t *testing.T) {
+		if _, err := NewTableFormatter(true).Format(nil); err == nil {
+			t.Error("Format(nil) expected error")
+		}
+	})
+}
+
+func TestTableFormatterPrettyURLs(t *testing.T) {
+	long := "https://go.dev/doc/tutorial/getting-started/with/a/very/long/path"
+	response := &searxng.SearchResponse{
+		Query: "golang",
+		Results: []searxng.SearchResult{
+			{Title: "Tutorial", URL: long, Engine: "google", Score: 1},
+			{Title: "Go", URL: "https://go.dev", Engine: "bing", Score: 0.5},
+		},
+	}
+
+	f, err := NewFormatterWithOptions("table", "general", Options{NoColor: true, PrettyURLs: true})
+	if err != nil {
+		t.Fatalf("NewFormatterWithOptions(table) error = %v", err)
+	}
+	output, err := f.Format(response)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	lines := strings.Split(output, "\n")
+	var table []string
+	for i, line := range lines {
+		if strings.HasPrefix(line, "#") {
+			table = lines[i : i+4]
+			break
+		}
+	}
+	if table == nil {
+		t.Fatalf("Format() missing header row:\n%s", output)
+	}
+	if !strings.Contains(table[0], "URL") {
+		t.Errorf("header should have a URL column, got %q", table[0])
+	}
+	if !strings.Contains(table[2], PrettyURL(long, prettyURLWidth)) {
+		t.Errorf("row 1 should show the shortened URL, got %q", table[2])
+	}
+	if len(table[1]) != len(table[2]) {
+		t.Errorf("separator width %d, want %d", len(table[1]), len(table[2]))
+	}
+	if !strings.Contains(output, "[1] "+long+"\n[2] https://go.dev\n") {
+		t.Errorf("full URLs should be listed below the table, got:\n%s", output)
+	}
+}
@@ -0,0 +1,82 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/mule-ai/search/internal/searxng"
+)
+
+// TemplateFormatter formats search results with a user-supplied Go
+// text/template.
+//
+// The template is executed with the *searxng.SearchResponse as its data,
+// so fields like .Query and .Results are available directly.
+type TemplateFormatter struct {
+	tmpl *template.Template
+}
+
+// templateFuncs are the helper functions available inside templates.
+var templateFuncs = template.FuncMap{
+	"truncate": func(length int, s string) string {
+		return NewBaseFormatter().TruncateWithEllipsis(s, length)
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"inc":   func(i int) int { return i + 1 },
+}
+
+// NewTemplateFormatter creates a formatter from template source text.
+//
+// Returns an error if the template cannot be parsed.
+//
+// Example:
+//
+//	tf, err := formatter.NewTemplateFormatter("{{range .Results}}{{.Title}}\n{{end}}")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	output, err := tf.Format(response)
+func NewTemplateFormatter(text string) (*TemplateFormatter, error) {
+	tmpl, err := template.New("search").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	return &TemplateFormatter{tmpl: tmpl}, nil
+}
+
+// NewTemplateFormatterFromFile creates a formatter from a template file.
+//
+// Returns an error if the file cannot be read or parsed.
+func NewTemplateFormatterFromFile(path string) (*TemplateFormatter, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	return NewTemplateFormatter(string(data))
+}
+
+// Format executes the template against the search results.
+//
+// Returns an error if the response is nil or execution fails.
+func (f *TemplateFormatter) Format(result *searxng.SearchResponse) (string, error) {
+	var buf strings.Builder
+	if err := f.StreamFormat(&buf, result); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// StreamFormat executes the template, writing the output directly to w.
+func (f *TemplateFormatter) StreamFormat(w io.Writer, result *searxng.SearchResponse) error {
+	if result == nil {
+		return fmt.Errorf("nil response provided")
+	}
+	if err := f.tmpl.Execute(w, result); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+	return nil
+}
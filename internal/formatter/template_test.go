@@ -0,0 +1,65 @@
+package formatter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mule-ai/search/internal/searxng"
+)
+
+func TestTemplateFormatter(t *testing.T) {
+	response := &searxng.SearchResponse{
+		Query: "golang",
+		Results: []searxng.SearchResult{
+			{Title: "A Tour of Go", URL: "https://go.dev/tour/"},
+			{Title: "Effective Go", URL: "https://go.dev/doc/effective_go"},
+		},
+	}
+
+	t.Run("executes against the response", func(t *testing.T) {
+		tf, err := NewTemplateFormatter("{{upper .Query}}\n{{range $i, $r := .Results}}{{inc $i}}. {{truncate 8 $r.Title}} <{{$r.URL}}>\n{{end}}")
+		if err != nil {
+			t.Fatalf("NewTemplateFormatter() error = %v", err)
+		}
+		output, err := tf.Format(response)
+		if err != nil {
+			t.Fatalf("Format() error = %v", err)
+		}
+		want := "GOLANG\n1. A Tou... <https://go.dev/tour/>\n2. Effec... <https://go.dev/doc/effective_go>\n"
+		if output != want {
+			t.Errorf("Format() = %q, want %q", output, want)
+		}
+	})
+
+	t.Run("from file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "brief.tmpl")
+		if err := os.WriteFile(path, []byte("{{len .Results}} results"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		tf, err := NewTemplateFormatterFromFile(path)
+		if err != nil {
+			t.Fatalf("NewTemplateFormatterFromFile() error = %v", err)
+		}
+		output, _ := tf.Format(response)
+		if output != "2 results" {
+			t.Errorf("Format() = %q, want %q", output, "2 results")
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, err := NewTemplateFormatter("{{.Query"); err == nil {
+			t.Error("NewTemplateFormatter() expected parse error")
+		}
+		if _, err := NewTemplateFormatterFromFile(filepath.Join(t.TempDir(), "missing.tmpl")); err == nil {
+			t.Error("NewTemplateFormatterFromFile() expected error for missing file")
+		}
+		tf, _ := NewTemplateFormatter("{{.NoSuchField}}")
+		if _, err := tf.Format(response); err == nil {
+			t.Error("Format() expected execution error")
+		}
+		if _, err := tf.Format(nil); err == nil {
+			t.Error("Format(nil) expected error")
+		}
+	})
+}
@@ -2,7 +2,10 @@ package formatter
 
 import (
 	"fmt"
+	"io"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/mule-ai/search/internal/searxng"
 )
@@ -10,7 +13,35 @@ import (
 // TextFormatter formats search results as plain text.
 type TextFormatter struct {
 	BaseFormatter
-	NoColor bool // Disable colored output
+	NoColor        bool   // Disable colored output
+	FieldSeparator string // Emit one line per result joined by this separator
+	PrettyURLs     bool   // Show shortened URLs, with the full ones listed after the results
+	Explain        bool   // Add a dim line per result with its raw ranking signals
+	Summary        bool   // Lead with answers and infoboxes, then the top few results
+	NoWrap         bool   // Emit each content line whole instead of truncating and wrapping it
+}
+
+// namedSeparators maps the separator names accepted by --field-separator
+// to the literal string they stand for.
+var namedSeparators = map[string]string{
+	"tab":       "\t",
+	"pipe":      "|",
+	"comma":     ",",
+	"semicolon": ";",
+	"space":     " ",
+}
+
+// ResolveFieldSeparator converts a separator name like "tab" or "pipe"
+// into its literal form. Any other value is returned unchanged.
+//
+// Example:
+//
+//	sep := formatter.ResolveFieldSeparator("pipe") // "|"
+func ResolveFieldSeparator(name string) string {
+	if sep, ok := namedSeparators[strings.ToLower(name)]; ok {
+		return sep
+	}
+	return name
 }
 
 // NewTextFormatter creates a new text formatter.
@@ -51,8 +82,32 @@ func NewTextFormatter(noColor bool) *TextFormatter {
 //	}
 //	fmt.Println(output)
 func (f *TextFormatter) Format(result *searxng.SearchResponse) (string, error) {
+	var buf strings.Builder
+	if err := f.StreamFormat(&buf, result); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// StreamFormat writes the search results as plain text to w.
+//
+// Unlike Format, output is written as soon as each result is rendered,
+// so the full formatted string is never held in memory. The output is
+// byte-for-byte identical to Format.
+//
+// Example:
+//
+//	tf := formatter.NewTextFormatter(false)
+//	if err := tf.StreamFormat(os.Stdout, response); err != nil {
+//	    log.Fatal(err)
+//	}
+func (f *TextFormatter) StreamFormat(w io.Writer, result *searxng.SearchResponse) error {
 	if result == nil {
-		return "", fmt.Errorf("nil response provided")
+		return fmt.Errorf("nil response provided")
+	}
+
+	if f.FieldSeparator != "" {
+		return f.streamFields(w, result)
 	}
 
 	var buf strings.Builder
@@ -61,16 +116,17 @@ func (f *TextFormatter) Format(result *searxng.SearchResponse) (string, error) {
 	buf.WriteString(fmt.Sprintf("%s\n", result.Query))
 	buf.WriteString(strings.Repeat("=", len(result.Query)) + "\n\n")
 
-	// Display results count - use NumberOfResults if available, otherwise use count of returned results
-	totalResults := result.NumberOfResults
-	if totalResults == 0 {
-		totalResults = len(result.Results)
+	if result.OriginalQuery != "" && result.OriginalQuery != result.Query {
+		buf.WriteString(fmt.Sprintf("Showing results for %s (searched: %s)\n\n",
+			f.colorize(result.OriginalQuery, "bold"), result.Query))
 	}
 
+	totalResults := searxng.EffectiveTotal(result)
+
 	if len(result.Results) == 0 {
 		buf.WriteString("No results found.\n\n")
 	} else {
-		buf.WriteString(fmt.Sprintf("Found %d results in %.2fs", totalResults, result.SearchTime))
+		buf.WriteString(fmt.Sprintf("Found %s results in %.2fs", f.formatInt(totalResults), result.SearchTime))
 
 		// Add page info if not on first page
 		if result.Page > 1 {
@@ -79,20 +135,69 @@ func (f *TextFormatter) Format(result *searxng.SearchResponse) (string, error) {
 
 		buf.WriteString("\n\n")
 	}
+	// In summary mode answers and infoboxes come first, and only the top
+	// results follow them
+	results := result.Results
+	summary := f.Summary && hasSummary(result)
+	if summary {
+		var lead strings.Builder
+		f.writeAnswers(&lead, result)
+		buf.WriteString(strings.TrimPrefix(lead.String(), "\n") + "\n")
+		results = results[:min(len(results), summaryResults)]
+	}
+	if err := flush(w, &buf); err != nil {
+		return err
+	}
+
+	// Results - each one is written out as soon as it is rendered
+	index := f.StartIndex
+	var shown []searxng.SearchResult
+	for si, section := range f.resultSections(results) {
+		if section.Heading != "" {
+			if si > 0 {
+				buf.WriteString("\n")
+			}
+			buf.WriteString(fmt.Sprintf("## %s\n\n", section.Heading))
+		}
+		for i, res := range section.Results {
+			f.formatResult(&buf, res, index)
+			index++
+			shown = append(shown, res)
+			if i < len(section.Results)-1 {
+				buf.WriteString("\n")
+			}
+			if err := flush(w, &buf); err != nil {
+				return err
+			}
+		}
+	}
 
-	// Results
-	for i, res := range result.Results {
-		f.formatResult(&buf, res, i)
-		if i < len(result.Results)-1 {
-			buf.WriteString("\n")
+	if f.PrettyURLs && len(shown) > 0 {
+		writeLinks(&buf, shown, f.StartIndex)
+	}
+
+	if !summary {
+		f.writeAnswers(&buf, result)
+	}
+
+	// Suggestions
+	if len(result.Suggestions) > 0 {
+		buf.WriteString("\n## Suggestions\n\n")
+		for _, suggestion := range result.Suggestions {
+			buf.WriteString(fmt.Sprintf("- %s\n", suggestion))
 		}
 	}
 
+	return flush(w, &buf)
+}
+
+// writeAnswers writes the answers and infoboxes sections.
+func (f *TextFormatter) writeAnswers(buf *strings.Builder, result *searxng.SearchResponse) {
 	// Answers
 	if len(result.Answers) > 0 {
 		buf.WriteString("\n## Answers\n\n")
 		for _, answer := range result.Answers {
-			buf.WriteString(fmt.Sprintf("- %s\n", answer))
+			buf.WriteString(fmt.Sprintf("- %s\n", answer.Answer))
 		}
 	}
 
@@ -138,16 +243,25 @@ func (f *TextFormatter) Format(result *searxng.SearchResponse) (string, error) {
 			}
 		}
 	}
+}
 
-	// Suggestions
-	if len(result.Suggestions) > 0 {
-		buf.WriteString("\n## Suggestions\n\n")
-		for _, suggestion := range result.Suggestions {
-			buf.WriteString(fmt.Sprintf("- %s\n", suggestion))
+// streamFields writes one line per result in the form
+// index<sep>title<sep>url<sep>score, with no header or trailing sections.
+func (f *TextFormatter) streamFields(w io.Writer, result *searxng.SearchResponse) error {
+	var buf strings.Builder
+	for i, res := range result.Results {
+		fields := []string{
+			fmt.Sprintf("%d", f.StartIndex+i+1),
+			f.colorize(res.Title, "bold"),
+			res.URL,
+			fmt.Sprintf("%.2f", res.Score),
+		}
+		buf.WriteString(strings.Join(fields, f.FieldSeparator) + "\n")
+		if err := flush(w, &buf); err != nil {
+			return err
 		}
 	}
-
-	return buf.String(), nil
+	return nil
 }
 
 func (f *TextFormatter) formatResult(buf *strings.Builder, result searxng.SearchResult, index int) {
@@ -156,7 +270,7 @@ func (f *TextFormatter) formatResult(buf *strings.Builder, result searxng.Search
 	buf.WriteString(title + "\n")
 
 	// URL
-	buf.WriteString(fmt.Sprintf("    %s\n", result.URL))
+	buf.WriteString(fmt.Sprintf("    %s\n", f.displayURL(result.URL)))
 
 	// Source and score
 	var sourceInfo strings.Builder
@@ -171,24 +285,97 @@ func (f *TextFormatter) formatResult(buf *strings.Builder, result searxng.Search
 		buf.WriteString(fmt.Sprintf("    Category: %s\n", result.Category))
 	}
 
+	if f.Explain {
+		buf.WriteString(f.colorize("    "+explainResult(result), "dim") + "\n")
+	}
+
 	// Content
 	if len(result.Content) > 0 {
 		buf.WriteString("\n")
 
 		// Truncate content if too long
 		content := result.Content
-		if len(content) > f.Width-8 {
-			content = content[:f.Width-11] + "..."
+		if !f.NoWrap {
+			content = truncateContent(content, f.Width-8)
 		}
 
-		lines := strings.Split(content, "\n")
-		for _, line := range lines {
-			wrapped := f.MaxWidth(line, f.Width-8)
-			for _, wline := range wrapped {
-				buf.WriteString("    " + wline + "\n")
+		var wrapped []string
+		for _, line := range strings.Split(content, "\n") {
+			if f.NoWrap {
+				wrapped = append(wrapped, line)
+				continue
 			}
+			wrapped = append(wrapped, f.MaxWidth(line, f.Width-8)...)
+		}
+		for _, wline := range f.LimitLines(wrapped) {
+			buf.WriteString("    " + wline + "\n")
+		}
+	}
+}
+
+// truncateContent shortens content to at most width runes, ending it with
+// "..." when cut. The cut falls on a rune boundary, so multi-byte text
+// stays valid UTF-8.
+func truncateContent(content string, width int) string {
+	if utf8.RuneCountInString(content) <= width {
+		return content
+	}
+	cut := width - 3
+	if cut <= 0 {
+		return "..."
+	}
+	return string([]rune(content)[:cut]) + "..."
+}
+
+// explainResult describes the signals behind a result's rank: the score the
+// engines gave it, before any normalization, the engines that returned it
+// and its category.
+func explainResult(result searxng.SearchResult) string {
+	score := result.Score
+	if result.OriginalScore != nil {
+		score = *result.OriginalScore
+	}
+	engines := result.Engines
+	if len(engines) == 0 && result.Engine != "" {
+		engines = []string{result.Engine}
+	}
+	category := result.Category
+	if category == "" {
+		category = "-"
+	}
+	return fmt.Sprintf("Explain: raw score %.4f | engines: %s | category: %s",
+		score, strings.Join(engines, ", "), category)
+}
+
+// FormatDiff formats the changes between two runs of a search, as
+// returned by searxng.DiffResults, diff-style: each added result marked
+// with a green "+" and each removed one with a red "-", title first and
+// URL below. Pass nil removed to show only new results. No changes give
+// an empty string.
+//
+// Example:
+//
+//	added, removed := searxng.DiffResults(previous.Results, current.Results)
+//	fmt.Print(tf.FormatDiff(added, removed))
+func (f *TextFormatter) FormatDiff(added, removed []searxng.SearchResult) string {
+	var buf strings.Builder
+	write := func(results []searxng.SearchResult, sign, style string) {
+		for _, r := range results {
+			buf.WriteString(f.colorize(sign, style) + " " + f.colorize(r.Title, "bold") + "\n")
+			buf.WriteString("  " + f.displayURL(r.URL) + "\n")
 		}
 	}
+	write(added, "+", "green")
+	write(removed, "-", "red")
+	return buf.String()
+}
+
+// displayURL returns u shortened with PrettyURL when PrettyURLs is set.
+func (f *TextFormatter) displayURL(u string) string {
+	if !f.PrettyURLs {
+		return u
+	}
+	return PrettyURL(u, prettyURLWidth)
 }
 
 // colorize adds ANSI color codes to text
@@ -199,6 +386,7 @@ func (f *TextFormatter) colorize(text string, style string) string {
 
 	codes := map[string]string{
 		"bold":     "\033[1m",
+		"dim":      "\033[2m",
 		"red":      "\033[31m",
 		"green":    "\033[32m",
 		"yellow":   "\033[33m",
@@ -229,9 +417,7 @@ func (f *TextFormatter) FormatResult(result searxng.SearchResult, index int) str
 
 	if len(result.Content) > 0 {
 		content := result.Content
-		if len(content) > f.Width-8 {
-			content = content[:f.Width-11] + "..."
-		}
+		content = truncateContent(content, f.Width-8)
 		lines := strings.Split(content, "\n")
 		for _, line := range lines {
 			wrapped := f.MaxWidth(line, f.Width-8)
@@ -252,7 +438,7 @@ func (f *TextFormatter) FormatResults(results []searxng.SearchResult, query stri
 	buf.WriteString(strings.Repeat("=", len(query)) + "\n\n")
 
 	if total > 0 {
-		buf.WriteString(fmt.Sprintf("Found %d results in %.2fs\n\n", total, searchTime))
+		buf.WriteString(fmt.Sprintf("Found %s results in %.2fs\n\n", f.formatInt(total), searchTime))
 	}
 
 	for i, res := range results {
@@ -273,7 +459,7 @@ func (f *TextFormatter) FormatWithQuery(query string, results []searxng.SearchRe
 	buf.WriteString(strings.Repeat("=", len(query)) + "\n\n")
 
 	if total > 0 {
-		buf.WriteString(fmt.Sprintf("Found %d results in %.2fs\n\n", total, searchTime))
+		buf.WriteString(fmt.Sprintf("Found %s results in %.2fs\n\n", f.formatInt(total), searchTime))
 	}
 
 	for i, res := range results {
@@ -294,7 +480,7 @@ func (f *TextFormatter) FormatWithAnswers(query string, results []searxng.Search
 	buf.WriteString(strings.Repeat("=", len(query)) + "\n\n")
 
 	if len(results) > 0 {
-		buf.WriteString(fmt.Sprintf("Found %d results\n\n", len(results)))
+		buf.WriteString(fmt.Sprintf("Found %s results\n\n", f.formatInt(len(results))))
 	}
 
 	for i, res := range results {
@@ -322,7 +508,7 @@ func (f *TextFormatter) FormatWithInfoboxes(query string, results []searxng.Sear
 	buf.WriteString(strings.Repeat("=", len(query)) + "\n\n")
 
 	if len(results) > 0 {
-		buf.WriteString(fmt.Sprintf("Found %d results\n\n", len(results)))
+		buf.WriteString(fmt.Sprintf("Found %s results\n\n", f.formatInt(len(results))))
 	}
 
 	for i, res := range results {
@@ -350,7 +536,7 @@ func (f *TextFormatter) FormatWithSuggestions(query string, results []searxng.Se
 	buf.WriteString(strings.Repeat("=", len(query)) + "\n\n")
 
 	if len(results) > 0 {
-		buf.WriteString(fmt.Sprintf("Found %d results\n\n", len(results)))
+		buf.WriteString(fmt.Sprintf("Found %s results\n\n", f.formatInt(len(results))))
 	}
 
 	for i, res := range results {
@@ -378,7 +564,7 @@ func (f *TextFormatter) FormatFull(query string, results []searxng.SearchResult,
 	buf.WriteString(strings.Repeat("=", len(query)) + "\n\n")
 
 	if len(results) > 0 {
-		buf.WriteString(fmt.Sprintf("Found %d results\n\n", len(results)))
+		buf.WriteString(fmt.Sprintf("Found %s results\n\n", f.formatInt(len(results))))
 	}
 
 	for i, res := range results {
@@ -418,7 +604,7 @@ func (f *TextFormatter) FormatHeader(query string, total int, searchTime float64
 	buf.WriteString(fmt.Sprintf("%s\n", query))
 	buf.WriteString(strings.Repeat("=", len(query)) + "\n\n")
 	if total > 0 {
-		buf.WriteString(fmt.Sprintf("Found %d results in %.2fs\n\n", total, searchTime))
+		buf.WriteString(fmt.Sprintf("Found %s results in %.2fs\n\n", f.formatInt(total), searchTime))
 	}
 	return buf.String()
 }
@@ -430,37 +616,105 @@ func (f *TextFormatter) FormatFooter(query string, total int, searchTime float64
 	return buf.String()
 }
 
-// FormatResultsTable formats results as a table
+// tableTitleWidth is the widest a title column may grow before titles
+// are truncated with an ellipsis.
+const tableTitleWidth = 50
+
+// FormatResultsTable formats results as an aligned table.
+//
+// Column widths are computed from the data, long titles are truncated
+// with an ellipsis and scores are right-aligned. The header row is bold
+// unless colors are disabled. With PrettyURLs, a column of shortened URLs
+// is added and the full URLs are listed below the table.
 func (f *TextFormatter) FormatResultsTable(results []searxng.SearchResult, query string, total int) string {
 	var buf strings.Builder
 	buf.WriteString(fmt.Sprintf("%s\n", query))
 	buf.WriteString(strings.Repeat("=", len(query)) + "\n\n")
 	if total > 0 {
-		buf.WriteString(fmt.Sprintf("Found %d results\n\n", total))
+		buf.WriteString(fmt.Sprintf("Found %s results\n\n", f.formatInt(total)))
 	}
 
 	if len(results) == 0 {
 		return buf.String()
 	}
 
-	buf.WriteString(fmt.Sprintf("%-3s %-50s %-20s %8s\n", "#", "Title", "Source", "Score"))
-	buf.WriteString(strings.Repeat("-", 80) + "\n")
-
+	headers := []string{"#", "Title", "Source", "Score"}
+	if f.PrettyURLs {
+		headers = []string{"#", "Title", "URL", "Source", "Score"}
+	}
+	rows := make([][]string, len(results))
+	widths := make([]int, len(headers))
+	for i, h := range headers {
+		widths[i] = utf8.RuneCountInString(h)
+	}
 	for i, res := range results {
-		title := f.TruncateWithEllipsis(res.Title, 50)
-		buf.WriteString(fmt.Sprintf("%-3d %-50s %-20s %8.2f\n", i+1, title, res.Engine, res.Score))
+		rows[i] = []string{
+			strconv.Itoa(f.StartIndex + i + 1),
+			f.TruncateWithEllipsis(res.Title, tableTitleWidth),
+		}
+		if f.PrettyURLs {
+			rows[i] = append(rows[i], PrettyURL(res.URL, prettyURLWidth))
+		}
+		rows[i] = append(rows[i], res.Engine, fmt.Sprintf("%.2f", res.Score))
+		for j, cell := range rows[i] {
+			if n := utf8.RuneCountInString(cell); n > widths[j] {
+				widths[j] = n
+			}
+		}
+	}
+
+	// Every column is left-aligned except the score
+	last := len(headers) - 1
+	writeRow := func(cells []string, style string) {
+		var line strings.Builder
+		for j, cell := range cells[:last] {
+			line.WriteString(padRight(cell, widths[j]) + "  ")
+		}
+		line.WriteString(padLeft(cells[last], widths[last]))
+		buf.WriteString(f.colorize(strings.TrimRight(line.String(), " "), style) + "\n")
+	}
+
+	lineWidth := 2 * last
+	for _, w := range widths {
+		lineWidth += w
+	}
+
+	writeRow(headers, "bold")
+	buf.WriteString(strings.Repeat("-", lineWidth) + "\n")
+	for _, row := range rows {
+		writeRow(row, "")
+	}
+
+	if f.PrettyURLs {
+		writeLinks(&buf, results, f.StartIndex)
 	}
 
 	return buf.String()
 }
 
+// padRight pads s with spaces on the right to width runes.
+func padRight(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// padLeft pads s with spaces on the left to width runes.
+func padLeft(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return strings.Repeat(" ", width-n) + s
+	}
+	return s
+}
+
 // FormatSimple formats results with just title and URL
 func (f *TextFormatter) FormatSimple(results []searxng.SearchResult, query string, total int) string {
 	var buf strings.Builder
 	buf.WriteString(fmt.Sprintf("%s\n", query))
 	buf.WriteString(strings.Repeat("=", len(query)) + "\n\n")
 	if total > 0 {
-		buf.WriteString(fmt.Sprintf("Found %d results\n\n", total))
+		buf.WriteString(fmt.Sprintf("Found %s results\n\n", f.formatInt(total)))
 	}
 
 	for i, res := range results {
@@ -524,7 +778,7 @@ func (f *TextFormatter) FormatWithAnswersAndInfoboxes(query string, results []se
 	buf.WriteString(strings.Repeat("=", len(query)) + "\n\n")
 
 	if len(results) > 0 {
-		buf.WriteString(fmt.Sprintf("Found %d results\n\n", len(results)))
+		buf.WriteString(fmt.Sprintf("Found %s results\n\n", f.formatInt(len(results))))
 	}
 
 	for i, res := range results {
@@ -558,7 +812,7 @@ func (f *TextFormatter) FormatWithAnswersAndSuggestions(query string, results []
 	buf.WriteString(strings.Repeat("=", len(query)) + "\n\n")
 
 	if len(results) > 0 {
-		buf.WriteString(fmt.Sprintf("Found %d results\n\n", len(results)))
+		buf.WriteString(fmt.Sprintf("Found %s results\n\n", f.formatInt(len(results))))
 	}
 
 	for i, res := range results {
@@ -592,7 +846,7 @@ func (f *TextFormatter) FormatWithInfoboxesAndSuggestions(query string, results
 	buf.WriteString(strings.Repeat("=", len(query)) + "\n\n")
 
 	if len(results) > 0 {
-		buf.WriteString(fmt.Sprintf("Found %d results\n\n", len(results)))
+		buf.WriteString(fmt.Sprintf("Found %s results\n\n", f.formatInt(len(results))))
 	}
 
 	for i, res := range results {
@@ -626,7 +880,7 @@ func (f *TextFormatter) FormatWithAllMetadata(query string, results []searxng.Se
 	buf.WriteString(strings.Repeat("=", len(query)) + "\n\n")
 
 	if len(results) > 0 {
-		buf.WriteString(fmt.Sprintf("Found %d results\n\n", len(results)))
+		buf.WriteString(fmt.Sprintf("Found %s results\n\n", f.formatInt(len(results))))
 	}
 
 	for i, res := range results {
@@ -683,7 +937,7 @@ func (f *TextFormatter) FormatCompact(results []searxng.SearchResult, query stri
 	buf.WriteString(fmt.Sprintf("%s\n", query))
 	buf.WriteString(strings.Repeat("=", len(query)) + "\n\n")
 	if total > 0 {
-		buf.WriteString(fmt.Sprintf("Found %d results\n\n", total))
+		buf.WriteString(fmt.Sprintf("Found %s results\n\n", f.formatInt(total)))
 	}
 
 	for i, res := range results {
@@ -704,7 +958,7 @@ func (f *TextFormatter) FormatWithSearchTime(query string, results []searxng.Sea
 	buf.WriteString(fmt.Sprintf("%s\n", query))
 	buf.WriteString(strings.Repeat("=", len(query)) + "\n\n")
 	if len(results) > 0 {
-		buf.WriteString(fmt.Sprintf("Found %d results in %.2fs\n\n", len(results), searchTime))
+		buf.WriteString(fmt.Sprintf("Found %s results in %.2fs\n\n", f.formatInt(len(results)), searchTime))
 	}
 
 	for i, res := range results {
@@ -723,7 +977,7 @@ func (f *TextFormatter) FormatWithInstance(query string, results []searxng.Searc
 	buf.WriteString(fmt.Sprintf("%s\n", query))
 	buf.WriteString(strings.Repeat("=", len(query)) + "\n\n")
 	if len(results) > 0 {
-		buf.WriteString(fmt.Sprintf("Found %d results\n\n", len(results)))
+		buf.WriteString(fmt.Sprintf("Found %s results\n\n", f.formatInt(len(results))))
 		buf.WriteString(fmt.Sprintf("* Instance: %s\n", instance))
 	}
 
@@ -743,7 +997,7 @@ func (f *TextFormatter) FormatWithAllOptions(query string, results []searxng.Sea
 	buf.WriteString(fmt.Sprintf("%s\n", query))
 	buf.WriteString(strings.Repeat("=", len(query)) + "\n\n")
 	if len(results) > 0 {
-		buf.WriteString(fmt.Sprintf("Found %d results in %.2fs\n\n", len(results), searchTime))
+		buf.WriteString(fmt.Sprintf("Found %s results in %.2fs\n\n", f.formatInt(len(results)), searchTime))
 	}
 
 	for i, res := range results {
@@ -783,7 +1037,7 @@ func (f *TextFormatter) FormatWithResultCount(query string, results []searxng.Se
 	buf.WriteString(fmt.Sprintf("%s\n", query))
 	buf.WriteString(strings.Repeat("=", len(query)) + "\n\n")
 	if total > 0 {
-		buf.WriteString(fmt.Sprintf("Found %d results\n\n", total))
+		buf.WriteString(fmt.Sprintf("Found %s results\n\n", f.formatInt(total)))
 	}
 
 	for i, res := range results {
@@ -802,7 +1056,7 @@ func (f *TextFormatter) FormatWithSearchTimeAndInstance(query string, results []
 	buf.WriteString(fmt.Sprintf("%s\n", query))
 	buf.WriteString(strings.Repeat("=", len(query)) + "\n\n")
 	if len(results) > 0 {
-		buf.WriteString(fmt.Sprintf("Found %d results in %.2fs\n\n", len(results), searchTime))
+		buf.WriteString(fmt.Sprintf("Found %s results in %.2fs\n\n", f.formatInt(len(results)), searchTime))
 		buf.WriteString(fmt.Sprintf("* Instance: %s\n", instance))
 	}
 
@@ -822,7 +1076,7 @@ func (f *TextFormatter) FormatWithAll(query string, results []searxng.SearchResu
 	buf.WriteString(fmt.Sprintf("%s\n", query))
 	buf.WriteString(strings.Repeat("=", len(query)) + "\n\n")
 	if total > 0 {
-		buf.WriteString(fmt.Sprintf("Found %d results in %.2fs\n\n", total, searchTime))
+		buf.WriteString(fmt.Sprintf("Found %s results in %.2fs\n\n", f.formatInt(total), searchTime))
 	}
 
 	for i, res := range results {
@@ -870,7 +1124,7 @@ func (f *TextFormatter) FormatResponseWithAll(result *searxng.SearchResponse, in
 		answerStrings,
 		result.Infoboxes,
 		result.Suggestions,
-		result.NumberOfResults,
+		searxng.EffectiveTotal(result),
 		result.SearchTime,
 	)
 }
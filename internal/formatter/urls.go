@@ -0,0 +1,64 @@
+package formatter
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/mule-ai/search/internal/searxng"
+)
+
+// prettyURLWidth is the longest a URL may be shown with --prettify-urls
+// before it is shortened.
+const prettyURLWidth = 50
+
+// PrettyURL shortens u to at most maxLen bytes for display, keeping the
+// scheme and host and cutting the path with an ellipsis. The query and
+// fragment are dropped first. The host is never cut, so the result can
+// be longer than maxLen for very long hosts. URLs that already fit, or
+// a maxLen of 0 or less, are returned unchanged.
+//
+// Example:
+//
+//	formatter.PrettyURL("https://go.dev/doc/tutorial/getting-started?utm_source=x", 30)
+//	// "https://go.dev/doc/tutorial..."
+func PrettyURL(u string, maxLen int) string {
+	if maxLen <= 0 || len(u) <= maxLen {
+		return u
+	}
+
+	const ellipsis = "..."
+	parsed, err := url.Parse(u)
+	if err != nil || parsed.Host == "" {
+		if maxLen <= len(ellipsis) {
+			return u[:maxLen]
+		}
+		return u[:maxLen-len(ellipsis)] + ellipsis
+	}
+
+	head := parsed.Host
+	if parsed.Scheme != "" {
+		head = parsed.Scheme + "://" + head
+	}
+	path := strings.TrimSuffix(parsed.EscapedPath(), "/")
+
+	budget := maxLen - len(head) - len(ellipsis)
+	if budget <= 0 {
+		return head + ellipsis
+	}
+	if len(path) > budget {
+		path = path[:budget]
+	}
+	return head + path + ellipsis
+}
+
+// writeLinks writes the full URL of each result, numbered like the
+// results above it from start+1, so shortened URLs can still be copied.
+func writeLinks(buf *strings.Builder, results []searxng.SearchResult, start int) {
+	width := len(strconv.Itoa(start + len(results)))
+	buf.WriteString("\n## Links\n\n")
+	for i, res := range results {
+		buf.WriteString(fmt.Sprintf("[%*d] %s\n", width, start+i+1, res.URL))
+	}
+}
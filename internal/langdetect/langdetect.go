@@ -0,0 +1,201 @@
+// Package langdetect guesses the language of short texts such as search
+// result snippets, without network access.
+//
+// Texts in a script used by one language (or a close family), such as
+// Hangul or Greek, are identified by their script. Latin-script texts
+// are compared against character trigram profiles of a few common
+// languages using the out-of-place rank distance of Cavnar and Trenkle.
+package langdetect
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// minLetters is the fewest letters Detect needs before it will guess;
+// shorter texts have too few trigrams to tell languages apart.
+const minLetters = 20
+
+// profileSize is how many of the most frequent trigrams are compared.
+const profileSize = 300
+
+// scriptLanguages are the languages Detect identifies by script alone.
+var scriptLanguages = []string{"ar", "el", "he", "hi", "ja", "ko", "ru", "th", "uk", "zh"}
+
+var (
+	profilesOnce sync.Once
+	profiles     map[string]map[string]int // language -> trigram -> rank
+)
+
+// Detect returns the ISO 639-1 code of the language text is most likely
+// written in, such as "en" or "de", or "" when the text is too short or
+// its language is not one Detect knows.
+//
+// Example:
+//
+//	langdetect.Detect("Alle Menschen sind frei und gleich an Würde und Rechten geboren") // "de"
+func Detect(text string) string {
+	if lang := detectScript(text); lang != "" {
+		return lang
+	}
+
+	counts, letters := trigramCounts(text)
+	if letters < minLetters {
+		return ""
+	}
+	ranked := rankTrigrams(counts)
+
+	profilesOnce.Do(buildProfiles)
+	best, bestDistance := "", -1
+	for lang, profile := range profiles {
+		distance := 0
+		for rank, gram := range ranked {
+			if r, ok := profile[gram]; ok {
+				distance += abs(rank - r)
+			} else {
+				distance += profileSize
+			}
+		}
+		if bestDistance < 0 || distance < bestDistance || (distance == bestDistance && lang < best) {
+			best, bestDistance = lang, distance
+		}
+	}
+	return best
+}
+
+// Supported returns the language codes Detect can return, sorted.
+func Supported() []string {
+	langs := append([]string(nil), scriptLanguages...)
+	for lang := range samples {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// IsSupported reports whether Detect can return lang.
+func IsSupported(lang string) bool {
+	for _, l := range Supported() {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// detectScript returns the language for text whose letters are mostly
+// in a script other than Latin, or "" otherwise.
+func detectScript(text string) string {
+	var latin, other int
+	counts := make(map[string]int)
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		lang := ""
+		switch {
+		case unicode.Is(unicode.Latin, r):
+			latin++
+			continue
+		case unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r):
+			lang = "ja"
+		case unicode.Is(unicode.Hangul, r):
+			lang = "ko"
+		case unicode.Is(unicode.Han, r):
+			lang = "zh"
+		case strings.ContainsRune("іїєґІЇЄҐ", r):
+			lang = "uk"
+		case unicode.Is(unicode.Cyrillic, r):
+			lang = "ru"
+		case unicode.Is(unicode.Greek, r):
+			lang = "el"
+		case unicode.Is(unicode.Arabic, r):
+			lang = "ar"
+		case unicode.Is(unicode.Hebrew, r):
+			lang = "he"
+		case unicode.Is(unicode.Devanagari, r):
+			lang = "hi"
+		case unicode.Is(unicode.Thai, r):
+			lang = "th"
+		}
+		counts[lang]++
+		other++
+	}
+	if other == 0 || other < latin {
+		return ""
+	}
+
+	// Kana marks Japanese even though most of its characters are Han,
+	// and Ukrainian letters mark Ukrainian among Cyrillic text
+	switch {
+	case counts["ja"] > 0:
+		return "ja"
+	case counts["uk"] > 0:
+		return "uk"
+	}
+	best := ""
+	for lang, n := range counts {
+		if lang != "" && (best == "" || n > counts[best] || (n == counts[best] && lang < best)) {
+			best = lang
+		}
+	}
+	return best
+}
+
+// trigramCounts counts the letter trigrams in text, with each word
+// padded by a space on both sides, and returns them with the number of
+// letters seen.
+func trigramCounts(text string) (map[string]int, int) {
+	counts := make(map[string]int)
+	letters := 0
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		runes := []rune(" " + word + " ")
+		letters += len(runes) - 2
+		for i := 0; i+3 <= len(runes); i++ {
+			counts[string(runes[i:i+3])]++
+		}
+	}
+	return counts, letters
+}
+
+// rankTrigrams returns up to profileSize trigrams, most frequent first.
+func rankTrigrams(counts map[string]int) []string {
+	grams := make([]string, 0, len(counts))
+	for gram := range counts {
+		grams = append(grams, gram)
+	}
+	sort.Slice(grams, func(i, j int) bool {
+		if counts[grams[i]] != counts[grams[j]] {
+			return counts[grams[i]] > counts[grams[j]]
+		}
+		return grams[i] < grams[j]
+	})
+	if len(grams) > profileSize {
+		grams = grams[:profileSize]
+	}
+	return grams
+}
+
+// buildProfiles ranks the trigrams of each sample.
+func buildProfiles() {
+	profiles = make(map[string]map[string]int, len(samples))
+	for lang, sample := range samples {
+		counts, _ := trigramCounts(sample)
+		profile := make(map[string]int)
+		for rank, gram := range rankTrigrams(counts) {
+			profile[gram] = rank
+		}
+		profiles[lang] = profile
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,52 @@
+package langdetect
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Go is an open source programming language that makes it simple to build secure, scalable systems.", "en"},
+		{"Go ist eine Programmiersprache, die von Google entwickelt wurde und sich für nebenläufige Anwendungen eignet.", "de"},
+		{"Go est un langage de programmation compilé et concurrent inspiré de C et Pascal, développé par Google.", "fr"},
+		{"Go es un lenguaje de programación concurrente y compilado inspirado en la sintaxis de C, desarrollado por Google.", "es"},
+		{"Go è un linguaggio di programmazione open source sviluppato da Google, pensato per essere semplice ed efficiente.", "it"},
+		{"Go é uma linguagem de programação criada pela Google e lançada em código livre, com uma sintaxe simples.", "pt"},
+		{"Go is een programmeertaal die door Google is ontwikkeld en die het makkelijk maakt om snelle programma's te schrijven.", "nl"},
+		{"Go är ett programspråk som utvecklats av Google och som gör det enkelt att bygga snabba och säkra program.", "sv"},
+		{"Go to język programowania opracowany przez firmę Google, który pozwala łatwo pisać szybkie i bezpieczne programy.", "pl"},
+		{"Go — компилируемый многопоточный язык программирования, разработанный внутри компании Google.", "ru"},
+		{"Go — компільована багатопотокова мова програмування, розроблена в компанії Google.", "uk"},
+		{"Goは、Googleによって開発されたプログラミング言語です。", "ja"},
+		{"Go是Google开发的一种静态强类型、编译型编程语言。", "zh"},
+		{"Go는 구글이 개발한 프로그래밍 언어이다.", "ko"},
+		{"Η Go είναι μια γλώσσα προγραμματισμού που αναπτύχθηκε από την Google.", "el"},
+		{"golang", ""},
+		{"", ""},
+		{"12345 !!! ???", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Detect(tt.text); got != tt.want {
+			t.Errorf("Detect(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSupported(t *testing.T) {
+	langs := Supported()
+	for i := 1; i < len(langs); i++ {
+		if langs[i-1] >= langs[i] {
+			t.Fatalf("Supported() not sorted: %v", langs)
+		}
+	}
+	for _, lang := range []string{"en", "de", "ja", "ru"} {
+		if !IsSupported(lang) {
+			t.Errorf("IsSupported(%q) = false", lang)
+		}
+	}
+	if IsSupported("xx") {
+		t.Error(`IsSupported("xx") = true`)
+	}
+}
@@ -0,0 +1,92 @@
+package langdetect
+
+// samples holds a short passage of ordinary prose for each Latin-script
+// language, heavy on the function words that dominate real text. The
+// trigram profiles are built from these when first needed.
+var samples = map[string]string{
+	"en": `All human beings are born free and equal in dignity and rights. They are
+endowed with reason and conscience and should act towards one another in a
+spirit of brotherhood. This is the first thing that you will notice when you
+read the text, and it is also what most of the people who write about it have
+said. There was a time when we could not find out how it would work, but now
+we know that the answer is in the way the system has been built. If you want
+to learn more about the language, you should start with the tutorial and then
+look at the documentation which explains how everything fits together.`,
+
+	"de": `Alle Menschen sind frei und gleich an Würde und Rechten geboren. Sie sind
+mit Vernunft und Gewissen begabt und sollen einander im Geist der
+Brüderlichkeit begegnen. Das ist das Erste, was man bemerkt, wenn man den Text
+liest, und es ist auch das, was die meisten Leute darüber geschrieben haben.
+Es gab eine Zeit, in der wir nicht herausfinden konnten, wie es funktioniert,
+aber jetzt wissen wir, dass die Antwort in der Art liegt, wie das System
+gebaut wurde. Wenn Sie mehr über die Sprache lernen wollen, sollten Sie mit
+der Einführung beginnen und sich dann die Dokumentation ansehen, die erklärt,
+wie alles zusammenpasst.`,
+
+	"fr": `Tous les êtres humains naissent libres et égaux en dignité et en droits. Ils
+sont doués de raison et de conscience et doivent agir les uns envers les
+autres dans un esprit de fraternité. C'est la première chose que l'on remarque
+quand on lit le texte, et c'est aussi ce que la plupart des gens qui en
+parlent ont dit. Il y avait un temps où nous ne pouvions pas savoir comment
+cela fonctionnerait, mais maintenant nous savons que la réponse se trouve dans
+la façon dont le système a été construit. Si vous voulez en apprendre plus sur
+le langage, vous devriez commencer par le tutoriel et ensuite lire la
+documentation qui explique comment tout s'assemble.`,
+
+	"es": `Todos los seres humanos nacen libres e iguales en dignidad y derechos y,
+dotados como están de razón y conciencia, deben comportarse fraternalmente los
+unos con los otros. Es lo primero que se nota cuando se lee el texto, y
+también es lo que la mayoría de las personas que escriben sobre ello han
+dicho. Hubo un tiempo en que no podíamos saber cómo funcionaría, pero ahora
+sabemos que la respuesta está en la forma en que se ha construido el sistema.
+Si quieres aprender más sobre el lenguaje, deberías empezar con el tutorial y
+luego mirar la documentación que explica cómo encaja todo.`,
+
+	"it": `Tutti gli esseri umani nascono liberi ed eguali in dignità e diritti. Essi
+sono dotati di ragione e di coscienza e devono agire gli uni verso gli altri
+in spirito di fratellanza. È la prima cosa che si nota quando si legge il
+testo, ed è anche quello che la maggior parte delle persone che ne scrivono
+ha detto. C'era un tempo in cui non riuscivamo a capire come avrebbe
+funzionato, ma adesso sappiamo che la risposta sta nel modo in cui il sistema
+è stato costruito. Se vuoi imparare di più sul linguaggio, dovresti iniziare
+con la guida e poi guardare la documentazione che spiega come tutto si
+incastra.`,
+
+	"pt": `Todos os seres humanos nascem livres e iguais em dignidade e em direitos.
+Dotados de razão e de consciência, devem agir uns para com os outros em
+espírito de fraternidade. É a primeira coisa que se nota quando se lê o
+texto, e também é o que a maioria das pessoas que escrevem sobre isso tem
+dito. Houve um tempo em que não conseguíamos saber como iria funcionar, mas
+agora sabemos que a resposta está na forma como o sistema foi construído. Se
+você quer aprender mais sobre a linguagem, deve começar pelo tutorial e depois
+ver a documentação, que explica como tudo se encaixa.`,
+
+	"nl": `Alle mensen worden vrij en gelijk in waardigheid en rechten geboren. Zij
+zijn begiftigd met verstand en geweten, en behoren zich jegens elkander in
+een geest van broederschap te gedragen. Dat is het eerste wat je opvalt als
+je de tekst leest, en het is ook wat de meeste mensen die erover schrijven
+hebben gezegd. Er was een tijd dat we niet konden zien hoe het zou werken,
+maar nu weten we dat het antwoord ligt in de manier waarop het systeem is
+gebouwd. Als je meer over de taal wilt leren, moet je met de handleiding
+beginnen en daarna de documentatie bekijken die uitlegt hoe alles in elkaar
+past.`,
+
+	"sv": `Alla människor är födda fria och lika i värde och rättigheter. De har
+utrustats med förnuft och samvete och bör handla gentemot varandra i en anda
+av broderskap. Det är det första man märker när man läser texten, och det är
+också vad de flesta som skriver om det har sagt. Det fanns en tid då vi inte
+kunde ta reda på hur det skulle fungera, men nu vet vi att svaret ligger i
+hur systemet har byggts. Om du vill lära dig mer om språket bör du börja med
+handledningen och sedan titta på dokumentationen som förklarar hur allt
+hänger ihop.`,
+
+	"pl": `Wszyscy ludzie rodzą się wolni i równi pod względem swej godności i swych
+praw. Są oni obdarzeni rozumem i sumieniem i powinni postępować wobec innych
+w duchu braterstwa. To jest pierwsza rzecz, którą się zauważa, kiedy czyta
+się ten tekst, i to jest także to, co powiedziała większość ludzi, którzy o
+tym piszą. Był czas, kiedy nie mogliśmy się dowiedzieć, jak to będzie
+działać, ale teraz wiemy, że odpowiedź tkwi w sposobie, w jaki zbudowano
+system. Jeśli chcesz dowiedzieć się więcej o tym języku, zacznij od
+poradnika, a potem przejrzyj dokumentację, która wyjaśnia, jak wszystko do
+siebie pasuje.`,
+}
@@ -0,0 +1,77 @@
+package searxng
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mule-ai/search/internal/config"
+)
+
+func TestSign(t *testing.T) {
+	// echo -n '/search?format=json&q=golang' | openssl dgst -sha256 -hmac s3cret
+	got := Sign("s3cret", "/search?format=json&q=golang")
+	if want := "7f68a7a05d3f0fc9ec01545dfa196543e9998c64102dc5756199c1e7e85f5e11"; got != want {
+		t.Errorf("Sign() = %q, want %q", got, want)
+	}
+}
+
+func TestClientHMACAuth(t *testing.T) {
+	var headers http.Header
+	var uri string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		uri = r.URL.RequestURI()
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"query":"golang","results":[]}`)
+	}))
+	defer server.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.Instance = server.URL
+	cfg.APIKey = "bearer-key"
+	cfg.AuthType = config.AuthTypeHMAC
+	cfg.AuthSecret = "s3cret"
+	client := NewClient(cfg)
+
+	if _, err := client.Search(NewSearchRequest("golang")); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if got, want := headers.Get(config.DefaultAuthHeader), Sign("s3cret", uri); got != want {
+		t.Errorf("%s = %q, want %q for %s", config.DefaultAuthHeader, got, want, uri)
+	}
+	if got := headers.Get("Authorization"); got != "" {
+		t.Errorf("expected no bearer token with hmac auth, got %q", got)
+	}
+
+	// A configured header name replaces the default
+	client.SetHMACAuth("s3cret", "X-Instance-Auth")
+	if _, err := client.Search(NewSearchRequest("golang")); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if got := headers.Get("X-Instance-Auth"); got != Sign("s3cret", uri) {
+		t.Errorf("X-Instance-Auth = %q, want the signature", got)
+	}
+
+	// Without a secret the API key is sent as before
+	client.SetHMACAuth("", "")
+	if _, err := client.Search(NewSearchRequest("golang")); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if got := headers.Get("Authorization"); got != "Bearer bearer-key" {
+		t.Errorf("Authorization = %q, want the bearer token", got)
+	}
+	if got := headers.Get(config.DefaultAuthHeader); got != "" {
+		t.Errorf("expected no signature with bearer auth, got %q", got)
+	}
+}
+
+func TestClientRedactsHMACSecret(t *testing.T) {
+	client := NewClientWithTimeout("https://example.com", 0)
+	client.SetHMACAuth("hmac-secret-1234", "")
+	if got := client.redact("echo: hmac-secret-1234"); strings.Contains(got, "hmac-secret") {
+		t.Errorf("redact() = %q, still contains the secret", got)
+	}
+}
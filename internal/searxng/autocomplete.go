@@ -0,0 +1,112 @@
+package searxng
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/mule-ai/search/internal/errors"
+)
+
+// Autocomplete returns query completions for the partial query from the
+// instance's /autocompleter endpoint.
+//
+// An instance without an autocomplete backend configured returns no
+// completions and no error. An instance without the endpoint returns an
+// empty slice and an error with code ErrCodeAutocompleteUnsupported.
+//
+// Example:
+//
+//	completions, err := client.Autocomplete("gola")
+//	// completions == []string{"golang", "golang tutorial", ...}
+func (c *Client) Autocomplete(query string) ([]string, error) {
+	return c.AutocompleteContext(context.Background(), query)
+}
+
+// AutocompleteContext is like Autocomplete but aborts the request when
+// ctx is canceled.
+func (c *Client) AutocompleteContext(ctx context.Context, query string) ([]string, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, errors.EmptyQuery()
+	}
+
+	u, err := url.Parse(c.instanceURL)
+	if err != nil {
+		return nil, errors.InvalidURL(c.instanceURL).WithErr(err)
+	}
+	u.Path = strings.TrimSuffix(strings.TrimSuffix(u.Path, "/search"), "/") + "/autocompleter"
+	u.RawQuery = url.Values{"q": {query}}.Encode()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeAPIError, "failed to create autocomplete request", err)
+	}
+	httpReq.Header.Set("User-Agent", c.userAgent)
+	httpReq.Header.Set("Accept", "application/json")
+	c.authorize(httpReq)
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, errors.Canceled(ctx.Err())
+		}
+		if se, ok := redirectError(err); ok {
+			return nil, se
+		}
+		return nil, errors.NetworkError(err)
+	}
+	body := newLimitedReader(resp.Body, c.maxResponseBytes)
+	defer func() {
+		io.Copy(io.Discard, body)
+		resp.Body.Close()
+	}()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return []string{}, errors.AutocompleteUnsupported(c.instanceURL)
+	default:
+		return nil, errors.HTTPStatusError(resp.StatusCode, resp.Status)
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		if err == errResponseTooLarge {
+			return nil, errors.ResponseTooLarge(c.maxResponseBytes)
+		}
+		return nil, errors.InvalidResponse(err)
+	}
+	return parseCompletions(data)
+}
+
+// parseCompletions decodes an autocompleter response. SearXNG answers
+// either with a plain array of completions or, in the OpenSearch
+// suggestions format, with [query, [completions...]].
+func parseCompletions(data []byte) ([]string, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, errors.InvalidResponse(err)
+	}
+
+	if len(items) >= 2 && len(items[1]) > 0 && items[1][0] == '[' {
+		var completions []string
+		if err := json.Unmarshal(items[1], &completions); err != nil {
+			return nil, errors.InvalidResponse(err)
+		}
+		return completions, nil
+	}
+
+	completions := make([]string, 0, len(items))
+	for _, item := range items {
+		var s string
+		if err := json.Unmarshal(item, &s); err != nil {
+			return nil, errors.InvalidResponse(fmt.Errorf("unexpected autocomplete entry %s", item))
+		}
+		completions = append(completions, s)
+	}
+	return completions, nil
+}
@@ -0,0 +1,61 @@
+package searxng
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mule-ai/search/internal/errors"
+)
+
+func TestAutocomplete(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		want     []string
+		wantCode errors.ErrorCode
+	}{
+		{"plain list", http.StatusOK, `["golang", "golang tutorial"]`, []string{"golang", "golang tutorial"}, ""},
+		{"opensearch format", http.StatusOK, `["gola", ["golang", "golang jobs"]]`, []string{"golang", "golang jobs"}, ""},
+		{"no backend", http.StatusOK, `[]`, []string{}, ""},
+		{"not supported", http.StatusNotFound, `not found`, []string{}, errors.ErrCodeAutocompleteUnsupported},
+		{"malformed", http.StatusOK, `{"results": []}`, nil, errors.ErrCodeInvalidResponse},
+		{"server error", http.StatusBadGateway, ``, nil, errors.ErrCodeAPIUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath, gotQuery string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath, gotQuery = r.URL.Path, r.URL.Query().Get("q")
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			client := NewClientWithTimeout(server.URL, 5*time.Second)
+			got, err := client.Autocomplete("gola")
+
+			if gotPath != "/autocompleter" || gotQuery != "gola" {
+				t.Errorf("request = %s?q=%s, want /autocompleter?q=gola", gotPath, gotQuery)
+			}
+			if code := errors.GetErrorCode(err); code != tt.wantCode {
+				t.Errorf("error code = %q, want %q (err %v)", code, tt.wantCode, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Autocomplete() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAutocompleteEmptyQuery(t *testing.T) {
+	client := NewClientWithTimeout("https://search.example", time.Second)
+	if _, err := client.Autocomplete("  "); errors.GetErrorCode(err) != errors.ErrCodeEmptyQuery {
+		t.Errorf("expected an empty query error, got %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+package searxng
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mule-ai/search/internal/errors"
+)
+
+// Circuit breaker defaults.
+const (
+	DefaultBreakerThreshold = 3
+	DefaultBreakerCooldown  = 60 * time.Second
+)
+
+// CircuitBreaker tracks consecutive failures per instance and short-circuits
+// requests to instances that keep failing.
+//
+// After Threshold consecutive failures an instance is "open": requests to it
+// are rejected without touching the network until Cooldown has passed. The
+// next request after the cooldown is let through as a probe; a success
+// closes the breaker, another failure opens it again for a full cooldown.
+//
+// State is kept in memory, so it lasts for the lifetime of the process.
+// A CircuitBreaker is safe for concurrent use and can be shared between
+// clients for different instances.
+//
+// Example:
+//
+//	breaker := searxng.NewCircuitBreaker(3, time.Minute)
+//	client := searxng.NewClient(cfg)
+//	client.SetCircuitBreaker(breaker)
+type CircuitBreaker struct {
+	Threshold int
+	Cooldown  time.Duration
+
+	mu     sync.Mutex
+	states map[string]*breakerState
+	now    func() time.Time
+}
+
+type breakerState struct {
+	failures int
+	openedAt time.Time
+}
+
+// BreakerState is a snapshot of the breaker for one instance.
+type BreakerState struct {
+	Instance string
+	Failures int       // Consecutive failures
+	Open     bool      // Requests are being short-circuited
+	RetryAt  time.Time // When an open breaker lets the next request through
+}
+
+// NewCircuitBreaker creates a breaker that opens after threshold consecutive
+// failures and stays open for cooldown.
+//
+// A threshold of 0 or less uses DefaultBreakerThreshold; likewise for the
+// cooldown and DefaultBreakerCooldown.
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold <= 0 {
+		threshold = DefaultBreakerThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultBreakerCooldown
+	}
+	return &CircuitBreaker{
+		Threshold: threshold,
+		Cooldown:  cooldown,
+		states:    make(map[string]*breakerState),
+		now:       time.Now,
+	}
+}
+
+// Allow reports whether a request to instance should be attempted.
+func (b *CircuitBreaker) Allow(instance string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	st, ok := b.states[instance]
+	if !ok || st.failures < b.Threshold {
+		return true
+	}
+	return !b.now().Before(st.openedAt.Add(b.Cooldown))
+}
+
+// RecordSuccess closes the breaker for instance.
+func (b *CircuitBreaker) RecordSuccess(instance string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.states, instance)
+}
+
+// RecordFailure counts a failure for instance, opening the breaker once
+// the threshold is reached.
+func (b *CircuitBreaker) RecordFailure(instance string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	st, ok := b.states[instance]
+	if !ok {
+		st = &breakerState{}
+		b.states[instance] = st
+	}
+	st.failures++
+	if st.failures >= b.Threshold {
+		// Restart the cooldown, including after a failed probe
+		st.openedAt = b.now()
+	}
+}
+
+// State returns the breaker state for instance.
+func (b *CircuitBreaker) State(instance string) BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.stateLocked(instance)
+}
+
+// States returns the state of every instance with recorded failures,
+// sorted by instance URL.
+func (b *CircuitBreaker) States() []BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	states := make([]BreakerState, 0, len(b.states))
+	for instance := range b.states {
+		states = append(states, b.stateLocked(instance))
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Instance < states[j].Instance
+	})
+	return states
+}
+
+func (b *CircuitBreaker) stateLocked(instance string) BreakerState {
+	state := BreakerState{Instance: instance}
+	st, ok := b.states[instance]
+	if !ok {
+		return state
+	}
+	state.Failures = st.failures
+	if st.failures >= b.Threshold {
+		state.RetryAt = st.openedAt.Add(b.Cooldown)
+		state.Open = b.now().Before(state.RetryAt)
+	}
+	return state
+}
+
+// IsInstanceFailure reports whether err means the instance itself is
+// unavailable, as opposed to a bad request or a canceled search.
+func IsInstanceFailure(err error) bool {
+	switch errors.GetErrorCode(err) {
+	case errors.ErrCodeNetworkTimeout,
+		errors.ErrCodeNetworkUnreachable,
+		errors.ErrCodeConnectionRefused,
+		errors.ErrCodeDNSFailed,
+		errors.ErrCodeAPIUnavailable:
+		return true
+	}
+	return false
+}
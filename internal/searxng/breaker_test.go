@@ -0,0 +1,87 @@
+package searxng
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mule-ai/search/internal/errors"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := NewCircuitBreaker(2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	const instance = "https://a.example"
+
+	b.RecordFailure(instance)
+	if !b.Allow(instance) {
+		t.Fatal("breaker should stay closed below the threshold")
+	}
+
+	b.RecordFailure(instance)
+	if b.Allow(instance) {
+		t.Fatal("breaker should open at the threshold")
+	}
+	state := b.State(instance)
+	if !state.Open || state.Failures != 2 || !state.RetryAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("unexpected open state: %+v", state)
+	}
+
+	// After the cooldown one probe is let through
+	now = now.Add(time.Minute)
+	if !b.Allow(instance) {
+		t.Fatal("breaker should allow a probe after the cooldown")
+	}
+
+	// A failed probe reopens for a full cooldown
+	b.RecordFailure(instance)
+	if b.Allow(instance) {
+		t.Fatal("failed probe should reopen the breaker")
+	}
+
+	b.RecordSuccess(instance)
+	if !b.Allow(instance) || b.State(instance).Failures != 0 {
+		t.Error("success should close the breaker")
+	}
+	if len(b.States()) != 0 {
+		t.Errorf("States() should be empty after success, got %v", b.States())
+	}
+}
+
+func TestNewCircuitBreakerDefaults(t *testing.T) {
+	b := NewCircuitBreaker(0, 0)
+	if b.Threshold != DefaultBreakerThreshold || b.Cooldown != DefaultBreakerCooldown {
+		t.Errorf("NewCircuitBreaker(0, 0) = %d/%v, want defaults", b.Threshold, b.Cooldown)
+	}
+}
+
+func TestClientCircuitBreaker(t *testing.T) {
+	var hits int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	client := NewClientWithTimeout(ts.URL, 5*time.Second)
+	client.SetCircuitBreaker(NewCircuitBreaker(2, time.Minute))
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Search(NewSearchRequest("test"))
+		if errors.GetErrorCode(err) != errors.ErrCodeAPIUnavailable {
+			t.Fatalf("search %d: expected API_UNAVAILABLE, got %v", i, err)
+		}
+	}
+
+	_, err := client.Search(NewSearchRequest("test"))
+	if errors.GetErrorCode(err) != errors.ErrCodeCircuitOpen {
+		t.Fatalf("expected CIRCUIT_OPEN after repeated failures, got %v", err)
+	}
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Errorf("open breaker should skip the request, server saw %d hits", got)
+	}
+}
@@ -6,6 +6,7 @@ package searxng
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -110,6 +111,53 @@ var CategoryAliases = map[string]string{
 	"pdf":     "files",
 }
 
+// Lookup tables built once from ValidCategories and CategoryAliases, so
+// lookups don't rebuild anything and every caller sees the same order.
+// Changes to either map after package initialization are not seen.
+var (
+	// categoryNames lists the category names in display order
+	categoryNames = buildCategoryNames()
+	// categoryIndex maps each category name and alias to its category name
+	categoryIndex = buildCategoryIndex()
+)
+
+// buildCategoryNames orders ValidCategories by categoryOrder, followed
+// by any categories missing from it alphabetically.
+func buildCategoryNames() []string {
+	names := make([]string, 0, len(ValidCategories))
+	listed := make(map[string]bool, len(categoryOrder))
+	for _, name := range categoryOrder {
+		if _, ok := ValidCategories[name]; ok {
+			names = append(names, name)
+			listed[name] = true
+		}
+	}
+
+	var extra []string
+	for name := range ValidCategories {
+		if !listed[name] {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	return append(names, extra...)
+}
+
+// buildCategoryIndex maps category names and aliases of known categories
+// to category names.
+func buildCategoryIndex() map[string]string {
+	index := make(map[string]string, len(ValidCategories)+len(CategoryAliases))
+	for alias, name := range CategoryAliases {
+		if _, ok := ValidCategories[name]; ok {
+			index[alias] = name
+		}
+	}
+	for name := range ValidCategories {
+		index[name] = name
+	}
+	return index
+}
+
 // GetCategory retrieves a category by name, resolving aliases.
 //
 // Returns the Category and nil error if found, or an empty Category and error
@@ -128,42 +176,29 @@ var CategoryAliases = map[string]string{
 //	cat, err = searxng.GetCategory("photo")
 //	// Returns the same "images" category
 func GetCategory(name string) (Category, error) {
-	name = strings.ToLower(strings.TrimSpace(name))
-	
-	// Check direct match
-	if cat, ok := ValidCategories[name]; ok {
-		return cat, nil
-	}
-	
-	// Check aliases
-	if alias, ok := CategoryAliases[name]; ok {
-		if cat, ok := ValidCategories[alias]; ok {
-			return cat, nil
-		}
+	if canonical, ok := categoryIndex[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return ValidCategories[canonical], nil
 	}
-	
+
 	return Category{}, fmt.Errorf("unknown category: %s (valid categories: %s)", 
-		name, strings.Join(GetCategoryNames(), ", "))
+		strings.ToLower(strings.TrimSpace(name)), strings.Join(categoryNames, ", "))
 }
 
 // GetCategoryNames returns a list of all valid category names.
 //
-// The returned list is unsorted.
+// The list is in display order (general first) and is the same on every
+// call. The caller may modify it.
 func GetCategoryNames() []string {
-	names := make([]string, 0, len(ValidCategories))
-	for name := range ValidCategories {
-		names = append(names, name)
-	}
-	return names
+	return append([]string(nil), categoryNames...)
 }
 
 // GetDisplayNames returns a list of display names for all categories.
 //
 // Display names are human-readable versions (e.g., "General", "Images").
 func GetDisplayNames() []string {
-	names := make([]string, 0, len(ValidCategories))
-	for _, cat := range ValidCategories {
-		names = append(names, cat.DisplayName)
+	names := make([]string, 0, len(categoryNames))
+	for _, name := range categoryNames {
+		names = append(names, ValidCategories[name].DisplayName)
 	}
 	return names
 }
@@ -182,8 +217,8 @@ func GetDisplayNames() []string {
 //	    fmt.Println("Invalid category")
 //	}
 func IsValidCategory(name string) bool {
-	_, err := GetCategory(name)
-	return err == nil
+	_, ok := categoryIndex[strings.ToLower(strings.TrimSpace(name))]
+	return ok
 }
 
 // NormalizeCategory normalizes a category name, resolving aliases.
@@ -192,15 +227,11 @@ func IsValidCategory(name string) bool {
 // returns the input as-is (may be a custom category).
 func NormalizeCategory(name string) string {
 	name = strings.ToLower(strings.TrimSpace(name))
-	
-	if _, ok := ValidCategories[name]; ok {
-		return name
-	}
-	
-	if alias, ok := CategoryAliases[name]; ok {
-		return alias
+
+	if canonical, ok := categoryIndex[name]; ok {
+		return canonical
 	}
-	
+
 	return name // Return as-is if unknown (may be a custom category)
 }
 
@@ -323,4 +354,75 @@ func CategoryInfo(name string) string {
 	
 	return fmt.Sprintf("%s (%s)\n  Description: %s\n  Example: search -c %s \"%s\"",
 		cat.DisplayName, cat.Name, cat.Description, cat.Name, cat.ExampleQuery)
-}
\ No newline at end of file
+}
+
+// UncategorizedGroup is the GroupByCategory key for results without a category.
+const UncategorizedGroup = "other"
+
+// categoryOrder is the display order of known categories in grouped output.
+var categoryOrder = []string{
+	"general", "images", "videos", "news", "map", "music",
+	"it", "science", "files", "social media",
+}
+
+// GroupByCategory groups results by their normalized category, keeping the
+// original order of results within each group.
+//
+// Results without a category are grouped under UncategorizedGroup. Use
+// SortedCategories to iterate the groups in a stable order.
+//
+// Example:
+//
+//	groups := searxng.GroupByCategory(resp.Results)
+//	for _, name := range searxng.SortedCategories(groups) {
+//	    fmt.Println(searxng.CategoryDisplayName(name), len(groups[name]))
+//	}
+func GroupByCategory(results []SearchResult) map[string][]SearchResult {
+	groups := make(map[string][]SearchResult)
+	for _, result := range results {
+		name := NormalizeCategory(result.Category)
+		if name == "" {
+			name = UncategorizedGroup
+		}
+		groups[name] = append(groups[name], result)
+	}
+	return groups
+}
+
+// SortedCategories returns the keys of groups in display order: known
+// categories in their usual order, then any others alphabetically, with
+// UncategorizedGroup last.
+func SortedCategories(groups map[string][]SearchResult) []string {
+	names := make([]string, 0, len(groups))
+	for _, name := range categoryOrder {
+		if _, ok := groups[name]; ok {
+			names = append(names, name)
+		}
+	}
+
+	var extra []string
+	for name := range groups {
+		if _, known := ValidCategories[name]; !known && name != UncategorizedGroup {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	names = append(names, extra...)
+
+	if _, ok := groups[UncategorizedGroup]; ok {
+		names = append(names, UncategorizedGroup)
+	}
+	return names
+}
+
+// CategoryDisplayName returns the heading for a category group, e.g.
+// "Images" for "images". Unknown categories are capitalized as-is.
+func CategoryDisplayName(name string) string {
+	if cat, err := GetCategory(name); err == nil {
+		return cat.DisplayName
+	}
+	if name == "" {
+		return ""
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
@@ -1,6 +1,7 @@
 package searxng
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -104,6 +105,32 @@ func TestIsValidCategory(t *testing.T) {
 	}
 }
 
+// TestGetCategoryNamesOrder tests that the names come in display order
+// on every call
+func TestGetCategoryNamesOrder(t *testing.T) {
+	names := GetCategoryNames()
+	if len(names) != len(ValidCategories) {
+		t.Fatalf("GetCategoryNames() returned %d names, want %d", len(names), len(ValidCategories))
+	}
+	for i, name := range categoryOrder {
+		if names[i] != name {
+			t.Errorf("GetCategoryNames()[%d] = %q, want %q", i, names[i], name)
+		}
+	}
+
+	// Callers may modify the returned slice
+	names[0] = "changed"
+	if GetCategoryNames()[0] != "general" {
+		t.Error("modifying the result of GetCategoryNames() changed later results")
+	}
+
+	for alias, name := range CategoryAliases {
+		if NormalizeCategory(alias) != name || !IsValidCategory(strings.ToUpper(alias)) {
+			t.Errorf("alias %q should resolve to %q", alias, name)
+		}
+	}
+}
+
 // TestNormalizeCategory tests NormalizeCategory function
 func TestNormalizeCategory(t *testing.T) {
 	tests := []struct {
@@ -292,4 +319,45 @@ func TestAllCategoriesHaveInfo(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestGroupByCategory(t *testing.T) {
+	results := []SearchResult{
+		{Title: "n1", Category: "news"},
+		{Title: "g1", Category: "general"},
+		{Title: "x1", Category: "custom"},
+		{Title: "n2", Category: "news"},
+		{Title: "u1"},
+		{Title: "i1", Category: "images"},
+		{Title: "g2", Category: "general"},
+	}
+
+	groups := GroupByCategory(results)
+
+	got := SortedCategories(groups)
+	want := []string{"general", "images", "news", "custom", UncategorizedGroup}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("SortedCategories() = %v, want %v", got, want)
+	}
+
+	news := groups["news"]
+	if len(news) != 2 || news[0].Title != "n1" || news[1].Title != "n2" {
+		t.Errorf("news group should keep result order, got %v", news)
+	}
+	if len(groups[UncategorizedGroup]) != 1 {
+		t.Errorf("uncategorized result should be grouped under %q", UncategorizedGroup)
+	}
+}
+
+func TestCategoryDisplayName(t *testing.T) {
+	tests := map[string]string{
+		"images":       "Images",
+		"social media": "Social Media",
+		"custom":       "Custom",
+		"other":        "Other",
+	}
+	for name, want := range tests {
+		if got := CategoryDisplayName(name); got != want {
+			t.Errorf("CategoryDisplayName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
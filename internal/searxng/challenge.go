@@ -0,0 +1,37 @@
+package searxng
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+)
+
+// challengeMarkers appear in the challenge pages Cloudflare and similar
+// anti-bot gates serve in place of the page asked for.
+var challengeMarkers = [][]byte{
+	[]byte("challenge-platform"),
+	[]byte("cf-chl-"),
+	[]byte("_cf_chl_opt"),
+	[]byte("<title>Just a moment...</title>"),
+	[]byte("Attention Required! | Cloudflare"),
+	[]byte("Checking your browser before accessing"),
+	[]byte("DDoS-Guard"),
+}
+
+// isChallenge reports whether a response with the given status, headers
+// and body is an anti-bot challenge page. Only 403 and 503 responses are
+// considered, as those are what the gates answer with.
+func isChallenge(statusCode int, header http.Header, body []byte) bool {
+	if statusCode != http.StatusForbidden && statusCode != http.StatusServiceUnavailable {
+		return false
+	}
+	if strings.EqualFold(header.Get("Cf-Mitigated"), "challenge") {
+		return true
+	}
+	for _, marker := range challengeMarkers {
+		if bytes.Contains(body, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package searxng
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mule-ai/search/internal/errors"
+)
+
+// cloudflareChallenge is a trimmed Cloudflare "Just a moment" page.
+const cloudflareChallenge = `<!DOCTYPE html><html lang="en-US"><head><title>Just a moment...</title>
+<meta http-equiv="refresh" content="390"></head><body><div class="main-wrapper" role="main">
+<noscript>Enable JavaScript and cookies to continue</noscript></div>
+<script>(function(){window._cf_chl_opt={cvId: '3',cZone: "searx.example.com",cType: 'managed'};
+var a = document.createElement('script');a.src = '/cdn-cgi/challenge-platform/h/g/orchestrate/chl_page/v1';
+document.getElementsByTagName('head')[0].appendChild(a);}());</script></body></html>`
+
+func TestSearchAntiBotChallenge(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		header   string
+		body     string
+		wantCode errors.ErrorCode
+	}{
+		{"cloudflare 403 page", http.StatusForbidden, "", cloudflareChallenge, errors.ErrCodeAntiBotChallenge},
+		{"cloudflare 503 page", http.StatusServiceUnavailable, "", cloudflareChallenge, errors.ErrCodeAntiBotChallenge},
+		{"cf-mitigated header", http.StatusForbidden, "challenge", "<html></html>", errors.ErrCodeAntiBotChallenge},
+		{"plain 403", http.StatusForbidden, "", "Forbidden", errors.ErrCodeAPIError},
+		{"plain 503", http.StatusServiceUnavailable, "", "Service Unavailable", errors.ErrCodeAPIUnavailable},
+		{"challenge body on another status", http.StatusInternalServerError, "", cloudflareChallenge, errors.ErrCodeAPIUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.header != "" {
+					w.Header().Set("Cf-Mitigated", tt.header)
+				}
+				w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClientWithTimeout(server.URL, 5*time.Second)
+			_, err := client.Search(NewSearchRequest("test"))
+			if err == nil {
+				t.Fatal("Search() expected error")
+			}
+			if code := errors.GetErrorCode(err); code != tt.wantCode {
+				t.Errorf("error code = %s, want %s (%v)", code, tt.wantCode, err)
+			}
+			if tt.wantCode == errors.ErrCodeAntiBotChallenge && errors.ExitCode(err) != errors.ExitInstance {
+				t.Errorf("exit code = %d, want %d", errors.ExitCode(err), errors.ExitInstance)
+			}
+		})
+	}
+}
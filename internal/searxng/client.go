@@ -5,8 +5,12 @@
 package searxng
 
 import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -24,16 +28,33 @@ const defaultUserAgent = "search-cli/0.1.0"
 // It encapsulates the HTTP client, instance URL, and authentication credentials
 // needed to communicate with a SearXNG instance.
 type Client struct {
-	instanceURL string
-	client      *http.Client
-	userAgent   string
-	apiKey      string
+	instanceURL      string
+	client           *http.Client
+	userAgent        string
+	apiKey           string
+	hmacSecret       string
+	hmacHeader       string
+	maxResponseBytes int64
+	breaker          *CircuitBreaker
+	limiter          *Limiter
+	retries          int
+	retryNotify      RetryNotify
+	rateLimiter      *RateLimiter
+	rateLimitNotify  RateLimitNotify
+	apiFormat        string
+	acceptLanguage   string
+	extraParams      map[string]string
+	traceNotify      TraceNotify
+	allowPartial     bool
 }
 
 // NewClient creates a new SearXNG client with the given configuration.
 //
 // The client is configured with the instance URL, timeout, and optional API key
-// from the provided Config. Returns a ready-to-use Client instance.
+// from the provided Config. Connections to the instance are kept alive between
+// searches according to MaxIdleConns and IdleConnTimeout. With Insecure,
+// the client accepts any TLS certificate from the instance. Returns a
+// ready-to-use Client instance.
 //
 // Example:
 //
@@ -43,14 +64,28 @@ type Client struct {
 //	client := searxng.NewClient(cfg)
 //	resp, err := client.Search(searxng.NewSearchRequest("golang"))
 func NewClient(cfg *config.Config) *Client {
-	return &Client{
+	transport := newTransport(cfg.MaxIdleConns, cfg.IdleConnTimeout)
+	if cfg.Insecure {
+		// The transport is this client's own, so nothing else skips verification
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	c := &Client{
 		instanceURL: cfg.Instance,
 		client: &http.Client{
-			Timeout: time.Duration(cfg.Timeout) * time.Second,
+			Timeout:   time.Duration(cfg.Timeout) * time.Second,
+			Transport: transport,
 		},
-		userAgent: defaultUserAgent,
-		apiKey:    cfg.APIKey,
+		userAgent:        defaultUserAgent,
+		apiKey:           cfg.APIKey,
+		acceptLanguage:   cfg.AcceptLanguage,
+		extraParams:      cfg.ExtraParams,
+		maxResponseBytes: maxResponseBytesOrDefault(cfg.MaxResponseBytes),
+	}
+	c.client.CheckRedirect = c.checkRedirect(cfg.MaxRedirects)
+	if cfg.AuthType == config.AuthTypeHMAC {
+		c.SetHMACAuth(cfg.AuthSecret, cfg.AuthHeader)
 	}
+	return c
 }
 
 // NewClientWithTimeout creates a new SearXNG client with custom timeout.
@@ -58,13 +93,16 @@ func NewClient(cfg *config.Config) *Client {
 // This is a convenience function for creating a client with specific timeout settings
 // without a full Config object.
 func NewClientWithTimeout(instanceURL string, timeout time.Duration) *Client {
-	return &Client{
+	c := &Client{
 		instanceURL: instanceURL,
 		client: &http.Client{
 			Timeout: timeout,
 		},
-		userAgent: defaultUserAgent,
+		userAgent:        defaultUserAgent,
+		maxResponseBytes: config.DefaultMaxResponseBytes,
 	}
+	c.client.CheckRedirect = c.checkRedirect(config.DefaultMaxRedirects)
+	return c
 }
 
 // Search executes a search query against the SearXNG API.
@@ -74,11 +112,57 @@ func NewClientWithTimeout(instanceURL string, timeout time.Duration) *Client {
 // infoboxes, and suggestions.
 //
 // Returns an error if:
+//   - The request fails Validate
 //   - The instance URL is invalid
 //   - The HTTP request fails
 //   - The API returns a non-200 status code
 //   - The response JSON cannot be parsed
 func (c *Client) Search(req *SearchRequest) (*SearchResponse, error) {
+	return c.SearchContext(context.Background(), req)
+}
+
+// SearchContext executes a search query like Search, aborting the HTTP
+// request when ctx is canceled.
+//
+// Example:
+//
+//	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+//	defer stop()
+//	resp, err := client.SearchContext(ctx, searxng.NewSearchRequest("golang"))
+func (c *Client) SearchContext(ctx context.Context, req *SearchRequest) (*SearchResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if c.limiter != nil {
+		if err := c.limiter.Acquire(ctx); err != nil {
+			return nil, err
+		}
+		defer c.limiter.Release()
+	}
+
+	if c.breaker == nil {
+		return c.searchWithRetries(ctx, req)
+	}
+
+	if !c.breaker.Allow(c.instanceURL) {
+		return nil, errors.CircuitOpen(c.instanceURL, c.breaker.State(c.instanceURL).RetryAt)
+	}
+	resp, err := c.searchWithRetries(ctx, req)
+	if err == nil {
+		c.breaker.RecordSuccess(c.instanceURL)
+	} else if IsInstanceFailure(err) {
+		c.breaker.RecordFailure(c.instanceURL)
+	}
+	return resp, err
+}
+
+// search performs the HTTP round trip for SearchContext.
+func (c *Client) search(ctx context.Context, req *SearchRequest) (*SearchResponse, error) {
+	if err := c.waitForRateLimit(ctx); err != nil {
+		return nil, err
+	}
+
 	// Build the URL
 	u, err := url.Parse(c.instanceURL)
 	if err != nil {
@@ -121,42 +205,118 @@ func (c *Client) Search(req *SearchRequest) (*SearchResponse, error) {
 		query.Set("time_range", req.TimeRange)
 	}
 
+	// Pass through extra parameters, without replacing any set above
+	for key, value := range c.extraParams {
+		if !query.Has(key) {
+			query.Set(key, value)
+		}
+	}
+
 	u.RawQuery = query.Encode()
 
+	// Time the phases of the request only when asked to
+	var trace *tracer
+	if c.traceNotify != nil {
+		ctx, trace = withTrace(ctx)
+	}
+
 	// Create HTTP request
-	httpReq, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
 		return nil, errors.Wrap(errors.ErrCodeAPIError, "failed to create search request", err)
 	}
 
 	httpReq.Header.Set("User-Agent", c.userAgent)
 	httpReq.Header.Set("Accept", "application/json")
+	if lang := c.acceptLanguageFor(req); lang != "" {
+		httpReq.Header.Set("Accept-Language", lang)
+	}
 
-	// Add API key if present
-	if c.apiKey != "" {
-		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	// Add the API key or HMAC signature, if configured
+	c.authorize(httpReq)
+	if req.IfNoneMatch != "" {
+		httpReq.Header.Set("If-None-Match", req.IfNoneMatch)
 	}
 
 	// Execute request
 	resp, err := c.client.Do(httpReq)
+	if trace != nil {
+		c.traceNotify(trace.Timings())
+	}
 	if err != nil {
+		if ctx.Err() != nil {
+			return nil, errors.Canceled(ctx.Err())
+		}
+		if se, ok := redirectError(err); ok {
+			return nil, se
+		}
 		return nil, errors.NetworkError(err)
 	}
-	defer resp.Body.Close()
+	// Guard against oversized bodies from broken or malicious instances
+	body := newLimitedReader(resp.Body, c.maxResponseBytes)
+
+	// Drain what the decoder leaves unread so the connection can be reused
+	defer func() {
+		io.Copy(io.Discard, body)
+		resp.Body.Close()
+	}()
+
+	// The cached copy the caller holds is still current
+	if resp.StatusCode == http.StatusNotModified && req.IfNoneMatch != "" {
+		return &SearchResponse{
+			Query:       req.Query,
+			Page:        req.Page,
+			Instance:    c.instanceURL,
+			ETag:        resp.Header.Get("ETag"),
+			NotModified: true,
+		}, nil
+	}
 
 	// Check response status
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, errors.HTTPStatusError(resp.StatusCode, resp.Status).WithVerbose(fmt.Sprintf("Response body: %s", string(body)))
+		errBody, _ := io.ReadAll(body)
+		if isChallenge(resp.StatusCode, resp.Header, errBody) {
+			return nil, errors.AntiBotChallenge(resp.StatusCode)
+		}
+		return nil, errors.HTTPStatusError(resp.StatusCode, resp.Status).WithVerbose(fmt.Sprintf("Response body: %s", c.redact(string(errBody))))
 	}
 
-	// Parse response using optimized decoder
-	decoder := NewOptimizedDecoder(resp.Body)
-	defer decoder.Close()
+	// A page in another format would only fail to decode with a vaguer error
+	if echoed := echoedFormat(resp.Header.Get("Content-Type")); echoed != "" {
+		return nil, errors.UnexpectedFormat(req.Format, echoed, resp.Header.Get("Content-Type"))
+	}
 
 	var searchResp SearchResponse
-	if err := decoder.Decode(&searchResp); err != nil {
-		return nil, errors.InvalidResponse(err)
+	if c.allowPartial {
+		// Buffer the body so what arrived can be salvaged if it is cut off
+		data, err := io.ReadAll(body)
+		if err == errResponseTooLarge {
+			return nil, errors.ResponseTooLarge(c.maxResponseBytes)
+		}
+		if ctx.Err() != nil {
+			return nil, errors.Canceled(ctx.Err())
+		}
+		if err == nil {
+			err = json.Unmarshal(data, &searchResp)
+		}
+		if err != nil {
+			partial := DecodePartial(data)
+			if partial == nil {
+				return nil, errors.InvalidResponse(err)
+			}
+			searchResp = *partial
+		}
+	} else {
+		// Parse response using optimized decoder
+		decoder := NewOptimizedDecoder(body)
+		defer decoder.Close()
+
+		if err := decoder.Decode(&searchResp); err != nil {
+			if err == errResponseTooLarge {
+				return nil, errors.ResponseTooLarge(c.maxResponseBytes)
+			}
+			return nil, errors.InvalidResponse(err)
+		}
 	}
 
 	// Set search time from headers if available
@@ -169,6 +329,7 @@ func (c *Client) Search(req *SearchRequest) (*SearchResponse, error) {
 	// Set pagination info
 	searchResp.Page = req.Page
 	searchResp.Instance = c.instanceURL
+	searchResp.ETag = resp.Header.Get("ETag")
 
 	return &searchResp, nil
 }
@@ -179,10 +340,19 @@ func (c *Client) Search(req *SearchRequest) (*SearchResponse, error) {
 // parameters and executes it. The format parameter is used for the SearXNG API
 // response format (should always be "json" for this client).
 func (c *Client) SearchWithConfig(query string, results int, format string, category string, timeout int, language string, safeSearch int, page int, timeRange string) (*SearchResponse, error) {
+	return c.SearchWithConfigContext(context.Background(), query, results, format, category, timeout, language, safeSearch, page, timeRange)
+}
+
+// SearchWithConfigContext is like SearchWithConfig but aborts the request
+// when ctx is canceled.
+func (c *Client) SearchWithConfigContext(ctx context.Context, query string, results int, format string, category string, timeout int, language string, safeSearch int, page int, timeRange string) (*SearchResponse, error) {
 	req := NewSearchRequest(query)
 
 	// Always use JSON for SearXNG API response (format parameter is for our output formatter)
 	req.Format = "json"
+	if c.apiFormat != "" {
+		req.Format = c.apiFormat
+	}
 
 	// Set page number (default to 1)
 	if page > 0 {
@@ -207,7 +377,7 @@ func (c *Client) SearchWithConfig(query string, results int, format string, cate
 		req.TimeRange = timeRange
 	}
 
-	return c.Search(req)
+	return c.SearchContext(ctx, req)
 }
 
 // ValidateInstance checks if the instance URL is valid and reachable.
@@ -217,6 +387,12 @@ func (c *Client) SearchWithConfig(query string, results int, format string, cate
 //
 // Returns an error if the URL is malformed or the instance is unreachable.
 func (c *Client) ValidateInstance() error {
+	return c.ValidateInstanceContext(context.Background())
+}
+
+// ValidateInstanceContext is like ValidateInstance but aborts the request
+// when ctx is canceled.
+func (c *Client) ValidateInstanceContext(ctx context.Context) error {
 	u, err := url.Parse(c.instanceURL)
 	if err != nil {
 		return errors.InvalidURL(c.instanceURL).WithErr(err)
@@ -233,7 +409,7 @@ func (c *Client) ValidateInstance() error {
 	}
 
 	// Try to fetch the root endpoint
-	httpReq, err := http.NewRequest(http.MethodGet, c.instanceURL, nil)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.instanceURL, nil)
 	if err != nil {
 		return errors.Wrap(errors.ErrCodeAPIError, "failed to create validation request", err)
 	}
@@ -242,10 +418,21 @@ func (c *Client) ValidateInstance() error {
 
 	resp, err := c.client.Do(httpReq)
 	if err != nil {
+		if ctx.Err() != nil {
+			return errors.Canceled(ctx.Err())
+		}
+		if se, ok := redirectError(err); ok {
+			return se
+		}
 		return errors.NetworkError(err)
 	}
 	defer resp.Body.Close()
 
+	// Drain the body through the size guard so an endless root page can't hang us
+	if _, err := io.Copy(io.Discard, newLimitedReader(resp.Body, c.maxResponseBytes)); err == errResponseTooLarge {
+		return errors.ResponseTooLarge(c.maxResponseBytes)
+	}
+
 	return nil
 }
 
@@ -386,6 +573,33 @@ func (c *Client) GetUserAgent() string {
 	return c.userAgent
 }
 
+// redact masks the API key and HMAC secret wherever they appear in s,
+// e.g. when an instance echoes request headers back in an error page.
+func (c *Client) redact(s string) string {
+	for _, secret := range []string{c.apiKey, c.hmacSecret} {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, config.RedactSecret(secret))
+		}
+	}
+	return s
+}
+
+// SetAllowPartial makes the client keep the results of a response whose
+// body is cut off, such as by a dropped connection, instead of failing
+// to decode it. The response is then marked Partial. By default any
+// decode error fails the search.
+//
+// Example:
+//
+//	client.SetAllowPartial(true)
+//	resp, err := client.Search(req)
+//	if err == nil && resp.Partial {
+//	    fmt.Fprintln(os.Stderr, "Warning: some results may be missing")
+//	}
+func (c *Client) SetAllowPartial(allow bool) {
+	c.allowPartial = allow
+}
+
 // SetAPIKey sets the API key for authentication.
 //
 // If set, the key will be sent as a Bearer token in the Authorization header.
@@ -393,6 +607,95 @@ func (c *Client) SetAPIKey(key string) {
 	c.apiKey = key
 }
 
+// SetMaxResponseBytes sets the maximum response body size in bytes.
+//
+// A value of 0 or less restores the default limit.
+func (c *Client) SetMaxResponseBytes(n int64) {
+	c.maxResponseBytes = maxResponseBytesOrDefault(n)
+}
+
+// SetCircuitBreaker makes the client consult b before each search.
+//
+// Requests are short-circuited with a CIRCUIT_OPEN error while the breaker
+// is open for this client's instance. Pass nil to disable.
+func (c *Client) SetCircuitBreaker(b *CircuitBreaker) {
+	c.breaker = b
+}
+
+// SetLimiter makes searches wait for a slot in l, bounding how many run at
+// once across every client sharing it. Pass nil to disable.
+func (c *Client) SetLimiter(l *Limiter) {
+	c.limiter = l
+}
+
+// SetAcceptLanguage sets the Accept-Language header sent with searches,
+// such as "en-US,en;q=0.9". It is independent of the request's language,
+// which selects the language of the results, so the instance UI and the
+// results can differ. Pass "" to send the request's language instead.
+func (c *Client) SetAcceptLanguage(value string) {
+	c.acceptLanguage = value
+}
+
+// acceptLanguageFor returns the Accept-Language header for req: the one
+// set with SetAcceptLanguage, or else the search language unless that is
+// SearXNG's "all" or "auto".
+func (c *Client) acceptLanguageFor(req *SearchRequest) string {
+	if c.acceptLanguage != "" {
+		return c.acceptLanguage
+	}
+	if len(req.Languages) == 0 {
+		return ""
+	}
+	switch lang := req.Languages[0]; lang {
+	case "all", "auto":
+		return ""
+	default:
+		return lang
+	}
+}
+
+// SetExtraParams sets parameters added to the query string of every
+// search, for instance options the client doesn't model such as
+// "enabled_plugins" or "theme". Parameters the client sets itself, and
+// any in the instance URL, take precedence. Pass nil to send none.
+func (c *Client) SetExtraParams(params map[string]string) {
+	c.extraParams = params
+}
+
+// SetAPIFormat overrides the format parameter SearchWithConfig requests
+// from the instance, which is otherwise "json". It exists for debugging
+// instances with quirky JSON endpoints; the response must still be JSON.
+// Pass "" to restore the default.
+func (c *Client) SetAPIFormat(format string) {
+	c.apiFormat = format
+}
+
+// echoedFormat names the format of a response whose Content-Type is one
+// SearXNG produces other than JSON, such as the HTML results page an
+// instance serves when its JSON output is disabled or a proxy intercepts
+// the request. It returns "" for JSON and for types it doesn't recognize,
+// which are left to the decoder.
+func echoedFormat(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	switch mediaType {
+	case "text/html", "application/xhtml+xml":
+		return "html"
+	case "application/rss+xml", "application/xml", "text/xml":
+		return "rss"
+	case "text/csv":
+		return "csv"
+	}
+	return ""
+}
+
+// GetMaxResponseBytes returns the maximum response body size in bytes.
+func (c *Client) GetMaxResponseBytes() int64 {
+	return c.maxResponseBytes
+}
+
 // GetAPIKey returns the current API key.
 func (c *Client) GetAPIKey() string {
 	return c.apiKey
@@ -405,3 +708,63 @@ func (c *Client) IsReachable() bool {
 	err := c.ValidateInstance()
 	return err == nil
 }
+
+// errResponseTooLarge is returned by limitedReader once the limit is exceeded.
+var errResponseTooLarge = fmt.Errorf("response body exceeds size limit")
+
+// limitedReader reads from r but fails with errResponseTooLarge once more
+// than n bytes have been read. Unlike io.LimitReader it reports the overflow
+// instead of silently truncating, so a cut-off body is never mistaken for a
+// complete one.
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+// newLimitedReader wraps r so that reading more than n bytes fails.
+func newLimitedReader(r io.Reader, n int64) io.Reader {
+	return &limitedReader{r: r, n: n}
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n < 0 {
+		return 0, errResponseTooLarge
+	}
+	// Read one byte past the limit so overflow can be detected
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return n, errResponseTooLarge
+	}
+	return n, err
+}
+
+// newTransport returns an HTTP transport that keeps up to maxIdle
+// connections to the instance alive for idleTimeout seconds. Values that
+// are not positive fall back to the config defaults.
+func newTransport(maxIdle, idleTimeout int) *http.Transport {
+	if maxIdle <= 0 {
+		maxIdle = config.DefaultMaxIdleConns
+	}
+	if idleTimeout <= 0 {
+		idleTimeout = config.DefaultIdleConnTimeout
+	}
+
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConns = maxIdle
+	// All requests go to one instance, so let it use the whole pool
+	t.MaxIdleConnsPerHost = maxIdle
+	t.IdleConnTimeout = time.Duration(idleTimeout) * time.Second
+	return t
+}
+
+// maxResponseBytesOrDefault returns n, or the default limit if n is not positive.
+func maxResponseBytesOrDefault(n int64) int64 {
+	if n <= 0 {
+		return config.DefaultMaxResponseBytes
+	}
+	return n
+}
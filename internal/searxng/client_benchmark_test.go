@@ -5,6 +5,9 @@ package searxng
 
 import (
 	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -440,4 +443,39 @@ func BenchmarkTimeRangeOptions(b *testing.B) {
 			_ = tr // Simulate validation/processing
 		}
 	}
-}
\ No newline at end of file
+}
+
+// benchmarkRepeatedSearch runs b.N searches against a local server with
+// the client's transport replaced by transport.
+func benchmarkRepeatedSearch(b *testing.B, transport *http.Transport) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"query":"golang","number_of_results":1,"results":[{"title":"Go","url":"https://go.dev","content":"","engine":"google","score":1}]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(&config.Config{Instance: server.URL, Timeout: 30})
+	client.client.Transport = transport
+	defer transport.CloseIdleConnections()
+
+	req := NewSearchRequest("golang")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.Search(req); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkRepeatedSearchKeepAlive reuses connections between searches,
+// as NewClient does by default
+func BenchmarkRepeatedSearchKeepAlive(b *testing.B) {
+	benchmarkRepeatedSearch(b, newTransport(config.DefaultMaxIdleConns, config.DefaultIdleConnTimeout))
+}
+
+// BenchmarkRepeatedSearchNoKeepAlive opens a new connection for every search
+func BenchmarkRepeatedSearchNoKeepAlive(b *testing.B) {
+	transport := newTransport(config.DefaultMaxIdleConns, config.DefaultIdleConnTimeout)
+	transport.DisableKeepAlives = true
+	benchmarkRepeatedSearch(b, transport)
+}
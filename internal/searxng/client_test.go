@@ -1,15 +1,22 @@
 package searxng
 
 import (
+	"context"
 	"encoding/json"
 	"io"
+	"log"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/mule-ai/search/internal/config"
+	"github.com/mule-ai/search/internal/errors"
 )
 
 func TestNewClient(t *testing.T) {
@@ -47,6 +54,55 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestNewClientConnectionPool(t *testing.T) {
+	client := NewClient(&config.Config{Instance: "https://search.butler.ooo", Timeout: 30, MaxIdleConns: 4, IdleConnTimeout: 15})
+	transport, ok := client.client.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("expected *http.Transport, got %T", client.client.Transport)
+	}
+	if transport.MaxIdleConns != 4 || transport.MaxIdleConnsPerHost != 4 {
+		t.Errorf("expected 4 idle conns, got %d (per host %d)", transport.MaxIdleConns, transport.MaxIdleConnsPerHost)
+	}
+	if transport.IdleConnTimeout != 15*time.Second {
+		t.Errorf("expected idle timeout 15s, got %v", transport.IdleConnTimeout)
+	}
+
+	// Unset values fall back to the defaults
+	transport = NewClient(&config.Config{Instance: "https://search.butler.ooo", Timeout: 30}).client.Transport.(*http.Transport)
+	if transport.MaxIdleConnsPerHost != config.DefaultMaxIdleConns {
+		t.Errorf("expected default idle conns %d, got %d", config.DefaultMaxIdleConns, transport.MaxIdleConnsPerHost)
+	}
+	if transport.IdleConnTimeout != config.DefaultIdleConnTimeout*time.Second {
+		t.Errorf("expected default idle timeout, got %v", transport.IdleConnTimeout)
+	}
+}
+
+func TestClientReusesConnections(t *testing.T) {
+	var conns int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		// Trailing bytes after the JSON value must not block reuse
+		io.WriteString(w, `{"query":"golang","results":[]}`+"\n\n")
+	}))
+	server.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	client := NewClient(&config.Config{Instance: server.URL, Timeout: 5})
+	for i := 0; i < 3; i++ {
+		if _, err := client.Search(NewSearchRequest("golang")); err != nil {
+			t.Fatalf("search %d failed: %v", i, err)
+		}
+	}
+	if n := atomic.LoadInt32(&conns); n != 1 {
+		t.Errorf("expected one connection for three searches, got %d", n)
+	}
+}
+
 func TestNewClientWithTimeout(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -349,6 +405,61 @@ func TestNewSearchRequest(t *testing.T) {
 	}
 }
 
+func TestSearchRequestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*SearchRequest)
+		code   errors.ErrorCode
+	}{
+		{"valid request", func(r *SearchRequest) {}, ""},
+		{"empty query", func(r *SearchRequest) { r.Query = "" }, errors.ErrCodeEmptyQuery},
+		{"whitespace query", func(r *SearchRequest) { r.Query = "   " }, errors.ErrCodeEmptyQuery},
+		{"zero page", func(r *SearchRequest) { r.Page = 0 }, errors.ErrCodeInvalidRange},
+		{"negative page", func(r *SearchRequest) { r.Page = -1 }, errors.ErrCodeInvalidRange},
+		{"safe search too high", func(r *SearchRequest) { r.SafeSearch = 3 }, errors.ErrCodeInvalidRange},
+		{"negative safe search", func(r *SearchRequest) { r.SafeSearch = -1 }, errors.ErrCodeInvalidRange},
+		{"safe search off", func(r *SearchRequest) { r.SafeSearch = 0 }, ""},
+		{"empty format", func(r *SearchRequest) { r.Format = "" }, errors.ErrCodeInvalidFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := NewSearchRequest("test")
+			tt.modify(req)
+
+			err := req.Validate()
+			if tt.code == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			if got := errors.GetErrorCode(err); got != tt.code {
+				t.Errorf("Validate() error code = %q, want %q (err: %v)", got, tt.code, err)
+			}
+		})
+	}
+}
+
+func TestClientSearchValidatesRequest(t *testing.T) {
+	called := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer ts.Close()
+
+	client := NewClientWithTimeout(ts.URL, 5*time.Second)
+	req := NewSearchRequest("test")
+	req.Page = 0
+
+	if _, err := client.Search(req); errors.GetErrorCode(err) != errors.ErrCodeInvalidRange {
+		t.Errorf("Expected INVALID_RANGE error, got %v", err)
+	}
+	if called {
+		t.Error("Invalid request should not reach the server")
+	}
+}
+
 func TestClientWithCustomHeaders(t *testing.T) {
 	// Create a test server
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -845,3 +956,368 @@ func TestSearchWithConfig(t *testing.T) {
 		t.Errorf("SearchWithConfig() query = %v, want 'config test'", response.Query)
 	}
 }
+
+func TestSearchMaxResponseBytes(t *testing.T) {
+	t.Run("body exceeding limit is rejected", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			// Stream a never-ending results array well past the limit
+			w.Write([]byte(`{"query":"test","results":[`))
+			chunk := []byte(`{"title":"padding","url":"https://example.com","content":"` + strings.Repeat("x", 1024) + `"},`)
+			for i := 0; i < 64; i++ {
+				if _, err := w.Write(chunk); err != nil {
+					return
+				}
+			}
+		}))
+		defer server.Close()
+
+		client := NewClientWithTimeout(server.URL, 5*time.Second)
+		client.SetMaxResponseBytes(8 * 1024)
+
+		_, err := client.Search(NewSearchRequest("test"))
+		if err == nil {
+			t.Fatal("Search() expected error for oversized response, got nil")
+		}
+		if !strings.Contains(err.Error(), "RESPONSE_TOO_LARGE") {
+			t.Errorf("Search() error = %v, want RESPONSE_TOO_LARGE", err)
+		}
+	})
+
+	t.Run("body within limit is accepted", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"query":"test","results":[{"title":"ok","url":"https://example.com"}]}`))
+		}))
+		defer server.Close()
+
+		client := NewClientWithTimeout(server.URL, 5*time.Second)
+		client.SetMaxResponseBytes(8 * 1024)
+
+		resp, err := client.Search(NewSearchRequest("test"))
+		if err != nil {
+			t.Fatalf("Search() unexpected error: %v", err)
+		}
+		if len(resp.Results) != 1 {
+			t.Errorf("Search() returned %d results, want 1", len(resp.Results))
+		}
+	})
+
+	t.Run("config value is applied", func(t *testing.T) {
+		cfg := config.DefaultConfig()
+		cfg.MaxResponseBytes = 1234
+		client := NewClient(cfg)
+		if got := client.GetMaxResponseBytes(); got != 1234 {
+			t.Errorf("GetMaxResponseBytes() = %d, want 1234", got)
+		}
+	})
+
+	t.Run("zero falls back to default", func(t *testing.T) {
+		client := NewClient(&config.Config{Instance: "https://example.com", Timeout: 5})
+		if got := client.GetMaxResponseBytes(); got != config.DefaultMaxResponseBytes {
+			t.Errorf("GetMaxResponseBytes() = %d, want %d", got, config.DefaultMaxResponseBytes)
+		}
+	})
+}
+
+func TestValidateInstanceMaxResponseBytes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("a", 4096)))
+	}))
+	defer server.Close()
+
+	client := NewClientWithTimeout(server.URL, 5*time.Second)
+	client.SetMaxResponseBytes(1024)
+
+	err := client.ValidateInstance()
+	if err == nil {
+		t.Fatal("ValidateInstance() expected error for oversized response, got nil")
+	}
+	if !strings.Contains(err.Error(), "RESPONSE_TOO_LARGE") {
+		t.Errorf("ValidateInstance() error = %v, want RESPONSE_TOO_LARGE", err)
+	}
+}
+
+func TestSearchRegionQualifiedLanguage(t *testing.T) {
+	var gotLanguage string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotLanguage = r.URL.Query().Get("language")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"query":"test","results":[]}`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithTimeout(server.URL, 5*time.Second)
+	if _, err := client.Search(NewSearchFromQuery("test", WithLanguage("en-US"))); err != nil {
+		t.Fatalf("Search() unexpected error: %v", err)
+	}
+	if gotLanguage != "en-US" {
+		t.Errorf("language param = %q, want %q", gotLanguage, "en-US")
+	}
+}
+
+func TestSearchContextCanceled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Hold the request open until the client gives up
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClientWithTimeout(server.URL, 5*time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := client.SearchContext(ctx, NewSearchRequest("test"))
+	if err == nil {
+		t.Fatal("SearchContext() expected error after cancel, got nil")
+	}
+	if !strings.Contains(err.Error(), "CANCELED") {
+		t.Errorf("SearchContext() error = %v, want CANCELED", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("SearchContext() took %v, want it aborted promptly", elapsed)
+	}
+}
+
+func TestSearchRedactsAPIKeyInErrors(t *testing.T) {
+	const apiKey = "sk-supersecretkey-9876"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A misbehaving instance that echoes request headers in its error page
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("bad credentials: " + r.Header.Get("Authorization")))
+	}))
+	defer server.Close()
+
+	client := NewClientWithTimeout(server.URL, 5*time.Second)
+	client.SetAPIKey(apiKey)
+
+	_, err := client.Search(NewSearchRequest("test"))
+	if err == nil {
+		t.Fatal("Search() expected error for 401 response")
+	}
+	searchErr, ok := err.(*errors.SearchError)
+	if !ok {
+		t.Fatalf("Search() error type = %T, want *errors.SearchError", err)
+	}
+	for _, text := range []string{searchErr.Error(), searchErr.Verbose} {
+		if strings.Contains(text, apiKey) {
+			t.Errorf("error output leaks the API key: %q", text)
+		}
+	}
+	if !strings.Contains(searchErr.Verbose, "Bearer ****9876") {
+		t.Errorf("expected masked key in verbose details, got %q", searchErr.Verbose)
+	}
+}
+
+func TestSearchUnexpectedContentType(t *testing.T) {
+	tests := []struct {
+		contentType string
+		body        string
+		want        string // "" for no error
+	}{
+		{"text/html; charset=utf-8", "<!DOCTYPE html><html></html>", "returned html"},
+		{"application/rss+xml", "<rss></rss>", "returned rss"},
+		{"text/csv", "title,url\n", "returned csv"},
+		{"application/json", `{"query":"test","results":[]}`, ""},
+		{"text/plain; charset=utf-8", `{"query":"test","results":[]}`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClientWithTimeout(server.URL, 5*time.Second)
+			_, err := client.Search(NewSearchRequest("test"))
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("Search() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Search() error = %v, want it to mention %q", err, tt.want)
+			}
+			if code := errors.GetErrorCode(err); code != errors.ErrCodeInvalidResponse {
+				t.Errorf("error code = %s, want %s", code, errors.ErrCodeInvalidResponse)
+			}
+		})
+	}
+}
+
+func TestClientAllowPartial(t *testing.T) {
+	// Promise more than is sent, so the connection drops mid-body
+	body := `{"query":"golang","results":[` +
+		`{"title":"Go","url":"https://go.dev/","engine":"google"},` +
+		`{"title":"Spec","url":"https://go.dev/ref/spec","engine":"bing"},` +
+		`{"title":"Tour","url":"https://go.dev/tou`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)+100))
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	client := NewClientWithTimeout(server.URL, 5*time.Second)
+	if _, err := client.Search(NewSearchRequest("golang")); errors.GetErrorCode(err) != errors.ErrCodeInvalidResponse {
+		t.Fatalf("Search() error = %v, want %s by default", err, errors.ErrCodeInvalidResponse)
+	}
+
+	client.SetAllowPartial(true)
+	resp, err := client.Search(NewSearchRequest("golang"))
+	if err != nil {
+		t.Fatalf("Search() with partial results allowed: %v", err)
+	}
+	if !resp.Partial || len(resp.Results) != 2 || resp.Results[1].Title != "Spec" {
+		t.Errorf("expected the 2 whole results marked partial, got partial=%v %+v", resp.Partial, resp.Results)
+	}
+	if resp.Instance != server.URL {
+		t.Errorf("Instance = %q, want %q", resp.Instance, server.URL)
+	}
+}
+
+func TestSetAPIFormat(t *testing.T) {
+	var gotFormat string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotFormat = r.URL.Query().Get("format")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"query":"test","results":[]}`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithTimeout(server.URL, 5*time.Second)
+	if _, err := client.SearchWithConfig("test", 10, "text", "", 5, "", 0, 1, ""); err != nil {
+		t.Fatalf("SearchWithConfig() unexpected error: %v", err)
+	}
+	if gotFormat != "json" {
+		t.Errorf("format param = %q, want json", gotFormat)
+	}
+
+	client.SetAPIFormat("json2")
+	if _, err := client.SearchWithConfig("test", 10, "text", "", 5, "", 0, 1, ""); err != nil {
+		t.Fatalf("SearchWithConfig() unexpected error: %v", err)
+	}
+	if gotFormat != "json2" {
+		t.Errorf("format param = %q, want json2", gotFormat)
+	}
+}
+
+func TestSearchAcceptLanguage(t *testing.T) {
+	var gotHeader, gotLanguage string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("Accept-Language")
+		gotLanguage = r.URL.Query().Get("language")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"query":"test","results":[]}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		header     string
+		language   string
+		wantHeader string
+	}{
+		{"defaults to the search language", "", "de", "de"},
+		{"independent of the search language", "en-US,en;q=0.9", "de", "en-US,en;q=0.9"},
+		{"no header for all languages", "", "all", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClientWithTimeout(server.URL, 5*time.Second)
+			client.SetAcceptLanguage(tt.header)
+			req := NewSearchRequest("test")
+			req.Languages = []string{tt.language}
+			if _, err := client.Search(req); err != nil {
+				t.Fatalf("Search() unexpected error: %v", err)
+			}
+			if gotHeader != tt.wantHeader {
+				t.Errorf("Accept-Language = %q, want %q", gotHeader, tt.wantHeader)
+			}
+			if gotLanguage != tt.language {
+				t.Errorf("language param = %q, want %q", gotLanguage, tt.language)
+			}
+		})
+	}
+}
+
+func TestSearchExtraParams(t *testing.T) {
+	var got url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"query":"test","results":[]}`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithTimeout(server.URL, 5*time.Second)
+	client.SetExtraParams(map[string]string{
+		"enabled_plugins": "Hash_plugin,Self_Information",
+		"theme":           "simple & clean",
+		"q":               "overridden",
+		"format":          "html",
+	})
+	if _, err := client.Search(NewSearchRequest("test")); err != nil {
+		t.Fatalf("Search() unexpected error: %v", err)
+	}
+
+	if v := got.Get("enabled_plugins"); v != "Hash_plugin,Self_Information" {
+		t.Errorf("enabled_plugins = %q, want %q", v, "Hash_plugin,Self_Information")
+	}
+	if v := got.Get("theme"); v != "simple & clean" {
+		t.Errorf("theme = %q, want %q", v, "simple & clean")
+	}
+	// Known parameters take precedence over passthrough ones
+	if v := got["q"]; len(v) != 1 || v[0] != "test" {
+		t.Errorf("q = %q, want [test]", v)
+	}
+	if v := got.Get("format"); v != "json" {
+		t.Errorf("format = %q, want json", v)
+	}
+}
+
+func TestNewClientInsecure(t *testing.T) {
+	// httptest's certificate is signed by a CA the system doesn't trust
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"query":"test","results":[]}`))
+	}))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0) // rejected handshakes are expected
+	server.StartTLS()
+	defer server.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.Instance = server.URL
+	_, err := NewClient(cfg).Search(NewSearchRequest("test"))
+	if err == nil {
+		t.Fatal("Search() expected a certificate error without Insecure")
+	}
+	if !strings.Contains(err.Error(), "certificate") {
+		t.Errorf("Search() error = %v, want a certificate error", err)
+	}
+
+	cfg.Insecure = true
+	if _, err := NewClient(cfg).Search(NewSearchRequest("test")); err != nil {
+		t.Errorf("Search() with Insecure error = %v", err)
+	}
+
+	// Other clients keep verifying
+	if tr, ok := http.DefaultTransport.(*http.Transport); ok && tr.TLSClientConfig != nil && tr.TLSClientConfig.InsecureSkipVerify {
+		t.Error("Insecure changed http.DefaultTransport")
+	}
+	cfg.Insecure = false
+	if _, err := NewClient(cfg).Search(NewSearchRequest("test")); err == nil {
+		t.Error("Search() expected a certificate error from a new client without Insecure")
+	}
+}
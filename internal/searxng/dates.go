@@ -0,0 +1,105 @@
+package searxng
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// publishedDateLayouts are the date formats SearXNG engines report in
+// publishedDate. Values without a zone are taken as UTC.
+var publishedDateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// parsePublishedDate parses a publishedDate value, reporting false when it
+// is empty or in an unknown format.
+func parsePublishedDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range publishedDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// ParseFilterDate parses a --since or --until value, either RFC3339 or
+// YYYY-MM-DD (UTC). With endOfDay, a bare date means its last instant, so
+// an --until date includes the whole day.
+//
+// Example:
+//
+//	until, err := searxng.ParseFilterDate("2024-06-30", true)
+//	// until == 2024-06-30T23:59:59.999999999Z
+func ParseFilterDate(s string, endOfDay bool) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: use YYYY-MM-DD or RFC3339", s)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return t, nil
+}
+
+// DateFilter keeps results published within a date range. A zero Since or
+// Until leaves that end of the range open.
+type DateFilter struct {
+	Since time.Time
+	Until time.Time
+	// RequireDate drops results without a published date; otherwise they
+	// are kept
+	RequireDate bool
+}
+
+// Active reports whether the filter restricts the date range.
+func (f DateFilter) Active() bool {
+	return !f.Since.IsZero() || !f.Until.IsZero()
+}
+
+// Apply returns the results published within the range, in their original
+// order. An inactive filter returns results unchanged.
+//
+// Example:
+//
+//	since, _ := searxng.ParseFilterDate("2024-01-01", false)
+//	resp.Results = searxng.DateFilter{Since: since}.Apply(resp.Results)
+func (f DateFilter) Apply(results []SearchResult) []SearchResult {
+	if !f.Active() {
+		return results
+	}
+
+	kept := make([]SearchResult, 0, len(results))
+	for _, r := range results {
+		if r.PublishedDate == nil {
+			if !f.RequireDate {
+				kept = append(kept, r)
+			}
+			continue
+		}
+		if !f.Since.IsZero() && r.PublishedDate.Before(f.Since) {
+			continue
+		}
+		if !f.Until.IsZero() && r.PublishedDate.After(f.Until) {
+			continue
+		}
+		kept = append(kept, r)
+	}
+	return kept
+}
+
+// Process implements ResultProcessor.
+func (f DateFilter) Process(results []SearchResult) []SearchResult {
+	return f.Apply(results)
+}
@@ -0,0 +1,113 @@
+package searxng
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseFilterDate(t *testing.T) {
+	tests := []struct {
+		in       string
+		endOfDay bool
+		want     time.Time
+		wantErr  bool
+	}{
+		{"2024-01-01", false, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"2024-06-30", true, time.Date(2024, 6, 30, 23, 59, 59, 999999999, time.UTC), false},
+		{"2024-03-05T10:30:00Z", true, time.Date(2024, 3, 5, 10, 30, 0, 0, time.UTC), false},
+		{"2024-03-05T10:30:00+02:00", false, time.Date(2024, 3, 5, 8, 30, 0, 0, time.UTC), false},
+		{"2024/01/01", false, time.Time{}, true},
+		{"yesterday", false, time.Time{}, true},
+		{"2024-13-01", false, time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFilterDate(tt.in, tt.endOfDay)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseFilterDate(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !got.Equal(tt.want) {
+			t.Errorf("ParseFilterDate(%q, %v) = %v, want %v", tt.in, tt.endOfDay, got, tt.want)
+		}
+	}
+}
+
+func TestSearchResultPublishedDate(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{`{"title":"a","publishedDate":"2024-02-03T04:05:06"}`, "2024-02-03T04:05:06Z"},
+		{`{"title":"a","publishedDate":"2024-02-03T04:05:06+01:00"}`, "2024-02-03T04:05:06+01:00"},
+		{`{"title":"a","publishedDate":"2024-02-03"}`, "2024-02-03T00:00:00Z"},
+		{`{"title":"a","publishedDate":null}`, ""},
+		{`{"title":"a","publishedDate":"not a date"}`, ""},
+		{`{"title":"a"}`, ""},
+	}
+
+	for _, tt := range tests {
+		var r SearchResult
+		if err := json.Unmarshal([]byte(tt.data), &r); err != nil {
+			t.Fatalf("Unmarshal(%s) error: %v", tt.data, err)
+		}
+		got := ""
+		if r.PublishedDate != nil {
+			got = r.PublishedDate.Format(time.RFC3339)
+		}
+		if got != tt.want {
+			t.Errorf("Unmarshal(%s) PublishedDate = %q, want %q", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestDateFilterApply(t *testing.T) {
+	date := func(s string) *time.Time {
+		t, _ := time.Parse("2006-01-02", s)
+		return &t
+	}
+	results := []SearchResult{
+		{Title: "old", PublishedDate: date("2023-12-31")},
+		{Title: "start", PublishedDate: date("2024-01-01")},
+		{Title: "undated"},
+		{Title: "end", PublishedDate: date("2024-06-30")},
+		{Title: "new", PublishedDate: date("2024-07-01")},
+	}
+	since, _ := ParseFilterDate("2024-01-01", false)
+	until, _ := ParseFilterDate("2024-06-30", true)
+
+	titles := func(rs []SearchResult) []string {
+		var out []string
+		for _, r := range rs {
+			out = append(out, r.Title)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name   string
+		filter DateFilter
+		want   []string
+	}{
+		{"inactive", DateFilter{RequireDate: true}, []string{"old", "start", "undated", "end", "new"}},
+		{"range keeps undated", DateFilter{Since: since, Until: until}, []string{"start", "undated", "end"}},
+		{"range requires date", DateFilter{Since: since, Until: until, RequireDate: true}, []string{"start", "end"}},
+		{"since only", DateFilter{Since: since, RequireDate: true}, []string{"start", "end", "new"}},
+		{"until only", DateFilter{Until: until, RequireDate: true}, []string{"old", "start", "end"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := titles(tt.filter.Apply(results))
+			if len(got) != len(tt.want) {
+				t.Fatalf("Apply() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Apply() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -80,6 +80,75 @@ func DecodeResponse(r io.Reader) (*SearchResponse, error) {
 	return &resp, nil
 }
 
+// DecodePartial salvages a response body that was cut off, e.g. by a
+// dropped connection: it keeps every result decoded in full before the
+// point the body ends, along with any other fields that came before
+// them, and marks the response Partial. It returns nil when not even one
+// result can be read, so a body that is simply invalid is still an error.
+//
+// Example:
+//
+//	if err := json.Unmarshal(data, &resp); err != nil {
+//	    if partial := searxng.DecodePartial(data); partial != nil {
+//	        return partial, nil
+//	    }
+//	    return nil, err
+//	}
+func DecodePartial(data []byte) *SearchResponse {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil
+	}
+
+	fields := make(map[string]json.RawMessage)
+	var results []SearchResult
+fields:
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			break
+		}
+		key, _ := tok.(string)
+		if key != "results" {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				break
+			}
+			fields[key] = raw
+			continue
+		}
+		if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+			break
+		}
+		for dec.More() {
+			var r SearchResult
+			if err := dec.Decode(&r); err != nil {
+				break fields
+			}
+			results = append(results, r)
+		}
+		if _, err := dec.Token(); err != nil {
+			break
+		}
+	}
+	if len(results) == 0 {
+		return nil
+	}
+
+	// The fields read in full decode as usual; one the instance sent in
+	// an unexpected shape is left at its zero value
+	var resp SearchResponse
+	if data, err := json.Marshal(fields); err == nil {
+		json.Unmarshal(data, &resp)
+	}
+	resp.Results = results
+	if resp.NumberOfResults < len(results) {
+		resp.NumberOfResults = len(results)
+	}
+	resp.Partial = true
+	return &resp
+}
+
 // StreamingDecoder provides streaming JSON decoding for large responses.
 type StreamingDecoder struct {
 	decoder *json.Decoder
@@ -4,6 +4,7 @@ package searxng
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"testing"
 )
 
@@ -270,6 +271,15 @@ func TestSearchResultUnmarshalJSON(t *testing.T) {
 				return r.ParsedURL != nil
 			},
 		},
+		{
+			name: "raw fields kept",
+			data: []byte(`{"title":"Test","url":"https://example.com","engines":["google","bing"],"positions":[1,3],"score":2}`),
+			wantErr: false,
+			check: func(r *SearchResult) bool {
+				engines, ok := r.Raw["engines"].([]interface{})
+				return ok && len(engines) == 2 && r.Raw["positions"] != nil && r.Raw["title"] == "Test"
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -376,6 +386,25 @@ func TestOptimizedDecoderVsStandard(t *testing.T) {
 }
 
 // TestDecoderWithLargeResponse verifies decoder handles large responses.
+func TestEffectiveTotal(t *testing.T) {
+	two := []SearchResult{{URL: "https://a.example"}, {URL: "https://b.example"}}
+	tests := []struct {
+		name string
+		resp *SearchResponse
+		want int
+	}{
+		{"nil response", nil, 0},
+		{"instance estimate", &SearchResponse{NumberOfResults: 1500, Results: two}, 1500},
+		{"no estimate", &SearchResponse{Results: two}, 2},
+		{"no results", &SearchResponse{}, 0},
+	}
+	for _, tt := range tests {
+		if got := EffectiveTotal(tt.resp); got != tt.want {
+			t.Errorf("%s: EffectiveTotal() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
 func TestDecoderWithLargeResponse(t *testing.T) {
 	// Create a large response with many results
 	var buf bytes.Buffer
@@ -399,4 +428,50 @@ func TestDecoderWithLargeResponse(t *testing.T) {
 	if len(resp.Results) != 100 {
 		t.Errorf("Results length = %v, want 100", len(resp.Results))
 	}
-}
\ No newline at end of file
+}
+// TestDecodePartial verifies results read before a body is cut off are kept.
+func TestDecodePartial(t *testing.T) {
+	full := `{"query":"golang","number_of_results":"1200","results":[` +
+		`{"title":"Go","url":"https://go.dev/","content":"Go is open source","engine":"google"},` +
+		`{"title":"Spec","url":"https://go.dev/ref/spec","content":"","engine":"bing"},` +
+		`{"title":"Tour","url":"https://go.dev/tour/","content":"A tour of Go","engine":"bing"}` +
+		`],"answers":[],"suggestions":["golang tutorial"]}`
+
+	tests := []struct {
+		name   string
+		cut    string // the body ends just before this
+		titles []string
+	}{
+		{"inside the third result", `Go","engine":"bing"}]`, []string{"Go", "Spec"}},
+		{"between results", `,{"title":"Tour"`, []string{"Go", "Spec"}},
+		{"after the results", `,"answers"`, []string{"Go", "Spec", "Tour"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := []byte(full[:bytes.Index([]byte(full), []byte(tt.cut))])
+			resp := DecodePartial(data)
+			if resp == nil {
+				t.Fatal("DecodePartial() = nil, want the results read")
+			}
+			if !resp.Partial {
+				t.Error("expected the response to be marked partial")
+			}
+			var titles []string
+			for _, r := range resp.Results {
+				titles = append(titles, r.Title)
+			}
+			if fmt.Sprint(titles) != fmt.Sprint(tt.titles) {
+				t.Errorf("results = %v, want %v", titles, tt.titles)
+			}
+			if resp.Query != "golang" || resp.NumberOfResults != 1200 {
+				t.Errorf("fields before the results: query %q, number_of_results %d", resp.Query, resp.NumberOfResults)
+			}
+		})
+	}
+
+	for _, data := range []string{``, `not json`, `{"query":"golang","results":[{"title":"Go"`, `[1,2]`} {
+		if resp := DecodePartial([]byte(data)); resp != nil {
+			t.Errorf("DecodePartial(%q) = %+v, want nil without a whole result", data, resp)
+		}
+	}
+}
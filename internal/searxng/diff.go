@@ -0,0 +1,41 @@
+package searxng
+
+// DiffResults compares two runs of the same search by result URL. added
+// holds the results in curr whose URL was not in prev, in curr's order;
+// removed holds those in prev whose URL is gone from curr, in prev's
+// order. A URL listed more than once is reported once.
+//
+// Example:
+//
+//	added, removed := searxng.DiffResults(previous.Results, current.Results)
+//	for _, r := range added {
+//	    fmt.Println("+", r.Title)
+//	}
+func DiffResults(prev, curr []SearchResult) (added, removed []SearchResult) {
+	inPrev := urlSet(prev)
+	inCurr := urlSet(curr)
+
+	reported := make(map[string]bool)
+	for _, r := range curr {
+		if !inPrev[r.URL] && !reported[r.URL] {
+			reported[r.URL] = true
+			added = append(added, r)
+		}
+	}
+	for _, r := range prev {
+		if !inCurr[r.URL] && !reported[r.URL] {
+			reported[r.URL] = true
+			removed = append(removed, r)
+		}
+	}
+	return added, removed
+}
+
+// urlSet returns the URLs of results.
+func urlSet(results []SearchResult) map[string]bool {
+	set := make(map[string]bool, len(results))
+	for _, r := range results {
+		set[r.URL] = true
+	}
+	return set
+}
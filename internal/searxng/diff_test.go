@@ -0,0 +1,48 @@
+package searxng
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffResults(t *testing.T) {
+	results := func(urls ...string) []SearchResult {
+		var out []SearchResult
+		for _, u := range urls {
+			out = append(out, SearchResult{Title: u, URL: "https://example.com/" + u})
+		}
+		return out
+	}
+	titles := func(results []SearchResult) string {
+		var out []string
+		for _, r := range results {
+			out = append(out, r.Title)
+		}
+		return strings.Join(out, ",")
+	}
+
+	tests := []struct {
+		name        string
+		prev, curr  []SearchResult
+		wantAdded   string
+		wantRemoved string
+	}{
+		{"unchanged", results("a", "b"), results("b", "a"), "", ""},
+		{"first run", nil, results("a", "b"), "a,b", ""},
+		{"added and removed", results("a", "b", "c"), results("d", "a", "e"), "d,e", "b,c"},
+		{"all gone", results("a", "b"), nil, "", "a,b"},
+		{"duplicates", results("a", "b", "b"), results("a", "c", "c"), "c", "b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, removed := DiffResults(tt.prev, tt.curr)
+			if got := titles(added); got != tt.wantAdded {
+				t.Errorf("added = %q, want %q", got, tt.wantAdded)
+			}
+			if got := titles(removed); got != tt.wantRemoved {
+				t.Errorf("removed = %q, want %q", got, tt.wantRemoved)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package searxng
+
+import "sort"
+
+// LimitPerEngine keeps at most n results from each engine, choosing each
+// engine's highest-scored results, and returns them in their original
+// order. Ties in score keep the earlier result. Results are grouped by
+// their Engine field; n of 0 or less returns results unchanged.
+//
+// Example:
+//
+//	resp.Results = searxng.LimitPerEngine(resp.Results, 3)
+func LimitPerEngine(results []SearchResult, n int) []SearchResult {
+	if n <= 0 {
+		return results
+	}
+
+	byEngine := make(map[string][]int)
+	for i, r := range results {
+		byEngine[r.Engine] = append(byEngine[r.Engine], i)
+	}
+
+	keep := make([]bool, len(results))
+	for _, indexes := range byEngine {
+		sort.SliceStable(indexes, func(a, b int) bool {
+			return results[indexes[a]].Score > results[indexes[b]].Score
+		})
+		if len(indexes) > n {
+			indexes = indexes[:n]
+		}
+		for _, i := range indexes {
+			keep[i] = true
+		}
+	}
+
+	limited := make([]SearchResult, 0, len(results))
+	for i, r := range results {
+		if keep[i] {
+			limited = append(limited, r)
+		}
+	}
+	return limited
+}
+
+// EngineLimit is a ResultProcessor that applies LimitPerEngine with its
+// value as n.
+type EngineLimit int
+
+// Process implements ResultProcessor.
+func (n EngineLimit) Process(results []SearchResult) []SearchResult {
+	return LimitPerEngine(results, int(n))
+}
+
+// EngineStat is how much one engine contributed to a result set.
+type EngineStat struct {
+	Engine       string  `json:"engine"`
+	Results      int     `json:"results"`
+	AverageScore float64 `json:"average_score"`
+}
+
+// EngineStats counts the results each engine returned and averages their
+// scores, most results first and by engine name among equals. A result
+// counts for every engine in Engines, or for Engine when SearXNG didn't
+// list them. Scores are the instance's, before any normalization.
+//
+// Example:
+//
+//	for _, s := range searxng.EngineStats(resp.Results) {
+//	    fmt.Printf("%s: %d results, average score %.2f\n", s.Engine, s.Results, s.AverageScore)
+//	}
+func EngineStats(results []SearchResult) []EngineStat {
+	byEngine := make(map[string]*EngineStat)
+	var stats []*EngineStat
+	for _, r := range results {
+		score := r.Score
+		if r.OriginalScore != nil {
+			score = *r.OriginalScore
+		}
+		engines := r.Engines
+		if len(engines) == 0 {
+			engines = []string{r.Engine}
+		}
+		for _, engine := range engines {
+			if engine == "" {
+				continue
+			}
+			s, ok := byEngine[engine]
+			if !ok {
+				s = &EngineStat{Engine: engine}
+				byEngine[engine] = s
+				stats = append(stats, s)
+			}
+			s.Results++
+			// Running total until averaged below
+			s.AverageScore += score
+		}
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Results != stats[j].Results {
+			return stats[i].Results > stats[j].Results
+		}
+		return stats[i].Engine < stats[j].Engine
+	})
+	out := make([]EngineStat, 0, len(stats))
+	for _, s := range stats {
+		s.AverageScore /= float64(s.Results)
+		out = append(out, *s)
+	}
+	return out
+}
@@ -0,0 +1,72 @@
+package searxng
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLimitPerEngine(t *testing.T) {
+	// Google floods the list; the cap keeps its best two in place
+	results := []SearchResult{
+		{Title: "g1", Engine: "google", Score: 1.0},
+		{Title: "g2", Engine: "google", Score: 3.0},
+		{Title: "b1", Engine: "bing", Score: 0.5},
+		{Title: "g3", Engine: "google", Score: 2.0},
+		{Title: "g4", Engine: "google", Score: 2.0},
+		{Title: "d1", Engine: "duckduckgo", Score: 0.1},
+		{Title: "g5", Engine: "google", Score: 0.2},
+	}
+
+	titles := func(rs []SearchResult) string {
+		s := ""
+		for _, r := range rs {
+			s += r.Title + " "
+		}
+		return s
+	}
+
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, "g1 g2 b1 g3 g4 d1 g5 "},
+		{-1, "g1 g2 b1 g3 g4 d1 g5 "},
+		{1, "g2 b1 d1 "},
+		{2, "g2 b1 g3 d1 "},
+		{10, "g1 g2 b1 g3 g4 d1 g5 "},
+	}
+
+	for _, tt := range tests {
+		if got := titles(LimitPerEngine(results, tt.n)); got != tt.want {
+			t.Errorf("LimitPerEngine(n=%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+
+	if len(results) != 7 || results[0].Title != "g1" {
+		t.Error("LimitPerEngine modified its input")
+	}
+}
+
+func TestEngineStats(t *testing.T) {
+	raw := 4.0
+	results := []SearchResult{
+		{Engine: "google", Engines: []string{"google", "bing"}, Score: 3.0},
+		{Engine: "google", Score: 1.0},
+		{Engine: "bing", Engines: []string{"bing"}, Score: 2.0},
+		// A normalized score counts as the raw one
+		{Engine: "duckduckgo", Score: 0.5, OriginalScore: &raw},
+		{Engine: "", Score: 9.0},
+	}
+
+	want := []EngineStat{
+		{Engine: "bing", Results: 2, AverageScore: 2.5},
+		{Engine: "google", Results: 2, AverageScore: 2.0},
+		{Engine: "duckduckgo", Results: 1, AverageScore: 4.0},
+	}
+	if got := EngineStats(results); !reflect.DeepEqual(got, want) {
+		t.Errorf("EngineStats() = %+v, want %+v", got, want)
+	}
+	if got := EngineStats(nil); len(got) != 0 {
+		t.Errorf("EngineStats(nil) = %+v, want none", got)
+	}
+}
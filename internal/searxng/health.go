@@ -0,0 +1,105 @@
+package searxng
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/url"
+	"time"
+)
+
+// healthProbeQuery is the query CheckHealth sends to confirm the instance
+// answers searches with JSON.
+const healthProbeQuery = "searxng"
+
+// TLS states reported in HealthReport.TLS.
+const (
+	TLSValid   = "valid"
+	TLSInvalid = "invalid"
+	TLSUnknown = "unknown" // the instance could not be reached for another reason
+	TLSNone    = "none"    // plain http
+)
+
+// HealthReport is the outcome of CheckHealth.
+type HealthReport struct {
+	Instance string
+	// Reachable is set when the instance's root page loaded
+	Reachable    bool
+	ReachLatency time.Duration
+	// TLS is one of TLSValid, TLSInvalid, TLSUnknown or TLSNone
+	TLS string
+	// JSON is set when the probe search returned a JSON response
+	JSON         bool
+	ProbeLatency time.Duration
+	// Results is the number of results the probe search returned
+	Results int
+	// Err is the first check that failed, or nil if the instance is healthy
+	Err error
+}
+
+// Healthy reports whether every check passed.
+func (r *HealthReport) Healthy() bool {
+	return r.Err == nil
+}
+
+// CheckHealth checks that the instance is reachable with ValidateInstance
+// and that it answers a probe search with JSON. The checks use the
+// client's timeout and settings, as a real search would. The probe is
+// skipped when the instance is unreachable.
+//
+// Example:
+//
+//	report := client.CheckHealth(ctx)
+//	if !report.Healthy() {
+//	    log.Fatal(report.Err)
+//	}
+func (c *Client) CheckHealth(ctx context.Context) *HealthReport {
+	report := &HealthReport{Instance: c.instanceURL, TLS: TLSNone}
+	https := false
+	if u, err := url.Parse(c.instanceURL); err == nil && u.Scheme == "https" {
+		https = true
+	}
+
+	start := time.Now()
+	err := c.ValidateInstanceContext(ctx)
+	report.ReachLatency = time.Since(start)
+	if err != nil {
+		report.Err = err
+		if https {
+			report.TLS = TLSUnknown
+			if isCertificateError(err) {
+				report.TLS = TLSInvalid
+			}
+		}
+		return report
+	}
+	report.Reachable = true
+	if https {
+		report.TLS = TLSValid
+	}
+
+	start = time.Now()
+	resp, err := c.SearchContext(ctx, NewSearchRequest(healthProbeQuery))
+	report.ProbeLatency = time.Since(start)
+	if err != nil {
+		report.Err = err
+		return report
+	}
+	report.JSON = true
+	report.Results = len(resp.Results)
+	return report
+}
+
+// isCertificateError reports whether err comes from verifying the
+// instance's TLS certificate.
+func isCertificateError(err error) bool {
+	var verifyErr *tls.CertificateVerificationError
+	var unknownAuthority x509.UnknownAuthorityError
+	var invalid x509.CertificateInvalidError
+	var hostname x509.HostnameError
+	return errors.As(err, &verifyErr) ||
+		errors.As(err, &unknownAuthority) ||
+		errors.As(err, &invalid) ||
+		errors.As(err, &hostname)
+}
@@ -0,0 +1,71 @@
+package searxng
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mule-ai/search/internal/errors"
+)
+
+func TestCheckHealth(t *testing.T) {
+	handler := func(contentType string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/search" {
+				fmt.Fprint(w, "<html></html>")
+				return
+			}
+			w.Header().Set("Content-Type", contentType)
+			fmt.Fprint(w, `{"query":"searxng","results":[{"title":"SearXNG","url":"https://searxng.org"}]}`)
+		}
+	}
+
+	t.Run("healthy", func(t *testing.T) {
+		server := httptest.NewServer(handler("application/json"))
+		defer server.Close()
+
+		report := NewClientWithTimeout(server.URL, 5*time.Second).CheckHealth(context.Background())
+		if !report.Healthy() || !report.Reachable || !report.JSON || report.Results != 1 {
+			t.Errorf("expected a healthy report, got %+v", report)
+		}
+		if report.TLS != TLSNone {
+			t.Errorf("TLS = %q, want %q for plain http", report.TLS, TLSNone)
+		}
+	})
+
+	t.Run("html instead of json", func(t *testing.T) {
+		server := httptest.NewServer(handler("text/html"))
+		defer server.Close()
+
+		report := NewClientWithTimeout(server.URL, 5*time.Second).CheckHealth(context.Background())
+		if report.Healthy() || !report.Reachable || report.JSON {
+			t.Errorf("expected a reachable instance without JSON, got %+v", report)
+		}
+		if code := errors.GetErrorCode(report.Err); code != errors.ErrCodeInvalidResponse {
+			t.Errorf("error code = %s, want %s", code, errors.ErrCodeInvalidResponse)
+		}
+	})
+
+	t.Run("untrusted certificate", func(t *testing.T) {
+		server := httptest.NewTLSServer(handler("application/json"))
+		defer server.Close()
+
+		report := NewClientWithTimeout(server.URL, 5*time.Second).CheckHealth(context.Background())
+		if report.Healthy() || report.Reachable || report.TLS != TLSInvalid {
+			t.Errorf("expected an invalid certificate, got %+v", report)
+		}
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		server := httptest.NewServer(handler("application/json"))
+		server.Close()
+
+		report := NewClientWithTimeout(server.URL, 5*time.Second).CheckHealth(context.Background())
+		if report.Healthy() || report.Reachable || report.JSON {
+			t.Errorf("expected an unreachable instance, got %+v", report)
+		}
+	})
+}
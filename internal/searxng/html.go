@@ -0,0 +1,90 @@
+package searxng
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// htmlTag matches an HTML tag, comment or doctype. A tag name must follow
+// the "<" directly, so text like "a < b" or "<3" is left alone.
+var htmlTag = regexp.MustCompile(`<(/?[a-zA-Z][a-zA-Z0-9-]*|!)[^<>]*>`)
+
+// breakTags are the tags that separate words, so removing them must leave
+// a space rather than joining "line<br>break" into "linebreak".
+var breakTags = map[string]bool{
+	"br": true, "p": true, "div": true, "li": true, "tr": true, "td": true, "th": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+// StripHTML removes HTML tags from s and decodes its entities. Engines
+// sometimes send snippets with highlighting like <b> or line breaks like
+// <br>, which would otherwise show up as literal markup.
+//
+// Example:
+//
+//	searxng.StripHTML("<b>Go</b> &amp; Rust<br>compared") // "Go & Rust compared"
+func StripHTML(s string) string {
+	if !strings.ContainsAny(s, "<&") {
+		return s
+	}
+	s = htmlTag.ReplaceAllStringFunc(s, func(tag string) string {
+		name := strings.ToLower(strings.TrimPrefix(htmlTag.FindStringSubmatch(tag)[1], "/"))
+		if breakTags[name] {
+			return " "
+		}
+		return ""
+	})
+	// Entities are decoded last so an escaped "&lt;b&gt;" stays visible text
+	return html.UnescapeString(s)
+}
+
+// StripResultsHTML applies StripHTML to the title and content of each
+// result in place.
+func StripResultsHTML(results []SearchResult) {
+	for i := range results {
+		results[i].Title = StripHTML(results[i].Title)
+		results[i].Content = StripHTML(results[i].Content)
+	}
+}
+
+// HTMLStripper is a ResultProcessor that applies StripResultsHTML.
+type HTMLStripper struct{}
+
+// Process implements ResultProcessor.
+func (HTMLStripper) Process(results []SearchResult) []SearchResult {
+	StripResultsHTML(results)
+	return results
+}
+
+// FilterThinContent returns the results whose content, without HTML and
+// surrounding whitespace, is at least n runes long, since results with
+// empty or very short snippets are often low quality. n <= 0 keeps every
+// result, including those with no snippet.
+//
+// Example:
+//
+//	resp.Results = searxng.FilterThinContent(resp.Results, 40)
+func FilterThinContent(results []SearchResult, n int) []SearchResult {
+	if n <= 0 {
+		return results
+	}
+
+	kept := make([]SearchResult, 0, len(results))
+	for _, r := range results {
+		if utf8.RuneCountInString(strings.TrimSpace(StripHTML(r.Content))) >= n {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// ContentLengthFilter is a ResultProcessor that applies FilterThinContent
+// with its value as n.
+type ContentLengthFilter int
+
+// Process implements ResultProcessor.
+func (n ContentLengthFilter) Process(results []SearchResult) []SearchResult {
+	return FilterThinContent(results, int(n))
+}
@@ -0,0 +1,83 @@
+package searxng
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStripHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain text unchanged", "Go is an open source language", "Go is an open source language"},
+		{"highlighting", "Learn <b>Go</b> with <em>examples</em>", "Learn Go with examples"},
+		{"line break separates words", "first line<br>second line<br/>third", "first line second line third"},
+		{"attributes", `<a href="https://go.dev/" class="x">Go</a> site`, "Go site"},
+		{"paragraphs", "<p>One</p><p>Two</p>", " One  Two "},
+		{"entities", "Tom &amp; Jerry &quot;quoted&quot; &#39;single&#39; &eacute;", `Tom & Jerry "quoted" 'single' é`},
+		{"escaped markup stays visible", "use &lt;b&gt; for bold", "use <b> for bold"},
+		{"comparisons kept", "a < b and c > d, <3", "a < b and c > d, <3"},
+		{"comments removed", "before<!-- note -->after", "beforeafter"},
+		{"uppercase tags", "<B>Bold</B>", "Bold"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripHTML(tt.in); got != tt.want {
+				t.Errorf("StripHTML(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTMLStripper(t *testing.T) {
+	results := []SearchResult{
+		{Title: "<b>Rust</b> &amp; Go", Content: "Compare <em>Rust</em><br>and Go", URL: "https://example.com/?a=1&amp;b=2"},
+	}
+	got := HTMLStripper{}.Process(results)
+	if got[0].Title != "Rust & Go" || got[0].Content != "Compare Rust and Go" {
+		t.Errorf("got title %q, content %q", got[0].Title, got[0].Content)
+	}
+	if got[0].URL != "https://example.com/?a=1&amp;b=2" {
+		t.Errorf("URL should be left alone, got %q", got[0].URL)
+	}
+}
+
+func TestFilterThinContent(t *testing.T) {
+	results := []SearchResult{
+		{Title: "empty", Content: ""},
+		{Title: "blank", Content: "   "},
+		{Title: "short", Content: "Go"},
+		{Title: "markup", Content: "<b>Go</b><br>"},
+		{Title: "runes", Content: "日本語です"},
+		{Title: "long", Content: "Go is an open source programming language"},
+	}
+
+	tests := []struct {
+		n    int
+		want []string
+	}{
+		{0, []string{"empty", "blank", "short", "markup", "runes", "long"}},
+		{-1, []string{"empty", "blank", "short", "markup", "runes", "long"}},
+		{1, []string{"short", "markup", "runes", "long"}},
+		{3, []string{"runes", "long"}},
+		{5, []string{"runes", "long"}},
+		{6, []string{"long"}},
+		{100, []string{}},
+	}
+	for _, tt := range tests {
+		got := ContentLengthFilter(tt.n).Process(results)
+		titles := []string{}
+		for _, r := range got {
+			titles = append(titles, r.Title)
+		}
+		if strings.Join(titles, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("FilterThinContent(%d) kept %v, want %v", tt.n, titles, tt.want)
+		}
+	}
+	if results[3].Content != "<b>Go</b><br>" {
+		t.Errorf("content should be left alone, got %q", results[3].Content)
+	}
+}
@@ -0,0 +1,84 @@
+package searxng
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/mule-ai/search/internal/errors"
+)
+
+// instanceConfig mirrors the parts of the instance's /config response we
+// use.
+type instanceConfig struct {
+	Categories []string `json:"categories"`
+}
+
+// FetchCategories returns the categories the instance supports, in the
+// order its /config endpoint lists them. Instances add their own
+// categories, such as "q&a" or "repos", and may drop some of the built-in
+// ones, so the list can differ from ValidCategories.
+//
+// Example:
+//
+//	categories, err := client.FetchCategories(ctx)
+//	// categories == []string{"general", "images", "videos", ...}
+func (c *Client) FetchCategories(ctx context.Context) ([]string, error) {
+	u, err := url.Parse(c.instanceURL)
+	if err != nil {
+		return nil, errors.InvalidURL(c.instanceURL).WithErr(err)
+	}
+	u.Path = strings.TrimSuffix(strings.TrimSuffix(u.Path, "/search"), "/") + "/config"
+	u.RawQuery = ""
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeAPIError, "failed to create config request", err)
+	}
+	httpReq.Header.Set("User-Agent", c.userAgent)
+	httpReq.Header.Set("Accept", "application/json")
+	c.authorize(httpReq)
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, errors.Canceled(ctx.Err())
+		}
+		if se, ok := redirectError(err); ok {
+			return nil, se
+		}
+		return nil, errors.NetworkError(err)
+	}
+	body := newLimitedReader(resp.Body, c.maxResponseBytes)
+	defer func() {
+		io.Copy(io.Discard, body)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.HTTPStatusError(resp.StatusCode, resp.Status)
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		if err == errResponseTooLarge {
+			return nil, errors.ResponseTooLarge(c.maxResponseBytes)
+		}
+		return nil, errors.InvalidResponse(err)
+	}
+	var cfg instanceConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, errors.InvalidResponse(err)
+	}
+
+	categories := make([]string, 0, len(cfg.Categories))
+	for _, name := range cfg.Categories {
+		if name = strings.TrimSpace(name); name != "" {
+			categories = append(categories, name)
+		}
+	}
+	return categories, nil
+}
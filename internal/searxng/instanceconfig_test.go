@@ -0,0 +1,53 @@
+package searxng
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mule-ai/search/internal/errors"
+)
+
+func TestFetchCategories(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		want     []string
+		wantCode errors.ErrorCode
+	}{
+		{"categories", http.StatusOK, `{"categories": ["general", "images", "q&a", " "], "engines": []}`, []string{"general", "images", "q&a"}, ""},
+		{"no categories", http.StatusOK, `{"engines": []}`, []string{}, ""},
+		{"malformed", http.StatusOK, `<html>`, nil, errors.ErrCodeInvalidResponse},
+		{"server error", http.StatusBadGateway, ``, nil, errors.ErrCodeAPIUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			client := NewClientWithTimeout(server.URL+"/search", 5*time.Second)
+			got, err := client.FetchCategories(context.Background())
+
+			if gotPath != "/config" {
+				t.Errorf("request path = %s, want /config", gotPath)
+			}
+			if code := errors.GetErrorCode(err); code != tt.wantCode {
+				t.Errorf("error code = %q, want %q (err %v)", code, tt.wantCode, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FetchCategories() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
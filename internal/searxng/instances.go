@@ -0,0 +1,130 @@
+package searxng
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mule-ai/search/internal/errors"
+)
+
+// DefaultInstanceListURL is the searx.space list of public SearXNG instances.
+const DefaultInstanceListURL = "https://searx.space/data/instances.json"
+
+// maxInstanceListBytes bounds the instance list download; the list is a
+// few megabytes, larger than a typical search response.
+const maxInstanceListBytes = 32 << 20
+
+// PublicInstance is a public SearXNG instance reported by searx.space.
+type PublicInstance struct {
+	URL          string
+	Version      string
+	Reliability  float64 // Percentage of successful test searches
+	ResponseTime float64 // Median test search time in seconds
+}
+
+// instanceList mirrors the parts of the searx.space instances.json we use.
+type instanceList struct {
+	Instances map[string]struct {
+		NetworkType string `json:"network_type"`
+		Version     string `json:"version"`
+		HTTP        struct {
+			StatusCode int    `json:"status_code"`
+			Error      string `json:"error"`
+		} `json:"http"`
+		Timing struct {
+			Search *struct {
+				SuccessPercentage float64 `json:"success_percentage"`
+				All               struct {
+					Median float64 `json:"median"`
+				} `json:"all"`
+			} `json:"search"`
+		} `json:"timing"`
+	} `json:"instances"`
+}
+
+// FetchPublicInstances downloads the instance list from listURL and returns
+// the healthy HTTPS instances, most reliable first and fastest first among
+// equally reliable ones.
+//
+// An instance is healthy when it is reachable over the clear web, answered
+// its last check with HTTP 200 and completed at least one test search.
+// searx.space does not report whether an instance allows format=json, so
+// some returned instances may still reject API requests.
+//
+// Example:
+//
+//	instances, err := searxng.FetchPublicInstances(ctx, searxng.DefaultInstanceListURL, 30*time.Second)
+func FetchPublicInstances(ctx context.Context, listURL string, timeout time.Duration) ([]PublicInstance, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, listURL, nil)
+	if err != nil {
+		return nil, errors.InvalidURL(listURL).WithErr(err)
+	}
+	httpReq.Header.Set("User-Agent", defaultUserAgent)
+	httpReq.Header.Set("Accept", "application/json")
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, errors.Canceled(ctx.Err())
+		}
+		return nil, errors.NetworkError(err).
+			WithSuggestion("Could not fetch the public instance list. Check your connection or try again later")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.HTTPStatusError(resp.StatusCode, resp.Status).
+			WithSuggestion("The instance list is unavailable. Try again later")
+	}
+
+	var list instanceList
+	if err := json.NewDecoder(newLimitedReader(resp.Body, maxInstanceListBytes)).Decode(&list); err != nil {
+		if err == errResponseTooLarge {
+			return nil, errors.ResponseTooLarge(maxInstanceListBytes)
+		}
+		return nil, errors.InvalidResponse(err)
+	}
+
+	return filterPublicInstances(&list), nil
+}
+
+// filterPublicInstances keeps the healthy HTTPS instances from list and
+// sorts them by reliability, then response time.
+func filterPublicInstances(list *instanceList) []PublicInstance {
+	instances := make([]PublicInstance, 0, len(list.Instances))
+	for u, info := range list.Instances {
+		if !strings.HasPrefix(u, "https://") || info.NetworkType != "normal" {
+			continue
+		}
+		if info.HTTP.StatusCode != http.StatusOK || info.HTTP.Error != "" {
+			continue
+		}
+		search := info.Timing.Search
+		if search == nil || search.SuccessPercentage <= 0 {
+			continue
+		}
+		instances = append(instances, PublicInstance{
+			URL:          strings.TrimSuffix(u, "/"),
+			Version:      info.Version,
+			Reliability:  search.SuccessPercentage,
+			ResponseTime: search.All.Median,
+		})
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		a, b := instances[i], instances[j]
+		if a.Reliability != b.Reliability {
+			return a.Reliability > b.Reliability
+		}
+		if a.ResponseTime != b.ResponseTime {
+			return a.ResponseTime < b.ResponseTime
+		}
+		return a.URL < b.URL
+	})
+	return instances
+}
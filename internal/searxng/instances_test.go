@@ -0,0 +1,82 @@
+package searxng
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mule-ai/search/internal/errors"
+)
+
+const testInstanceList = `{
+  "instances": {
+    "https://slow.example/": {"network_type": "normal", "version": "2024.1",
+      "http": {"status_code": 200, "error": null},
+      "timing": {"search": {"success_percentage": 100, "all": {"median": 0.9}}}},
+    "https://fast.example/": {"network_type": "normal", "version": "2024.2",
+      "http": {"status_code": 200, "error": null},
+      "timing": {"search": {"success_percentage": 100, "all": {"median": 0.3}}}},
+    "https://flaky.example/": {"network_type": "normal",
+      "http": {"status_code": 200},
+      "timing": {"search": {"success_percentage": 60, "all": {"median": 0.1}}}},
+    "http://plain.example/": {"network_type": "normal",
+      "http": {"status_code": 200},
+      "timing": {"search": {"success_percentage": 100, "all": {"median": 0.1}}}},
+    "https://onion.example/": {"network_type": "tor",
+      "http": {"status_code": 200},
+      "timing": {"search": {"success_percentage": 100, "all": {"median": 0.1}}}},
+    "https://down.example/": {"network_type": "normal",
+      "http": {"status_code": 502, "error": "Bad Gateway"},
+      "timing": {}},
+    "https://untested.example/": {"network_type": "normal",
+      "http": {"status_code": 200},
+      "timing": {}}
+  }
+}`
+
+func TestFetchPublicInstances(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, testInstanceList)
+	}))
+	defer ts.Close()
+
+	instances, err := FetchPublicInstances(context.Background(), ts.URL, 5*time.Second)
+	if err != nil {
+		t.Fatalf("FetchPublicInstances() error = %v", err)
+	}
+
+	want := []string{"https://fast.example", "https://slow.example", "https://flaky.example"}
+	if len(instances) != len(want) {
+		t.Fatalf("got %d instances, want %d: %+v", len(instances), len(want), instances)
+	}
+	for i, u := range want {
+		if instances[i].URL != u {
+			t.Errorf("instance %d = %s, want %s", i, instances[i].URL, u)
+		}
+	}
+	if instances[0].Version != "2024.2" || instances[0].ResponseTime != 0.3 || instances[0].Reliability != 100 {
+		t.Errorf("unexpected instance details: %+v", instances[0])
+	}
+}
+
+func TestFetchPublicInstancesErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	_, err := FetchPublicInstances(context.Background(), ts.URL, 5*time.Second)
+	if errors.GetErrorCode(err) != errors.ErrCodeAPIUnavailable {
+		t.Errorf("Expected API_UNAVAILABLE for a 503, got %v", err)
+	}
+
+	ts.Close()
+	_, err = FetchPublicInstances(context.Background(), ts.URL, 5*time.Second)
+	if _, ok := errors.IsSearchError(err); !ok {
+		t.Errorf("Expected a SearchError when the list is unreachable, got %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package searxng
+
+import (
+	"strings"
+
+	"github.com/mule-ai/search/internal/langdetect"
+)
+
+// DetectLanguages sets DetectedLanguage on each result from its title and
+// content. Detection runs locally, but it scores every result against
+// each language profile, so callers should only run it when asked to.
+func DetectLanguages(results []SearchResult) {
+	for i := range results {
+		results[i].DetectedLanguage = langdetect.Detect(results[i].Title + " " + results[i].Content)
+	}
+}
+
+// FilterLanguage returns the results whose DetectedLanguage matches lang,
+// such as "de" or "de-DE" (only the language part is compared). Results
+// whose language could not be detected are kept, since there is no sign
+// they are in a different language. An empty lang returns results
+// unchanged.
+//
+// Example:
+//
+//	searxng.DetectLanguages(resp.Results)
+//	resp.Results = searxng.FilterLanguage(resp.Results, "de")
+func FilterLanguage(results []SearchResult, lang string) []SearchResult {
+	lang = baseLanguage(lang)
+	if lang == "" {
+		return results
+	}
+
+	kept := make([]SearchResult, 0, len(results))
+	for _, r := range results {
+		if r.DetectedLanguage == "" || r.DetectedLanguage == lang {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// baseLanguage reduces a language tag like "pt-BR" or "pt_BR" to "pt".
+func baseLanguage(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
+
+// LanguageDetector is a ResultProcessor that applies DetectLanguages.
+type LanguageDetector struct{}
+
+// Process implements ResultProcessor.
+func (LanguageDetector) Process(results []SearchResult) []SearchResult {
+	DetectLanguages(results)
+	return results
+}
+
+// LanguageFilter is a ResultProcessor that applies FilterLanguage with
+// its value as lang. Languages must already be detected.
+type LanguageFilter string
+
+// Process implements ResultProcessor.
+func (lang LanguageFilter) Process(results []SearchResult) []SearchResult {
+	return FilterLanguage(results, string(lang))
+}
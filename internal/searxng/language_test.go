@@ -0,0 +1,35 @@
+package searxng
+
+import "testing"
+
+func TestDetectAndFilterLanguage(t *testing.T) {
+	results := []SearchResult{
+		{Title: "The Go Programming Language", Content: "Go is an open source programming language that makes it simple to build secure, scalable systems."},
+		{Title: "Go (Programmiersprache)", Content: "Go ist eine Programmiersprache, die von Google entwickelt wurde und sich für nebenläufige Anwendungen eignet."},
+		{Title: "Go", Content: ""},
+	}
+	DetectLanguages(results)
+
+	want := []string{"en", "de", ""}
+	for i, r := range results {
+		if r.DetectedLanguage != want[i] {
+			t.Errorf("result %d detected as %q, want %q", i, r.DetectedLanguage, want[i])
+		}
+	}
+
+	tests := []struct {
+		lang string
+		want int
+	}{
+		{"de", 2},
+		{"de-DE", 2},
+		{"EN", 2},
+		{"fr", 1},
+		{"", 3},
+	}
+	for _, tt := range tests {
+		if got := FilterLanguage(results, tt.lang); len(got) != tt.want {
+			t.Errorf("FilterLanguage(%q) kept %d results, want %d", tt.lang, len(got), tt.want)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package searxng
+
+import (
+	"context"
+
+	"github.com/mule-ai/search/internal/errors"
+)
+
+// Limiter bounds how many searches run at once. Share one Limiter between
+// clients to cap the total load a command puts on instances.
+type Limiter struct {
+	slots chan struct{}
+}
+
+// NewLimiter returns a Limiter that allows n concurrent searches. Values
+// below 1 are treated as 1.
+//
+// Example:
+//
+//	limiter := searxng.NewLimiter(cfg.Concurrency)
+//	for _, instance := range instances {
+//	    client := searxng.NewClientWithTimeout(instance, timeout)
+//	    client.SetLimiter(limiter)
+//	}
+func NewLimiter(n int) *Limiter {
+	if n < 1 {
+		n = 1
+	}
+	return &Limiter{slots: make(chan struct{}, n)}
+}
+
+// Limit returns the number of searches allowed at once.
+func (l *Limiter) Limit() int {
+	return cap(l.slots)
+}
+
+// Acquire blocks until a slot is free or ctx is done.
+func (l *Limiter) Acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return errors.Canceled(ctx.Err())
+	}
+}
+
+// Release frees a slot taken by Acquire.
+func (l *Limiter) Release() {
+	<-l.slots
+}
@@ -0,0 +1,76 @@
+package searxng
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mule-ai/search/internal/errors"
+)
+
+func TestNewLimiter(t *testing.T) {
+	if got := NewLimiter(3).Limit(); got != 3 {
+		t.Errorf("Limit() = %d, want 3", got)
+	}
+	if got := NewLimiter(0).Limit(); got != 1 {
+		t.Errorf("Limit() for 0 = %d, want 1", got)
+	}
+}
+
+func TestLimiterAcquireCanceled(t *testing.T) {
+	l := NewLimiter(1)
+	if err := l.Acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer l.Release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := l.Acquire(ctx)
+	if errors.GetErrorCode(err) != errors.ErrCodeCanceled {
+		t.Errorf("expected CANCELED while the only slot is taken, got %v", err)
+	}
+}
+
+func TestClientLimiterBoundsConcurrentSearches(t *testing.T) {
+	var inFlight, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"query":"golang","results":[]}`)
+	}))
+	defer server.Close()
+
+	// Several clients sharing one limiter are bounded together
+	limiter := NewLimiter(2)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client := NewClientWithTimeout(server.URL, 5*time.Second)
+			client.SetLimiter(limiter)
+			if _, err := client.Search(NewSearchRequest("golang")); err != nil {
+				t.Errorf("search failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("expected at most 2 searches in flight, saw %d", peak)
+	}
+}
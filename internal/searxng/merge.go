@@ -0,0 +1,92 @@
+package searxng
+
+// MergeResponses combines responses into one, for searches spread over
+// several pages or instances. Nil responses are skipped.
+//
+// Results, answers, infoboxes and unresponsive engines are concatenated
+// in order; duplicate results are kept, since deciding which copy wins is
+// up to the caller. Suggestions and corrections are unioned, keeping the
+// first occurrence of each.
+//
+// NumberOfResults is the largest reported by any response, and at least
+// the number of merged results: every instance estimates the size of the
+// same result set, so summing would count it more than once. SearchTime
+// is the longest of the responses, as when they are fetched at once.
+// Query, Page and Instance come from the first response that sets them,
+// Instances lists every distinct instance used, and the merged response is
+// Partial if any of them is.
+//
+// Example:
+//
+//	first, _ := client.Search(searxng.NewSearchRequest("golang"))
+//	req := searxng.NewSearchRequest("golang")
+//	req.Page = 2
+//	second, _ := client.Search(req)
+//	resp := searxng.MergeResponses(first, second)
+func MergeResponses(responses ...*SearchResponse) *SearchResponse {
+	merged := &SearchResponse{
+		Results:             []SearchResult{},
+		Answers:             []Answer{},
+		Infoboxes:           []Infobox{},
+		Suggestions:         []string{},
+		Corrections:         []string{},
+		UnresponsiveEngines: [][]string{},
+	}
+	seenSuggestions := make(map[string]bool)
+	seenCorrections := make(map[string]bool)
+	seenInstances := make(map[string]bool)
+
+	for _, resp := range responses {
+		if resp == nil {
+			continue
+		}
+		if merged.Query == "" {
+			merged.Query = resp.Query
+		}
+		if merged.Page == 0 {
+			merged.Page = resp.Page
+		}
+
+		merged.Results = append(merged.Results, resp.Results...)
+		merged.Answers = append(merged.Answers, resp.Answers...)
+		merged.Infoboxes = append(merged.Infoboxes, resp.Infoboxes...)
+		merged.UnresponsiveEngines = append(merged.UnresponsiveEngines, resp.UnresponsiveEngines...)
+		merged.Suggestions = appendUnique(merged.Suggestions, seenSuggestions, resp.Suggestions...)
+		merged.Corrections = appendUnique(merged.Corrections, seenCorrections, resp.Corrections...)
+
+		instances := resp.Instances
+		if len(instances) == 0 && resp.Instance != "" {
+			instances = []string{resp.Instance}
+		}
+		merged.Instances = appendUnique(merged.Instances, seenInstances, instances...)
+
+		if resp.NumberOfResults > merged.NumberOfResults {
+			merged.NumberOfResults = resp.NumberOfResults
+		}
+		if resp.SearchTime > merged.SearchTime {
+			merged.SearchTime = resp.SearchTime
+		}
+		merged.Partial = merged.Partial || resp.Partial
+	}
+
+	if len(merged.Instances) > 0 {
+		merged.Instance = merged.Instances[0]
+	}
+	if merged.NumberOfResults < len(merged.Results) {
+		merged.NumberOfResults = len(merged.Results)
+	}
+	return merged
+}
+
+// appendUnique appends the values not yet in seen to list, marking them
+// seen. Empty values are skipped.
+func appendUnique(list []string, seen map[string]bool, values ...string) []string {
+	for _, v := range values {
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		list = append(list, v)
+	}
+	return list
+}
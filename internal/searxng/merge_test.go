@@ -0,0 +1,122 @@
+package searxng
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeResponsesEmpty(t *testing.T) {
+	for name, responses := range map[string][]*SearchResponse{
+		"no responses":  nil,
+		"nil responses": {nil, nil},
+	} {
+		t.Run(name, func(t *testing.T) {
+			merged := MergeResponses(responses...)
+			if merged == nil {
+				t.Fatal("MergeResponses() = nil, want an empty response")
+			}
+			if merged.Results == nil || merged.Answers == nil || merged.Infoboxes == nil ||
+				merged.Suggestions == nil || merged.Corrections == nil || merged.UnresponsiveEngines == nil {
+				t.Errorf("expected initialized slices, got %+v", merged)
+			}
+			if merged.NumberOfResults != 0 || merged.Instance != "" || len(merged.Instances) != 0 {
+				t.Errorf("expected no results or instances, got %+v", merged)
+			}
+		})
+	}
+}
+
+func TestMergeResponsesSingle(t *testing.T) {
+	resp := &SearchResponse{
+		Query:           "golang",
+		Results:         []SearchResult{{Title: "Go", URL: "https://go.dev"}},
+		Answers:         []Answer{{Answer: "Go is a language"}},
+		Suggestions:     []string{"golang tutorial"},
+		NumberOfResults: 1200,
+		SearchTime:      0.4,
+		Page:            2,
+		Instance:        "https://searx.example",
+	}
+
+	merged := MergeResponses(resp)
+	if merged == resp {
+		t.Fatal("MergeResponses() should return a new response")
+	}
+	if merged.Query != "golang" || merged.Page != 2 || merged.SearchTime != 0.4 || merged.NumberOfResults != 1200 {
+		t.Errorf("metadata not carried over: %+v", merged)
+	}
+	if !reflect.DeepEqual(merged.Results, resp.Results) || !reflect.DeepEqual(merged.Answers, resp.Answers) {
+		t.Errorf("results or answers not carried over: %+v", merged)
+	}
+	if merged.Instance != "https://searx.example" || !reflect.DeepEqual(merged.Instances, []string{"https://searx.example"}) {
+		t.Errorf("instance = %q, instances = %v", merged.Instance, merged.Instances)
+	}
+}
+
+func TestMergeResponsesOverlapping(t *testing.T) {
+	shared := SearchResult{Title: "Go", URL: "https://go.dev"}
+	a := &SearchResponse{
+		Query:               "golang",
+		Results:             []SearchResult{shared, {Title: "Tour", URL: "https://go.dev/tour/"}},
+		Infoboxes:           []Infobox{{Infobox: "Go"}},
+		Suggestions:         []string{"golang tutorial", "golang jobs"},
+		Corrections:         []string{"go lang"},
+		UnresponsiveEngines: [][]string{{"bing", "timeout"}},
+		NumberOfResults:     1000,
+		SearchTime:          0.2,
+		Instance:            "https://a.example",
+	}
+	b := &SearchResponse{
+		Query:           "golang",
+		Results:         []SearchResult{shared, {Title: "Docs", URL: "https://go.dev/doc/"}},
+		Infoboxes:       []Infobox{{Infobox: "Gopher"}},
+		Suggestions:     []string{"golang jobs", "", "golang generics"},
+		Corrections:     []string{"go lang"},
+		NumberOfResults: 3000,
+		SearchTime:      0.9,
+		Instance:        "https://b.example",
+	}
+	c := &SearchResponse{
+		Results:   []SearchResult{{Title: "Blog", URL: "https://go.dev/blog/"}},
+		Instances: []string{"https://b.example", "https://c.example"},
+	}
+
+	merged := MergeResponses(a, nil, b, c)
+
+	var urls []string
+	for _, r := range merged.Results {
+		urls = append(urls, r.URL)
+	}
+	wantURLs := []string{"https://go.dev", "https://go.dev/tour/", "https://go.dev", "https://go.dev/doc/", "https://go.dev/blog/"}
+	if !reflect.DeepEqual(urls, wantURLs) {
+		t.Errorf("result URLs = %v, want %v", urls, wantURLs)
+	}
+	if want := []string{"golang tutorial", "golang jobs", "golang generics"}; !reflect.DeepEqual(merged.Suggestions, want) {
+		t.Errorf("suggestions = %v, want %v", merged.Suggestions, want)
+	}
+	if want := []string{"go lang"}; !reflect.DeepEqual(merged.Corrections, want) {
+		t.Errorf("corrections = %v, want %v", merged.Corrections, want)
+	}
+	if len(merged.Infoboxes) != 2 || len(merged.UnresponsiveEngines) != 1 {
+		t.Errorf("expected 2 infoboxes and 1 unresponsive engine, got %d and %d", len(merged.Infoboxes), len(merged.UnresponsiveEngines))
+	}
+	if merged.NumberOfResults != 3000 {
+		t.Errorf("NumberOfResults = %d, want the largest estimate 3000", merged.NumberOfResults)
+	}
+	if merged.SearchTime != 0.9 {
+		t.Errorf("SearchTime = %v, want the longest 0.9", merged.SearchTime)
+	}
+	wantInstances := []string{"https://a.example", "https://b.example", "https://c.example"}
+	if !reflect.DeepEqual(merged.Instances, wantInstances) || merged.Instance != "https://a.example" {
+		t.Errorf("instance = %q, instances = %v, want %v", merged.Instance, merged.Instances, wantInstances)
+	}
+}
+
+func TestMergeResponsesCountsAtLeastResults(t *testing.T) {
+	// Instances that omit number_of_results still total their results
+	a := &SearchResponse{Results: []SearchResult{{URL: "https://a.example"}}}
+	b := &SearchResponse{Results: []SearchResult{{URL: "https://b.example"}, {URL: "https://c.example"}}}
+	if got := MergeResponses(a, b).NumberOfResults; got != 3 {
+		t.Errorf("NumberOfResults = %d, want 3", got)
+	}
+}
@@ -0,0 +1,34 @@
+package searxng
+
+// ResultProcessor is one transform on a result list, such as a filter, a
+// cap or a rewrite. Process may modify results in place and returns the
+// results to keep.
+type ResultProcessor interface {
+	Process(results []SearchResult) []SearchResult
+}
+
+// ProcessorFunc adapts a function to a ResultProcessor.
+type ProcessorFunc func(results []SearchResult) []SearchResult
+
+// Process calls f(results).
+func (f ProcessorFunc) Process(results []SearchResult) []SearchResult {
+	return f(results)
+}
+
+// Pipeline applies its processors in order, each to the results of the
+// one before. Order matters: a per-engine cap after a date filter keeps
+// the best in-range results, while the reverse can keep none.
+//
+// Example:
+//
+//	pipeline := searxng.Pipeline{searxng.DateFilter{Since: since}, searxng.EngineLimit(3)}
+//	resp.Results = pipeline.Process(resp.Results)
+type Pipeline []ResultProcessor
+
+// Process runs results through each processor in turn.
+func (p Pipeline) Process(results []SearchResult) []SearchResult {
+	for _, processor := range p {
+		results = processor.Process(results)
+	}
+	return results
+}
@@ -0,0 +1,69 @@
+package searxng
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPipeline(t *testing.T) {
+	day := func(d int) *time.Time {
+		t := time.Date(2024, 6, d, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+	newResults := func() []SearchResult {
+		return []SearchResult{
+			{Title: "g1", URL: "https://a.example/?utm_source=x", Engine: "google", Score: 4, PublishedDate: day(1)},
+			{Title: "g2", URL: "https://b.example/", Engine: "google", Score: 3, PublishedDate: day(20)},
+			{Title: "g3", URL: "https://c.example/?utm_medium=y", Engine: "google", Score: 2, PublishedDate: day(21)},
+			{Title: "b1", URL: "https://d.example/", Engine: "bing", Score: 1, PublishedDate: day(22)},
+		}
+	}
+	summary := func(rs []SearchResult) string {
+		var parts []string
+		for _, r := range rs {
+			parts = append(parts, r.Title+"="+r.URL)
+		}
+		return strings.Join(parts, " ")
+	}
+
+	since := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	var order []string
+	trace := func(name string) ResultProcessor {
+		return ProcessorFunc(func(rs []SearchResult) []SearchResult {
+			order = append(order, name)
+			return rs
+		})
+	}
+
+	pipeline := Pipeline{
+		trace("start"),
+		DateFilter{Since: since},
+		EngineLimit(1),
+		TrackingStripper{},
+		ScoreNormalizer{},
+		trace("end"),
+	}
+	got := pipeline.Process(newResults())
+
+	// The date filter drops g1 first, so the cap keeps g2 rather than nothing
+	if want := "g2=https://b.example/ b1=https://d.example/"; summary(got) != want {
+		t.Errorf("pipeline = %q, want %q", summary(got), want)
+	}
+	if got[0].Score != 1 || got[1].Score != 0 {
+		t.Errorf("scores were not normalized: %v, %v", got[0].Score, got[1].Score)
+	}
+	if strings.Join(order, ",") != "start,end" {
+		t.Errorf("processors ran out of order: %v", order)
+	}
+
+	// Capping before filtering keeps only google's best, which is out of range
+	reversed := Pipeline{EngineLimit(1), DateFilter{Since: since}}
+	if got := summary(reversed.Process(newResults())); got != "b1=https://d.example/" {
+		t.Errorf("reversed pipeline = %q", got)
+	}
+
+	if got := Pipeline(nil).Process(newResults()); len(got) != 4 {
+		t.Errorf("empty pipeline kept %d results, want 4", len(got))
+	}
+}
@@ -0,0 +1,81 @@
+package searxng
+
+import (
+	"strings"
+)
+
+// QueryOpts holds search operators to add to a query. Empty fields are
+// left out.
+type QueryOpts struct {
+	Site     string // Restrict to a domain: site:example.com
+	FileType string // Restrict to a file type: filetype:pdf
+	InTitle  string // Require words in the title: intitle:"release notes"
+}
+
+// BuildQuery returns base with the operators from opts appended.
+//
+// Values are normalized first: a scheme or trailing slash is dropped from
+// Site and a leading dot from FileType, and InTitle is quoted when it
+// contains spaces. An operator already present in base is not added again.
+//
+// Example:
+//
+//	q := searxng.BuildQuery("tutorial", searxng.QueryOpts{Site: "https://go.dev/", FileType: ".pdf"})
+//	// q == "tutorial site:go.dev filetype:pdf"
+func BuildQuery(base string, opts QueryOpts) string {
+	query := strings.TrimSpace(base)
+
+	var terms []string
+	if site := normalizeSite(opts.Site); site != "" {
+		terms = append(terms, "site:"+site)
+	}
+	if fileType := strings.TrimPrefix(strings.TrimSpace(opts.FileType), "."); fileType != "" {
+		terms = append(terms, "filetype:"+fileType)
+	}
+	if title := strings.Trim(strings.TrimSpace(opts.InTitle), `"`); title != "" {
+		if strings.ContainsAny(title, " \t") {
+			title = `"` + title + `"`
+		}
+		terms = append(terms, "intitle:"+title)
+	}
+
+	for _, term := range terms {
+		if containsTerm(query, term) {
+			continue
+		}
+		if query == "" {
+			query = term
+		} else {
+			query += " " + term
+		}
+	}
+	return query
+}
+
+// normalizeSite reduces a --site value like "https://example.com/" to
+// the bare domain the site: operator expects.
+func normalizeSite(site string) string {
+	site = strings.TrimSpace(site)
+	for _, scheme := range []string{"https://", "http://"} {
+		if len(site) >= len(scheme) && strings.EqualFold(site[:len(scheme)], scheme) {
+			site = site[len(scheme):]
+			break
+		}
+	}
+	return strings.TrimSuffix(site, "/")
+}
+
+// containsTerm reports whether query already has term, ignoring case.
+// Single-word terms must match a whole word so that site:go.dev is not
+// mistaken for site:go.dev.example.
+func containsTerm(query, term string) bool {
+	if strings.ContainsAny(term, " \t") {
+		return strings.Contains(strings.ToLower(query), strings.ToLower(term))
+	}
+	for _, field := range strings.Fields(query) {
+		if strings.EqualFold(field, term) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package searxng
+
+import "testing"
+
+func TestBuildQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		base string
+		opts QueryOpts
+		want string
+	}{
+		{"no operators", "golang", QueryOpts{}, "golang"},
+		{"site", "golang", QueryOpts{Site: "go.dev"}, "golang site:go.dev"},
+		{"site with scheme and slash", "golang", QueryOpts{Site: "https://go.dev/"}, "golang site:go.dev"},
+		{"filetype with dot", "report", QueryOpts{FileType: ".pdf"}, "report filetype:pdf"},
+		{"single word title", "go", QueryOpts{InTitle: "release"}, "go intitle:release"},
+		{"quoted multi-word title", "go", QueryOpts{InTitle: "release notes"}, `go intitle:"release notes"`},
+		{
+			"all operators in order",
+			"golang",
+			QueryOpts{Site: "go.dev", FileType: "pdf", InTitle: "spec"},
+			"golang site:go.dev filetype:pdf intitle:spec",
+		},
+		{"already typed", "golang site:go.dev", QueryOpts{Site: "go.dev"}, "golang site:go.dev"},
+		{"already typed other case", "golang SITE:Go.Dev", QueryOpts{Site: "go.dev"}, "golang SITE:Go.Dev"},
+		{"different site kept", "golang site:go.dev.example", QueryOpts{Site: "go.dev"}, "golang site:go.dev.example site:go.dev"},
+		{"already typed quoted title", `go intitle:"release notes"`, QueryOpts{InTitle: "release notes"}, `go intitle:"release notes"`},
+		{"empty base", "", QueryOpts{Site: "go.dev"}, "site:go.dev"},
+		{"whitespace values ignored", "golang", QueryOpts{Site: "  ", FileType: " "}, "golang"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BuildQuery(tt.base, tt.opts); got != tt.want {
+				t.Errorf("BuildQuery() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package searxng
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mule-ai/search/internal/errors"
+)
+
+// RateLimitNotify is called when a request has to wait for the rate
+// limiter, with how long it will wait.
+type RateLimitNotify func(wait time.Duration)
+
+// RateLimiter spaces requests out to at most a given number per second.
+// It is a token bucket holding a single token, refilled every 1/rate
+// seconds, so the first request goes out at once and later ones wait
+// their turn. Share one RateLimiter between clients and goroutines to cap
+// the combined rate they send to an instance.
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time // when the token is next available
+}
+
+// NewRateLimiter returns a RateLimiter allowing perSecond requests per
+// second, or nil, which never waits, when perSecond is not positive.
+//
+// Example:
+//
+//	limiter := searxng.NewRateLimiter(cfg.RateLimit)
+//	client.SetRateLimiter(limiter, nil)
+func NewRateLimiter(perSecond float64) *RateLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &RateLimiter{interval: time.Duration(float64(time.Second) / perSecond)}
+}
+
+// Interval returns the spacing enforced between requests.
+func (r *RateLimiter) Interval() time.Duration {
+	if r == nil {
+		return 0
+	}
+	return r.interval
+}
+
+// Wait blocks until the token is available or ctx is done, and returns
+// how long it had to wait. A nil RateLimiter returns at once.
+func (r *RateLimiter) Wait(ctx context.Context) (time.Duration, error) {
+	if r == nil {
+		return 0, nil
+	}
+	wait := r.reserve(time.Now())
+	return wait, sleep(ctx, wait)
+}
+
+// reserve takes the next token and returns how long after now it becomes
+// available.
+func (r *RateLimiter) reserve(now time.Time) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	at := r.next
+	if at.Before(now) {
+		at = now
+	}
+	r.next = at.Add(r.interval)
+	return at.Sub(now)
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return errors.Canceled(ctx.Err())
+	}
+}
+
+// SetRateLimiter makes every request the client sends, retries included,
+// wait for r first. notify, if not nil, is called whenever a request has
+// to wait. Pass a nil r to disable.
+//
+// Example:
+//
+//	client.SetRateLimiter(searxng.NewRateLimiter(1), func(wait time.Duration) {
+//	    fmt.Fprintf(os.Stderr, "Waiting %s to respect the rate limit\n", wait)
+//	})
+func (c *Client) SetRateLimiter(r *RateLimiter, notify RateLimitNotify) {
+	c.rateLimiter = r
+	c.rateLimitNotify = notify
+}
+
+// waitForRateLimit waits for the client's rate limiter, if any.
+func (c *Client) waitForRateLimit(ctx context.Context) error {
+	if c.rateLimiter == nil {
+		return nil
+	}
+	wait := c.rateLimiter.reserve(time.Now())
+	if wait > 0 && c.rateLimitNotify != nil {
+		c.rateLimitNotify(wait)
+	}
+	return sleep(ctx, wait)
+}
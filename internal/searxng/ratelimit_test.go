@@ -0,0 +1,96 @@
+package searxng
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mule-ai/search/internal/errors"
+)
+
+func TestNewRateLimiter(t *testing.T) {
+	if got := NewRateLimiter(4).Interval(); got != 250*time.Millisecond {
+		t.Errorf("Interval() = %s, want 250ms", got)
+	}
+	for _, rate := range []float64{0, -1} {
+		if r := NewRateLimiter(rate); r != nil {
+			t.Errorf("NewRateLimiter(%v) = %v, want nil", rate, r)
+		}
+	}
+
+	var r *RateLimiter
+	if wait, err := r.Wait(context.Background()); wait != 0 || err != nil {
+		t.Errorf("nil Wait() = %s, %v; want no wait", wait, err)
+	}
+}
+
+func TestRateLimiterWaitCanceled(t *testing.T) {
+	r := NewRateLimiter(0.1)
+	if wait, err := r.Wait(context.Background()); wait != 0 || err != nil {
+		t.Fatalf("first Wait() = %s, %v; want no wait", wait, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := r.Wait(ctx)
+	if errors.GetErrorCode(err) != errors.ErrCodeCanceled {
+		t.Errorf("expected CANCELED while waiting for the token, got %v", err)
+	}
+}
+
+func TestClientRateLimiterSpacesRequests(t *testing.T) {
+	var mu sync.Mutex
+	var arrivals []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		arrivals = append(arrivals, time.Now())
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"query":"golang","number_of_results":0,"results":[]}`)
+	}))
+	defer server.Close()
+
+	const interval = 100 * time.Millisecond
+	limiter := NewRateLimiter(float64(time.Second / interval))
+	var notified []time.Duration
+	var notifyMu sync.Mutex
+
+	// Concurrent workers with their own clients share one limiter
+	const workers = 4
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		client := NewClientWithTimeout(server.URL, 5*time.Second)
+		client.SetRateLimiter(limiter, func(wait time.Duration) {
+			notifyMu.Lock()
+			notified = append(notified, wait)
+			notifyMu.Unlock()
+		})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Search(NewSearchRequest("golang")); err != nil {
+				t.Errorf("Search() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(arrivals) != workers {
+		t.Fatalf("got %d requests, want %d", len(arrivals), workers)
+	}
+	sort.Slice(arrivals, func(i, j int) bool { return arrivals[i].Before(arrivals[j]) })
+	// A late wakeup can bring two requests closer than the interval, but
+	// never the whole run under its total spacing
+	want := (workers - 1) * interval
+	if spread := arrivals[workers-1].Sub(arrivals[0]); spread < want-10*time.Millisecond {
+		t.Errorf("%d requests arrived within %s, want them spread over at least %s", workers, spread, want)
+	}
+	if len(notified) != workers-1 {
+		t.Errorf("expected %d waiting notifications, got %d", workers-1, len(notified))
+	}
+}
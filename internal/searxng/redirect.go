@@ -0,0 +1,62 @@
+package searxng
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/mule-ai/search/internal/errors"
+)
+
+// checkRedirect returns an http.Client CheckRedirect function that
+// follows at most max redirects and never forwards the client's
+// credentials to a host other than the one first requested, or over
+// plain HTTP when the first request used HTTPS: the Authorization header
+// and any HMAC signature are dropped. Any other same-host redirect is
+// signed again for its new path and query.
+func (c *Client) checkRedirect(max int) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) > max {
+			return errors.TooManyRedirects(max, req.URL.Redacted())
+		}
+		// Keep an API key for one instance away from wherever it sends us,
+		// and out of cleartext
+		downgrade := via[0].URL.Scheme == "https" && req.URL.Scheme != "https"
+		if req.URL.Host != via[0].URL.Host || downgrade {
+			req.Header.Del("Authorization")
+			if c.hmacSecret != "" {
+				req.Header.Del(c.hmacHeader)
+			}
+			return nil
+		}
+		// A signature only holds for the URI it was computed over
+		if c.hmacSecret != "" {
+			req.Header.Set(c.hmacHeader, Sign(c.hmacSecret, req.URL.RequestURI()))
+		}
+		return nil
+	}
+}
+
+// SetMaxRedirects sets how many redirects the client follows before
+// failing with TOO_MANY_REDIRECTS; 0 follows none. The Authorization
+// header and HMAC signature are dropped whenever a redirect leads to a
+// different host or from HTTPS to HTTP.
+func (c *Client) SetMaxRedirects(n int) {
+	if n < 0 {
+		n = 0
+	}
+	c.client.CheckRedirect = c.checkRedirect(n)
+}
+
+// redirectError returns the error checkRedirect stopped a request with,
+// which http.Client.Do wraps in a *url.Error.
+func redirectError(err error) (*errors.SearchError, bool) {
+	urlErr, ok := err.(*url.Error)
+	if !ok {
+		return nil, false
+	}
+	se, ok := urlErr.Err.(*errors.SearchError)
+	if !ok || se.Code != errors.ErrCodeTooManyRedirects {
+		return nil, false
+	}
+	return se, true
+}
@@ -0,0 +1,176 @@
+package searxng
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mule-ai/search/internal/config"
+	"github.com/mule-ai/search/internal/errors"
+)
+
+// redirectingServer redirects /search to /moved on the same server, or
+// to other when it is set, and records the Authorization header /moved
+// receives.
+func redirectingServer(t *testing.T, other string, gotAuth *string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, other+"/moved?"+r.URL.RawQuery, http.StatusFound)
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		*gotAuth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"query":"test","results":[]}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRedirectAuthorization(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.APIKey = "secret"
+
+	// A different port is a different host
+	var otherAuth string
+	other := redirectingServer(t, "", &otherAuth)
+	var unused string
+	cfg.Instance = redirectingServer(t, other.URL, &unused).URL
+	if _, err := NewClient(cfg).Search(NewSearchRequest("test")); err != nil {
+		t.Fatalf("Search() unexpected error: %v", err)
+	}
+	if otherAuth != "" {
+		t.Errorf("Authorization forwarded to another host: %q", otherAuth)
+	}
+
+	var sameAuth string
+	cfg.Instance = redirectingServer(t, "", &sameAuth).URL
+	if _, err := NewClient(cfg).Search(NewSearchRequest("test")); err != nil {
+		t.Fatalf("Search() unexpected error: %v", err)
+	}
+	if sameAuth != "Bearer secret" {
+		t.Errorf("Authorization on a same-host redirect = %q, want it kept", sameAuth)
+	}
+}
+
+func TestRedirectHMACSignature(t *testing.T) {
+	// signedServer redirects /search like redirectingServer and records
+	// the signature and URI /moved receives
+	signedServer := func(other string, gotSig, gotURI *string) *httptest.Server {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, other+"/moved?"+r.URL.RawQuery, http.StatusFound)
+		})
+		mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+			*gotSig, *gotURI = r.Header.Get(config.DefaultAuthHeader), r.URL.RequestURI()
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"query":"test","results":[]}`))
+		})
+		server := httptest.NewServer(mux)
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.AuthType = config.AuthTypeHMAC
+	cfg.AuthSecret = "s3cret"
+
+	var otherSig, otherURI, unused string
+	other := signedServer("", &otherSig, &otherURI)
+	cfg.Instance = signedServer(other.URL, &unused, &unused).URL
+	if _, err := NewClient(cfg).Search(NewSearchRequest("test")); err != nil {
+		t.Fatalf("Search() unexpected error: %v", err)
+	}
+	if otherSig != "" {
+		t.Errorf("signature forwarded to another host: %q", otherSig)
+	}
+
+	var sameSig, sameURI string
+	cfg.Instance = signedServer("", &sameSig, &sameURI).URL
+	if _, err := NewClient(cfg).Search(NewSearchRequest("test")); err != nil {
+		t.Fatalf("Search() unexpected error: %v", err)
+	}
+	if want := Sign("s3cret", sameURI); sameSig != want {
+		t.Errorf("signature on a same-host redirect = %q, want %q for %s", sameSig, want, sameURI)
+	}
+}
+
+func TestRedirectDowngrade(t *testing.T) {
+	// redirect runs the client's redirect check for a hop from https to to
+	// and returns the credentials left on the redirected request
+	redirect := func(c *Client, to string) (auth, sig string) {
+		t.Helper()
+		first, _ := http.NewRequest(http.MethodGet, "https://search.example/search?q=test", nil)
+		c.authorize(first)
+		req, _ := http.NewRequest(http.MethodGet, to, nil)
+		req.Header = first.Header.Clone()
+		if err := c.checkRedirect(10)(req, []*http.Request{first}); err != nil {
+			t.Fatalf("checkRedirect() unexpected error: %v", err)
+		}
+		return req.Header.Get("Authorization"), req.Header.Get(config.DefaultAuthHeader)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.APIKey = "secret"
+	bearer := NewClient(cfg)
+	if auth, _ := redirect(bearer, "http://search.example/moved?q=test"); auth != "" {
+		t.Errorf("Authorization sent in cleartext after a downgrade: %q", auth)
+	}
+	if auth, _ := redirect(bearer, "https://search.example/moved?q=test"); auth != "Bearer secret" {
+		t.Errorf("Authorization on an https redirect = %q, want it kept", auth)
+	}
+
+	hmacClient := NewClient(cfg)
+	hmacClient.SetHMACAuth("s3cret", "")
+	if _, sig := redirect(hmacClient, "http://search.example/moved?q=test"); sig != "" {
+		t.Errorf("signature sent in cleartext after a downgrade: %q", sig)
+	}
+	if _, sig := redirect(hmacClient, "https://search.example/moved?q=test"); sig != Sign("s3cret", "/moved?q=test") {
+		t.Errorf("signature on an https redirect = %q, want it re-signed", sig)
+	}
+}
+
+func TestMaxRedirects(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		http.Redirect(w, r, r.URL.RequestURI(), http.StatusFound)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		max  int
+		want int32
+	}{
+		{0, 1},
+		{2, 3},
+		{config.DefaultMaxRedirects, config.DefaultMaxRedirects + 1},
+	}
+
+	for _, tt := range tests {
+		atomic.StoreInt32(&hits, 0)
+		cfg := config.DefaultConfig()
+		cfg.Instance = server.URL
+		cfg.MaxRedirects = tt.max
+		_, err := NewClient(cfg).Search(NewSearchRequest("test"))
+		if code := errors.GetErrorCode(err); code != errors.ErrCodeTooManyRedirects {
+			t.Errorf("max %d: error code = %q (%v), want %s", tt.max, code, err, errors.ErrCodeTooManyRedirects)
+		}
+		if got := atomic.LoadInt32(&hits); got != tt.want {
+			t.Errorf("max %d: got %d requests, want %d", tt.max, got, tt.want)
+		}
+	}
+
+	// SetMaxRedirects changes the limit of a client made without a Config
+	atomic.StoreInt32(&hits, 0)
+	client := NewClientWithTimeout(server.URL, 0)
+	client.SetMaxRedirects(1)
+	if _, err := client.Search(NewSearchRequest("test")); errors.GetErrorCode(err) != errors.ErrCodeTooManyRedirects {
+		t.Errorf("SetMaxRedirects(1): expected TOO_MANY_REDIRECTS, got %v", err)
+	}
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Errorf("SetMaxRedirects(1): got %d requests, want 2", got)
+	}
+}
@@ -0,0 +1,128 @@
+package searxng
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mule-ai/search/internal/errors"
+)
+
+// retryBackoff is the pause before the first retry; it doubles for each
+// later one. Pauses are taken from the same budget as the attempts.
+const retryBackoff = 100 * time.Millisecond
+
+// RetryNotify is called before each retry with the number of the attempt
+// that failed, starting at 1, its error and the timeout budget left.
+type RetryNotify func(attempt int, err error, remaining time.Duration)
+
+// SetRetries makes the client retry a search up to n more times when the
+// instance is unreachable, times out or answers with a server error.
+// notify, if not nil, is called before each retry. n <= 0 disables
+// retries.
+//
+// The client timeout is a ceiling for all attempts together rather than
+// for each one: every attempt gets an equal share of the budget that is
+// left, so retries never make a search wait longer than --timeout.
+//
+// Example:
+//
+//	client.SetRetries(2, func(attempt int, err error, remaining time.Duration) {
+//	    fmt.Fprintf(os.Stderr, "attempt %d failed, %s left\n", attempt, remaining)
+//	})
+func (c *Client) SetRetries(n int, notify RetryNotify) {
+	c.retries = n
+	c.retryNotify = notify
+}
+
+// searchWithRetries runs search, retrying instance failures within the
+// timeout budget set by SetRetries.
+func (c *Client) searchWithRetries(ctx context.Context, req *SearchRequest) (*SearchResponse, error) {
+	if c.retries <= 0 {
+		return c.search(ctx, req)
+	}
+
+	// The whole budget, shared by every attempt and the pauses between them
+	parent := ctx
+	budget := c.client.Timeout
+	if budget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, budget)
+		defer cancel()
+	}
+
+	attempts := c.retries + 1
+	tried := 0
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+		if deadline, ok := ctx.Deadline(); ok {
+			share := time.Until(deadline) / time.Duration(attempts-attempt+1)
+			attemptCtx, cancel = context.WithTimeout(ctx, share)
+		}
+		resp, err := c.search(attemptCtx, req)
+		tried++
+		timedOut := attemptCtx.Err() == context.DeadlineExceeded
+		cancel()
+		if err == nil {
+			return resp, nil
+		}
+
+		// The caller gave up; that is not something to retry
+		if parent.Err() != nil {
+			return nil, err
+		}
+		// An attempt that ran out of its share timed out, it wasn't canceled
+		if timedOut {
+			err = errors.NetworkError(context.DeadlineExceeded)
+		}
+		lastErr = err
+		if !IsInstanceFailure(err) || attempt == attempts || ctx.Err() != nil {
+			break
+		}
+
+		remaining := budgetLeft(ctx)
+		if c.retryNotify != nil {
+			c.retryNotify(attempt, err, remaining)
+		}
+
+		pause := retryBackoff << (attempt - 1)
+		if _, ok := ctx.Deadline(); ok && pause >= remaining {
+			break
+		}
+		select {
+		case <-time.After(pause):
+		case <-ctx.Done():
+		}
+		if parent.Err() != nil {
+			return nil, errors.Canceled(parent.Err())
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	se, ok := errors.IsSearchError(lastErr)
+	if !ok || budget <= 0 {
+		return nil, lastErr
+	}
+	verbose := fmt.Sprintf("Gave up after %d of %d attempts with %s of the %s timeout budget left",
+		tried, attempts, budgetLeft(ctx), budget)
+	if se.Verbose != "" {
+		verbose = se.Verbose + "\n" + verbose
+	}
+	return nil, se.WithVerbose(verbose)
+}
+
+// budgetLeft returns the time until ctx's deadline, rounded for display,
+// or 0 when ctx has no deadline or it has passed.
+func budgetLeft(ctx context.Context) time.Duration {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0
+	}
+	if left := time.Until(deadline); left > 0 {
+		return left.Round(time.Millisecond)
+	}
+	return 0
+}
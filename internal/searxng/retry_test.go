@@ -0,0 +1,118 @@
+package searxng
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mule-ai/search/internal/errors"
+)
+
+func TestRetriesStayWithinTimeout(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		select {
+		case <-time.After(5 * time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	timeout := 600 * time.Millisecond
+	client := NewClientWithTimeout(server.URL, timeout)
+	var notified int32
+	client.SetRetries(2, func(attempt int, err error, remaining time.Duration) {
+		atomic.AddInt32(&notified, 1)
+		if remaining <= 0 || remaining >= timeout {
+			t.Errorf("attempt %d: remaining budget %s outside (0, %s)", attempt, remaining, timeout)
+		}
+	})
+
+	start := time.Now()
+	_, err := client.Search(NewSearchRequest("golang"))
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("expected a timeout error")
+	}
+	if code := errors.GetErrorCode(err); code != errors.ErrCodeNetworkTimeout {
+		t.Errorf("error code = %s, want %s", code, errors.ErrCodeNetworkTimeout)
+	}
+	if elapsed > timeout+200*time.Millisecond {
+		t.Errorf("retries took %s, more than the %s timeout", elapsed, timeout)
+	}
+	if atomic.LoadInt32(&hits) < 2 || notified < 1 {
+		t.Errorf("expected the search to be retried, got %d requests and %d notifications", hits, notified)
+	}
+	if se, ok := errors.IsSearchError(err); !ok || !strings.Contains(se.Verbose, "timeout budget left") {
+		t.Errorf("verbose details should report the budget left, got %+v", err)
+	}
+}
+
+func TestRetriesRecoverFromServerError(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"query":"golang","number_of_results":1,"results":[{"title":"Go","url":"https://go.dev","content":"","engine":"test","score":1}]}`)
+	}))
+	defer server.Close()
+
+	client := NewClientWithTimeout(server.URL, 5*time.Second)
+	var attempts []int
+	client.SetRetries(2, func(attempt int, err error, remaining time.Duration) {
+		attempts = append(attempts, attempt)
+	})
+
+	resp, err := client.Search(NewSearchRequest("golang"))
+	if err != nil {
+		t.Fatalf("expected the retry to succeed, got %v", err)
+	}
+	if len(resp.Results) != 1 {
+		t.Errorf("expected 1 result, got %d", len(resp.Results))
+	}
+	if atomic.LoadInt32(&hits) != 2 || len(attempts) != 1 || attempts[0] != 1 {
+		t.Errorf("expected one retry after attempt 1, got %d requests and notifications %v", hits, attempts)
+	}
+}
+
+func TestRetriesSkipClientErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		retries int
+		status  int
+		want    int32
+	}{
+		{"not found is not retried", 2, http.StatusNotFound, 1},
+		{"retries disabled", 0, http.StatusServiceUnavailable, 1},
+		{"server error is retried", 1, http.StatusServiceUnavailable, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hits int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&hits, 1)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			client := NewClientWithTimeout(server.URL, 5*time.Second)
+			client.SetRetries(tt.retries, nil)
+			if _, err := client.Search(NewSearchRequest("golang")); err == nil {
+				t.Fatal("expected an error")
+			}
+			if atomic.LoadInt32(&hits) != tt.want {
+				t.Errorf("got %d requests, want %d", hits, tt.want)
+			}
+		})
+	}
+}
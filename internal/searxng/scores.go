@@ -0,0 +1,59 @@
+package searxng
+
+// NormalizeScores rescales result scores in place to the range 0-1.
+//
+// Engines score on different scales, so raw scores can't be compared
+// across result sets. Normalization is min-max over the given results:
+//
+//	normalized = (score - min) / (max - min)
+//
+// so the best result scores 1 and the worst 0. When every result has the
+// same non-zero score, all are set to 1. When every score is zero, scores
+// are assigned by rank position instead: (n - i) / n for the result at
+// index i, so the first of n results scores 1 and the last 1/n.
+//
+// The score before normalization is kept in OriginalScore.
+//
+// Example:
+//
+//	searxng.NormalizeScores(resp.Results)
+//	// resp.Results[0].Score is now between 0 and 1
+func NormalizeScores(results []SearchResult) {
+	if len(results) == 0 {
+		return
+	}
+
+	lo, hi := results[0].Score, results[0].Score
+	for _, r := range results[1:] {
+		if r.Score < lo {
+			lo = r.Score
+		}
+		if r.Score > hi {
+			hi = r.Score
+		}
+	}
+
+	n := float64(len(results))
+	for i := range results {
+		original := results[i].Score
+		results[i].OriginalScore = &original
+
+		switch {
+		case hi > lo:
+			results[i].Score = (original - lo) / (hi - lo)
+		case hi != 0:
+			results[i].Score = 1
+		default:
+			results[i].Score = (n - float64(i)) / n
+		}
+	}
+}
+
+// ScoreNormalizer is a ResultProcessor that applies NormalizeScores.
+type ScoreNormalizer struct{}
+
+// Process implements ResultProcessor.
+func (ScoreNormalizer) Process(results []SearchResult) []SearchResult {
+	NormalizeScores(results)
+	return results
+}
@@ -0,0 +1,43 @@
+package searxng
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNormalizeScores(t *testing.T) {
+	tests := []struct {
+		name   string
+		scores []float64
+		want   []float64
+	}{
+		{"min-max rescale", []float64{10, 5, 0}, []float64{1, 0.5, 0}},
+		{"unsorted scores", []float64{2, 8, 4}, []float64{0, 1, 1.0 / 3}},
+		{"equal non-zero scores", []float64{3, 3}, []float64{1, 1}},
+		{"all zero uses rank", []float64{0, 0, 0, 0}, []float64{1, 0.75, 0.5, 0.25}},
+		{"single result", []float64{0.4}, []float64{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := make([]SearchResult, len(tt.scores))
+			for i, s := range tt.scores {
+				results[i].Score = s
+			}
+
+			NormalizeScores(results)
+
+			for i, r := range results {
+				if math.Abs(r.Score-tt.want[i]) > 1e-9 {
+					t.Errorf("result %d score = %v, want %v", i, r.Score, tt.want[i])
+				}
+				if r.OriginalScore == nil || *r.OriginalScore != tt.scores[i] {
+					t.Errorf("result %d original score = %v, want %v", i, r.OriginalScore, tt.scores[i])
+				}
+			}
+		})
+	}
+
+	// Empty input should not panic
+	NormalizeScores(nil)
+}
@@ -0,0 +1,125 @@
+package searxng
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timings breaks down where the time of one request to the instance went.
+// Phases that didn't happen, such as DNS and connect on a reused
+// connection or TLS over plain HTTP, are zero.
+//
+// FirstByte is measured from the start of the request, so on a new
+// connection it includes DNS, connect and TLS. It ends when the first byte
+// of the response headers arrives, before the body is read.
+type Timings struct {
+	DNS       time.Duration
+	Connect   time.Duration
+	TLS       time.Duration
+	FirstByte time.Duration // from the start of the request to the first byte of the response headers
+	Reused    bool          // the request went over a kept-alive connection
+}
+
+// String formats the timings for a diagnostic line, e.g.
+// "dns=12ms connect=30ms tls=85ms ttfb=240ms".
+func (t Timings) String() string {
+	s := fmt.Sprintf("dns=%s connect=%s tls=%s ttfb=%s",
+		t.DNS.Round(time.Microsecond), t.Connect.Round(time.Microsecond),
+		t.TLS.Round(time.Microsecond), t.FirstByte.Round(time.Microsecond))
+	if t.Reused {
+		s += " (reused connection)"
+	}
+	return s
+}
+
+// TraceNotify is called after each request to the instance with its
+// timings, including requests that fail.
+type TraceNotify func(timings Timings)
+
+// SetTrace makes the client time each request with an httptrace.ClientTrace
+// and pass the timings to notify. Pass nil to disable, which skips tracing
+// entirely.
+//
+// Example:
+//
+//	client.SetTrace(func(t searxng.Timings) {
+//	    fmt.Fprintln(os.Stderr, "Trace:", t)
+//	})
+func (c *Client) SetTrace(notify TraceNotify) {
+	c.traceNotify = notify
+}
+
+// tracer collects Timings from the hooks of an httptrace.ClientTrace,
+// which may run on other goroutines than the request's.
+type tracer struct {
+	mu           sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	timings      Timings
+}
+
+// withTrace returns ctx with a ClientTrace recording into a new tracer,
+// timing from now.
+func withTrace(ctx context.Context) (context.Context, *tracer) {
+	t := &tracer{start: time.Now()}
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mu.Lock()
+			t.dnsStart = time.Now()
+			t.mu.Unlock()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mu.Lock()
+			t.timings.DNS = time.Since(t.dnsStart)
+			t.mu.Unlock()
+		},
+		ConnectStart: func(string, string) {
+			t.mu.Lock()
+			// Dialing several addresses at once counts from the first
+			if t.connectStart.IsZero() {
+				t.connectStart = time.Now()
+			}
+			t.mu.Unlock()
+		},
+		ConnectDone: func(_, _ string, err error) {
+			t.mu.Lock()
+			if err == nil {
+				t.timings.Connect = time.Since(t.connectStart)
+			}
+			t.mu.Unlock()
+		},
+		TLSHandshakeStart: func() {
+			t.mu.Lock()
+			t.tlsStart = time.Now()
+			t.mu.Unlock()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.mu.Lock()
+			t.timings.TLS = time.Since(t.tlsStart)
+			t.mu.Unlock()
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			t.timings.Reused = info.Reused
+			t.mu.Unlock()
+		},
+		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			t.timings.FirstByte = time.Since(t.start)
+			t.mu.Unlock()
+		},
+	}), t
+}
+
+// Timings returns what has been recorded so far.
+func (t *tracer) Timings() Timings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.timings
+}
@@ -0,0 +1,66 @@
+package searxng
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientTrace(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"query":"golang","results":[]}`)
+	}))
+	defer server.Close()
+
+	client := NewClientWithTimeout(server.URL, 5*time.Second)
+	client.client = server.Client()
+	var traced []Timings
+	client.SetTrace(func(timings Timings) {
+		traced = append(traced, timings)
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Search(NewSearchRequest("golang")); err != nil {
+			t.Fatalf("search %d failed: %v", i, err)
+		}
+	}
+	if len(traced) != 2 {
+		t.Fatalf("expected timings for 2 requests, got %d", len(traced))
+	}
+
+	first := traced[0]
+	if first.Reused || first.Connect <= 0 || first.TLS <= 0 || first.FirstByte <= 0 {
+		t.Errorf("expected connect, TLS and first byte timings for a new connection, got %+v", first)
+	}
+	if first.FirstByte < first.DNS+first.Connect+first.TLS {
+		t.Errorf("first byte timing should include connection setup, got %+v", first)
+	}
+	second := traced[1]
+	if !second.Reused || second.Connect != 0 || second.TLS != 0 || second.FirstByte <= 0 {
+		t.Errorf("expected only a first byte timing on a reused connection, got %+v", second)
+	}
+
+	// Without a trace set, searching works as before
+	client.SetTrace(nil)
+	if _, err := client.Search(NewSearchRequest("golang")); err != nil {
+		t.Fatalf("untraced search failed: %v", err)
+	}
+	if len(traced) != 2 {
+		t.Errorf("expected no timings once tracing is off, got %d", len(traced))
+	}
+}
+
+func TestTimingsString(t *testing.T) {
+	timings := Timings{DNS: 2 * time.Millisecond, Connect: 3 * time.Millisecond, TLS: 5 * time.Millisecond, FirstByte: 40 * time.Millisecond}
+	if got, want := timings.String(), "dns=2ms connect=3ms tls=5ms ttfb=40ms"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	timings = Timings{FirstByte: time.Millisecond, Reused: true}
+	if got := timings.String(); !strings.HasSuffix(got, "(reused connection)") {
+		t.Errorf("String() = %q, want a reused connection note", got)
+	}
+}
@@ -5,7 +5,11 @@ package searxng
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
+
+	"github.com/mule-ai/search/internal/errors"
 )
 
 // SearchResult represents a single search result from SearXNG.
@@ -34,16 +38,33 @@ type SearchResult struct {
 	ParsedURL   []string `json:"parsed_url,omitempty"`
 	Template    string   `json:"template,omitempty"`
 	PublishedDate *time.Time `json:"-"`
+	// OriginalScore holds the engine score when Score has been normalized
+	OriginalScore *float64 `json:"original_score,omitempty"`
+	// Raw holds every field of the result as the instance sent it
+	Raw map[string]interface{} `json:"-"`
+	// Engines lists every engine that returned the result; Engine is
+	// only the first of them
+	Engines []string `json:"-"`
+	// DetectedLanguage is the language guessed from the title and content
+	// by DetectLanguages, empty until it runs or when unsure
+	DetectedLanguage string `json:"-"`
+
+	// host caches Host() for the URL it was computed from
+	host    string
+	hostURL string
 }
 
 // UnmarshalJSON implements custom JSON unmarshaling for SearchResult.
 //
 // This handles edge cases like missing fields and provides default values.
+// The complete object is also kept in Raw.
 func (sr *SearchResult) UnmarshalJSON(data []byte) error {
 	// Use type alias to avoid recursion
 	type Alias SearchResult
 	aux := &struct {
-		Score interface{} `json:"score"`
+		Score         interface{} `json:"score"`
+		PublishedDate interface{} `json:"publishedDate"`
+		Engines       []string    `json:"engines"`
 		*Alias
 	}{
 		Alias: (*Alias)(sr),
@@ -61,11 +82,29 @@ func (sr *SearchResult) UnmarshalJSON(data []byte) error {
 		sr.Score = 0.0
 	}
 
+	sr.Engines = aux.Engines
+
 	// Ensure slices are initialized
 	if sr.ParsedURL == nil {
 		sr.ParsedURL = []string{}
 	}
 
+	// publishedDate is null or missing for engines that don't report it
+	if s, ok := aux.PublishedDate.(string); ok {
+		if t, ok := parsePublishedDate(s); ok {
+			sr.PublishedDate = &t
+		}
+	}
+
+	sr.host, sr.hostURL = parseHost(sr.URL), sr.URL
+
+	// Keep fields we don't model, such as positions and engines
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	sr.Raw = raw
+
 	return nil
 }
 
@@ -189,7 +228,24 @@ type SearchResponse struct {
 	SearchTime float64 `json:"-"`
 	// Pagination info
 	Page     int    `json:"page,omitempty"`
+	// PagesFetched is how many instance pages were merged into the
+	// response; 0 or 1 for a single page
+	PagesFetched int `json:"-"`
 	Instance string `json:"-"` // Instance URL for display
+	// Instances lists every instance a merged response came from
+	Instances []string `json:"-"`
+	// OriginalQuery is the query the user typed, set when it was rewritten
+	// before searching, e.g. by search operators, append_query or
+	// auto-correct; Query is then the query actually searched
+	OriginalQuery string `json:"-"`
+	// ETag is the entity tag the instance sent, if any
+	ETag string `json:"-"`
+	// NotModified is set when the instance answered IfNoneMatch with
+	// 304 Not Modified; the response then carries no results
+	NotModified bool `json:"-"`
+	// Partial is set when the body was cut off and only the results read
+	// before that are kept (see SetAllowPartial)
+	Partial bool `json:"-"`
 }
 
 // UnmarshalJSON implements custom JSON unmarshaling for SearchResponse.
@@ -248,6 +304,25 @@ func (sr *SearchResponse) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// EffectiveTotal returns the number of results to report for resp: the
+// instance's NumberOfResults when it gave one, or else the number of
+// results returned. Many instances send 0 or omit the field when their
+// engines don't estimate a total. Formatters all use it so they agree.
+//
+// Example:
+//
+//	resp := &searxng.SearchResponse{Results: make([]searxng.SearchResult, 3)}
+//	searxng.EffectiveTotal(resp) // 3
+func EffectiveTotal(resp *SearchResponse) int {
+	if resp == nil {
+		return 0
+	}
+	if resp.NumberOfResults > 0 {
+		return resp.NumberOfResults
+	}
+	return len(resp.Results)
+}
+
 // SearchRequest represents a search request to the SearXNG API.
 //
 // It contains all parameters that can be sent to the /search endpoint,
@@ -275,6 +350,9 @@ type SearchRequest struct {
 	Engines     []string
 	TimeRange   string
 	Timeout     time.Duration
+	// IfNoneMatch is an ETag from an earlier response. When it still
+	// matches, the instance answers 304 and the response has NotModified set.
+	IfNoneMatch string
 }
 
 // NewSearchRequest creates a new search request with default values.
@@ -299,3 +377,26 @@ func NewSearchRequest(query string) *SearchRequest {
 		Timeout:    30 * time.Second,
 	}
 }
+
+// Validate checks that the request can be sent to a SearXNG instance.
+//
+// It returns a *errors.SearchError if the query is empty, the page is
+// below 1, the safe search level is outside 0-2 or the format is empty.
+// Client.Search calls Validate before building the request URL.
+func (r *SearchRequest) Validate() error {
+	if strings.TrimSpace(r.Query) == "" {
+		return errors.EmptyQuery()
+	}
+	if r.Page < 1 {
+		return errors.New(errors.ErrCodeInvalidRange, fmt.Sprintf("Invalid page: %d (must be at least 1)", r.Page)).
+			WithSuggestion("Pages are numbered from 1")
+	}
+	if r.SafeSearch < 0 || r.SafeSearch > 2 {
+		return errors.InvalidRange("safe search level", 0, 2, r.SafeSearch)
+	}
+	if r.Format == "" {
+		return errors.New(errors.ErrCodeInvalidFormat, "Response format cannot be empty").
+			WithSuggestion("Set Format to \"json\"; NewSearchRequest does this by default")
+	}
+	return nil
+}
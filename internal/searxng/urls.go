@@ -0,0 +1,129 @@
+package searxng
+
+import (
+	"net/url"
+	"strings"
+)
+
+// trackingParams are query parameters that only identify the click
+// source. Names are matched case-insensitively.
+var trackingParams = map[string]bool{
+	"fbclid":      true,
+	"gclid":       true,
+	"dclid":       true,
+	"gbraid":      true,
+	"wbraid":      true,
+	"msclkid":     true,
+	"yclid":       true,
+	"twclid":      true,
+	"igshid":      true,
+	"mc_cid":      true,
+	"mc_eid":      true,
+	"_ga":         true,
+	"_gl":         true,
+	"_hsenc":      true,
+	"_hsmi":       true,
+	"mkt_tok":     true,
+	"oly_anon_id": true,
+	"oly_enc_id":  true,
+	"vero_id":     true,
+}
+
+// isTrackingParam reports whether the query parameter name is a known
+// tracking parameter. Any utm_ parameter counts.
+func isTrackingParam(name string) bool {
+	name = strings.ToLower(name)
+	return strings.HasPrefix(name, "utm_") || trackingParams[name]
+}
+
+// CleanURL removes known tracking parameters such as utm_source, fbclid
+// and gclid from u.
+//
+// Other parameters keep their order and encoding, and the fragment is
+// preserved. A URL that can't be parsed or has nothing to strip is
+// returned unchanged.
+//
+// Example:
+//
+//	searxng.CleanURL("https://example.com/a?id=7&utm_source=feed#top")
+//	// "https://example.com/a?id=7#top"
+func CleanURL(u string) string {
+	parsed, err := url.Parse(u)
+	if err != nil || parsed.RawQuery == "" {
+		return u
+	}
+
+	pairs := strings.Split(parsed.RawQuery, "&")
+	kept := pairs[:0]
+	for _, pair := range pairs {
+		name := pair
+		if i := strings.IndexByte(pair, '='); i >= 0 {
+			name = pair[:i]
+		}
+		if decoded, err := url.QueryUnescape(name); err == nil {
+			name = decoded
+		}
+		if !isTrackingParam(name) {
+			kept = append(kept, pair)
+		}
+	}
+
+	if len(kept) == len(pairs) {
+		return u
+	}
+
+	parsed.RawQuery = strings.Join(kept, "&")
+	// Drop a dangling "?" when every parameter was removed
+	parsed.ForceQuery = false
+	return parsed.String()
+}
+
+// Host returns the lowercased hostname of the result URL, without port,
+// or "" if the URL can't be parsed. A URL without a scheme, such as
+// "example.com/page", is read as a host and path.
+//
+// The host is computed once when the result is decoded; results built
+// in code, or whose URL has since changed, parse it on each call.
+//
+// Example:
+//
+//	r := searxng.SearchResult{URL: "https://Go.dev:443/doc"}
+//	r.Host() // "go.dev"
+func (r SearchResult) Host() string {
+	if r.hostURL == r.URL && r.URL != "" {
+		return r.host
+	}
+	return parseHost(r.URL)
+}
+
+// parseHost extracts the lowercased hostname from u.
+func parseHost(u string) string {
+	u = strings.TrimSpace(u)
+	if u == "" {
+		return ""
+	}
+	if !strings.Contains(u, "://") && !strings.HasPrefix(u, "//") {
+		u = "//" + u
+	}
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+// StripTracking applies CleanURL to the URL of each result in place.
+func StripTracking(results []SearchResult) {
+	for i := range results {
+		results[i].URL = CleanURL(results[i].URL)
+	}
+}
+
+// TrackingStripper is a ResultProcessor that applies StripTracking.
+type TrackingStripper struct{}
+
+// Process implements ResultProcessor.
+func (TrackingStripper) Process(results []SearchResult) []SearchResult {
+	StripTracking(results)
+	return results
+}
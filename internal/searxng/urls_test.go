@@ -0,0 +1,90 @@
+package searxng
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCleanURL(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"no query", "https://example.com/page", "https://example.com/page"},
+		{"utm params", "https://example.com/a?utm_source=feed&utm_medium=rss", "https://example.com/a"},
+		{"keeps meaningful params in order", "https://example.com/a?q=go&utm_campaign=x&page=2", "https://example.com/a?q=go&page=2"},
+		{"click ids", "https://example.com/?fbclid=abc&gclid=def&msclkid=ghi", "https://example.com/"},
+		{"keeps fragment", "https://example.com/doc?id=7&utm_source=feed#section-2", "https://example.com/doc?id=7#section-2"},
+		{"case insensitive", "https://example.com/?UTM_Source=x&FBCLID=y&id=1", "https://example.com/?id=1"},
+		{"keeps encoding", "https://example.com/s?q=a%20b%2Bc&utm_term=x", "https://example.com/s?q=a%20b%2Bc"},
+		{"param without value", "https://example.com/?debug&gclid", "https://example.com/?debug"},
+		{"nothing to strip is unchanged", "https://example.com/a%2Fb?x=1&y=2", "https://example.com/a%2Fb?x=1&y=2"},
+		{"similar names kept", "https://example.com/?utm=1&clid=2", "https://example.com/?utm=1&clid=2"},
+		{"unparseable", "://not a url", "://not a url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CleanURL(tt.in); got != tt.want {
+				t.Errorf("CleanURL(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripTracking(t *testing.T) {
+	results := []SearchResult{
+		{URL: "https://example.com/?utm_source=x"},
+		{URL: "https://example.com/?id=1"},
+	}
+	StripTracking(results)
+	if results[0].URL != "https://example.com/" || results[1].URL != "https://example.com/?id=1" {
+		t.Errorf("unexpected URLs after StripTracking: %q, %q", results[0].URL, results[1].URL)
+	}
+}
+
+func TestSearchResultHost(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{"https", "https://go.dev/doc/", "go.dev"},
+		{"uppercase and port", "HTTP://Example.COM:8080/path", "example.com"},
+		{"subdomain", "https://pkg.go.dev/net/url", "pkg.go.dev"},
+		{"no scheme", "example.com/page?q=1", "example.com"},
+		{"protocol relative", "//cdn.example.com/lib.js", "cdn.example.com"},
+		{"ipv6", "http://[::1]:8080/", "::1"},
+		{"empty", "", ""},
+		{"malformed", "http://exa mple.com/%zz", ""},
+		{"bad port", "https://example.com:port/", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (SearchResult{URL: tt.url}).Host(); got != tt.want {
+				t.Errorf("Host() for %q = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchResultHostCached(t *testing.T) {
+	var r SearchResult
+	if err := json.Unmarshal([]byte(`{"title":"Go","url":"https://Go.dev/doc"}`), &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.hostURL != r.URL || r.host != "go.dev" {
+		t.Errorf("expected host cached on decode, got %q for %q", r.host, r.hostURL)
+	}
+	if got := r.Host(); got != "go.dev" {
+		t.Errorf("Host() = %q, want go.dev", got)
+	}
+
+	// A changed URL is parsed again rather than served from the cache
+	r.URL = "https://example.org/"
+	if got := r.Host(); got != "example.org" {
+		t.Errorf("Host() after URL change = %q, want example.org", got)
+	}
+}
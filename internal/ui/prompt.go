@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// IsInteractive reports whether f is connected to a terminal rather than
+// a pipe or file.
+func IsInteractive(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Confirm writes prompt followed by " [y/N] " to out and reads one line
+// from in. Only "y" or "yes" (any case) count as confirmation.
+//
+// Example:
+//
+//	ok, err := ui.Confirm(os.Stdin, os.Stderr, "Open 25 results?")
+func Confirm(in io.Reader, out io.Writer, prompt string) (bool, error) {
+	if _, err := fmt.Fprintf(out, "%s [y/N] ", prompt); err != nil {
+		return false, err
+	}
+
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// ParseSelection parses a choice of items numbered 1 to n, such as "2",
+// "1,3", "1 3" or "2-4", returning the chosen numbers in the order given
+// without repeats.
+//
+// Example:
+//
+//	picks, err := ui.ParseSelection("1,3-4", 10)
+//	// picks == []int{1, 3, 4}
+func ParseSelection(input string, n int) ([]int, error) {
+	fields := strings.FieldsFunc(input, func(r rune) bool { return r == ',' || unicode.IsSpace(r) })
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no results chosen")
+	}
+
+	var picks []int
+	seen := make(map[int]bool)
+	for _, field := range fields {
+		first, last, isRange := strings.Cut(field, "-")
+		from, err := strconv.Atoi(first)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a result number", field)
+		}
+		to := from
+		if isRange {
+			if to, err = strconv.Atoi(last); err != nil || to < from {
+				return nil, fmt.Errorf("%q is not a range of result numbers", field)
+			}
+		}
+		if from < 1 || to > n {
+			return nil, fmt.Errorf("%s is out of range: choose from 1 to %d", field, n)
+		}
+		for i := from; i <= to; i++ {
+			if !seen[i] {
+				seen[i] = true
+				picks = append(picks, i)
+			}
+		}
+	}
+	return picks, nil
+}
+
+// Select writes prompt to out and reads a choice of items numbered 1 to n
+// from in, as accepted by ParseSelection. An invalid choice is reported
+// and asked for again; an empty line or end of input returns nil, for
+// no choice.
+//
+// Example:
+//
+//	picks, err := ui.Select(os.Stdin, os.Stderr, "Open which results?", 10)
+func Select(in io.Reader, out io.Writer, prompt string, n int) ([]int, error) {
+	reader := bufio.NewReader(in)
+	for {
+		if _, err := fmt.Fprintf(out, "%s [1-%d, e.g. 1,3 or 2-4; empty to cancel] ", prompt, n); err != nil {
+			return nil, err
+		}
+
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if strings.TrimSpace(line) == "" {
+			return nil, nil
+		}
+		picks, parseErr := ParseSelection(line, n)
+		if parseErr == nil {
+			return picks, nil
+		}
+		fmt.Fprintf(out, "Invalid choice: %v\n", parseErr)
+		if err == io.EOF {
+			return nil, nil
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"y\n", true},
+		{"YES\n", true},
+		{" yes \n", true},
+		{"n\n", false},
+		{"\n", false},
+		{"", false},
+		{"maybe\n", false},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		got, err := Confirm(strings.NewReader(tt.input), &out, "Continue?")
+		if err != nil {
+			t.Fatalf("Confirm(%q) returned error: %v", tt.input, err)
+		}
+		if got != tt.want {
+			t.Errorf("Confirm(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+		if out.String() != "Continue? [y/N] " {
+			t.Errorf("Expected prompt %q, got %q", "Continue? [y/N] ", out.String())
+		}
+	}
+}
+
+func TestParseSelection(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []int
+	}{
+		{"2", []int{2}},
+		{"1,3", []int{1, 3}},
+		{" 3 1 ", []int{3, 1}},
+		{"2-4", []int{2, 3, 4}},
+		{"1, 2-3, 2", []int{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		got, err := ParseSelection(tt.input, 5)
+		if err != nil {
+			t.Errorf("ParseSelection(%q) error = %v", tt.input, err)
+			continue
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("ParseSelection(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+
+	for _, input := range []string{"", "0", "6", "a", "4-2", "2-9", "1-x"} {
+		if _, err := ParseSelection(input, 5); err == nil {
+			t.Errorf("ParseSelection(%q) expected an error", input)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	var out bytes.Buffer
+	picks, err := Select(strings.NewReader("7\n2,3\n"), &out, "Open which results?", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(picks) != "[2 3]" {
+		t.Errorf("Select() = %v, want [2 3] after the invalid choice", picks)
+	}
+	if strings.Count(out.String(), "Open which results? [1-5") != 2 || !strings.Contains(out.String(), "Invalid choice") {
+		t.Errorf("expected the invalid choice reported and asked again, got %q", out.String())
+	}
+
+	for _, input := range []string{"\n", "", "9"} {
+		out.Reset()
+		picks, err := Select(strings.NewReader(input), &out, "Open which results?", 5)
+		if err != nil || picks != nil {
+			t.Errorf("Select(%q) = %v, %v; want no choice", input, picks, err)
+		}
+	}
+}
@@ -12,9 +12,19 @@ import (
 	"sync"
 	"time"
 
+	"golang.org/x/term"
+
 	"github.com/mule-ai/search/internal/config"
 )
 
+// Spinner styles for NewSearchSpinner, as set with --spinner or the
+// spinner config setting.
+const (
+	SpinnerDots = "dots" // braille dots; the default
+	SpinnerLine = "line" // ASCII -\|/ for terminals without Unicode fonts
+	SpinnerNone = "none" // no animation, just a "Searching..." line
+)
+
 // Spinner represents a loading indicator with animation.
 //
 // It provides visual feedback during long-running operations like network requests.
@@ -47,15 +57,22 @@ func NewSpinner(message string) *Spinner {
 		message:       message,
 		stopChan:      make(chan struct{}),
 		writer:        os.Stderr,
-		frames:        getSpinnerFrames(),
+		frames:        spinnerFrames(SpinnerDots),
 		frameInterval: 100 * time.Millisecond,
 		isTTY:         isTerminal(os.Stderr),
 	}
 }
 
+// Terminal control sequences used to draw and clean up the spinner.
+const (
+	clearLine  = "\r\033[K"
+	hideCursor = "\033[?25l"
+	showCursor = "\033[?25h"
+)
+
 // Start begins the spinner animation.
 //
-// If running in a TTY, it shows an animated spinner.
+// If running in a TTY, it hides the cursor and shows an animated spinner.
 // If not a TTY, it prints a simple message.
 //
 // Example:
@@ -73,10 +90,14 @@ func (s *Spinner) Start() {
 	}
 
 	s.active = true
-	s.wg.Add(1)
 
 	if s.isTTY {
-		go s.animate()
+		if s.stopChan == nil {
+			s.stopChan = make(chan struct{})
+		}
+		fmt.Fprint(s.writer, hideCursor)
+		s.wg.Add(1)
+		go s.animate(s.stopChan)
 	} else {
 		// Non-TTY: just print the message
 		fmt.Fprintf(s.writer, "%s...\n", s.message)
@@ -85,7 +106,7 @@ func (s *Spinner) Start() {
 
 // Stop stops the spinner animation.
 //
-// If running in a TTY, it clears the spinner line.
+// If running in a TTY, it clears the spinner line and shows the cursor again.
 // If not a TTY, it prints a completion message.
 //
 // Example:
@@ -96,32 +117,40 @@ func (s *Spinner) Start() {
 //	spinner.Stop("Done!")
 func (s *Spinner) Stop(finalMessage string) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	if !s.active {
+		s.mu.Unlock()
 		return
 	}
+	s.active = false
+	stopChan := s.stopChan
+	s.stopChan = make(chan struct{})
+	s.mu.Unlock()
 
-	// Handle edge case where stopChan might be nil
-	if s.stopChan != nil {
-		close(s.stopChan)
+	// Wait for the animation outside the lock, as it takes the lock to draw
+	if s.isTTY && stopChan != nil {
+		close(stopChan)
 		s.wg.Wait()
-		s.stopChan = make(chan struct{})
 	}
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	// Clear the spinner line
 	if s.isTTY {
-		fmt.Fprintf(s.writer, "\r\033[K") // Clear line
-		if finalMessage != "" {
-			fmt.Fprintf(s.writer, "%s\n", finalMessage)
-		}
-	} else {
-		if finalMessage != "" {
-			fmt.Fprintf(s.writer, "%s\n", finalMessage)
-		}
+		fmt.Fprint(s.writer, clearLine+showCursor)
 	}
+	if finalMessage != "" {
+		fmt.Fprintf(s.writer, "%s\n", finalMessage)
+	}
+}
 
-	s.active = false
+// Restore stops the spinner and returns the terminal to a clean state,
+// clearing the spinner line and showing the cursor.
+//
+// It is safe to call more than once and from a signal handler goroutine,
+// e.g. when a search is interrupted with Ctrl-C.
+func (s *Spinner) Restore() {
+	s.Stop("")
 }
 
 // Update changes the spinner message.
@@ -142,7 +171,7 @@ func (s *Spinner) Update(message string) {
 }
 
 // animate runs the spinner animation loop.
-func (s *Spinner) animate() {
+func (s *Spinner) animate(stopChan chan struct{}) {
 	defer s.wg.Done()
 
 	frame := 0
@@ -151,23 +180,24 @@ func (s *Spinner) animate() {
 
 	for {
 		select {
-		case <-s.stopChan:
+		case <-stopChan:
 			return
 		case <-ticker.C:
 			s.mu.Lock()
 			frameText := s.frames[frame%len(s.frames)]
-			fmt.Fprintf(s.writer, "\r\033[K%s %s", frameText, s.message)
+			fmt.Fprintf(s.writer, "%s%s %s", clearLine, frameText, s.message)
 			s.mu.Unlock()
 			frame++
 		}
 	}
 }
 
-// getSpinnerFrames returns the appropriate spinner frames.
-//
-// Uses simple ASCII characters that work on most terminals.
-func getSpinnerFrames() []string {
-	// Simple rotating spinner using common Unicode characters
+// spinnerFrames returns the animation frames for a spinner style,
+// defaulting to dots.
+func spinnerFrames(style string) []string {
+	if strings.EqualFold(style, SpinnerLine) {
+		return []string{"-", "\\", "|", "/"}
+	}
 	return []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
 }
 
@@ -185,10 +215,7 @@ func isTerminal(w io.Writer) bool {
 
 // isTerminalFile checks if a file descriptor is a terminal.
 func isTerminalFile(fd uintptr) bool {
-	// Simple check: in most environments, std{in,out,err} are 0,1,2
-	// For a more robust check, we'd use syscall isatty
-	// This is a simplified version that works for most cases
-	return fd >= 0 && fd <= 2
+	return term.IsTerminal(int(fd))
 }
 
 // ProgressReporter tracks progress of multi-step operations.
@@ -283,16 +310,31 @@ func ShouldShowSpinner(cfg *config.Config) bool {
 type SearchSpinner struct {
 	spinner *Spinner
 	enabled bool
+	static  bool // SpinnerNone: print one line instead of animating
 }
 
-// NewSearchSpinner creates a new search spinner.
-func NewSearchSpinner(enabled bool) *SearchSpinner {
+// NewSearchSpinner creates a new search spinner in the given style: dots
+// (or empty), line or none. The animated styles are only shown when
+// stderr is a terminal, so no escape codes end up in logs; none prints a
+// single "Searching..." line wherever stderr goes.
+//
+// Example:
+//
+//	spinner := ui.NewSearchSpinner(cfg.Verbose, cfg.Spinner)
+//	spinner.Start()
+//	defer spinner.Restore()
+func NewSearchSpinner(enabled bool, style string) *SearchSpinner {
+	if strings.EqualFold(style, SpinnerNone) {
+		return &SearchSpinner{enabled: enabled, static: true}
+	}
+
 	s := &SearchSpinner{
 		enabled: enabled && isTerminal(os.Stderr),
 	}
 
 	if s.enabled {
 		s.spinner = NewSpinner("Searching...")
+		s.spinner.frames = spinnerFrames(style)
 	}
 
 	return s
@@ -300,6 +342,10 @@ func NewSearchSpinner(enabled bool) *SearchSpinner {
 
 // Start begins the search spinner.
 func (s *SearchSpinner) Start() {
+	if s.enabled && s.static {
+		fmt.Fprintln(os.Stderr, "Searching...")
+		return
+	}
 	if s.enabled && s.spinner != nil {
 		s.spinner.Start()
 	}
@@ -327,6 +373,16 @@ func (s *SearchSpinner) Stop(resultCount int, duration string) {
 	}
 }
 
+// Restore stops the spinner without a message and restores the terminal.
+//
+// Call it when a search is interrupted so no partial spinner line or
+// hidden cursor is left behind.
+func (s *SearchSpinner) Restore() {
+	if s.enabled && s.spinner != nil {
+		s.spinner.Restore()
+	}
+}
+
 // StopWithError stops the spinner with an error message.
 func (s *SearchSpinner) StopWithError(err error) {
 	if s.enabled && s.spinner != nil {
@@ -2,8 +2,13 @@
 package ui
 
 import (
+	"bytes"
+	"io"
+	"os"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestSanitizeInput(t *testing.T) {
@@ -148,7 +153,7 @@ func TestProgressReporter(t *testing.T) {
 }
 
 func TestSearchSpinner(t *testing.T) {
-	spinner := NewSearchSpinner(true)
+	spinner := NewSearchSpinner(true, SpinnerDots)
 
 	if spinner == nil {
 		t.Fatal("Expected spinner to be created")
@@ -160,7 +165,7 @@ func TestSearchSpinner(t *testing.T) {
 }
 
 func TestSearchSpinnerDisabled(t *testing.T) {
-	spinner := NewSearchSpinner(false)
+	spinner := NewSearchSpinner(false, SpinnerDots)
 
 	if spinner == nil {
 		t.Fatal("Expected spinner to be created")
@@ -172,6 +177,64 @@ func TestSearchSpinnerDisabled(t *testing.T) {
 	spinner.StopWithError(nil)
 }
 
+// captureStderr runs fn with os.Stderr replaced by a pipe and returns
+// what fn wrote to it.
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldStderr := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = oldStderr }()
+
+	fn()
+	w.Close()
+	out, _ := io.ReadAll(r)
+	r.Close()
+	return string(out)
+}
+
+func TestSearchSpinnerNonTTY(t *testing.T) {
+	for _, style := range []string{"", SpinnerDots, SpinnerLine} {
+		got := captureStderr(t, func() {
+			spinner := NewSearchSpinner(true, style)
+			spinner.Start()
+			time.Sleep(250 * time.Millisecond)
+			spinner.Stop(3, "0.1s")
+			spinner.Restore()
+		})
+		if strings.Contains(got, "\033[") || strings.Contains(got, "\r") {
+			t.Errorf("style %q: expected no animation escape codes on a pipe, got %q", style, got)
+		}
+	}
+
+	got := captureStderr(t, func() {
+		spinner := NewSearchSpinner(true, SpinnerNone)
+		spinner.Start()
+		spinner.Stop(3, "0.1s")
+		spinner.Restore()
+	})
+	if got != "Searching...\n" {
+		t.Errorf("style none: expected a single Searching... line, got %q", got)
+	}
+
+	got = captureStderr(t, func() {
+		NewSearchSpinner(false, SpinnerNone).Start()
+	})
+	if got != "" {
+		t.Errorf("expected no output from a disabled spinner, got %q", got)
+	}
+}
+
+func TestSpinnerLineFrames(t *testing.T) {
+	frames := spinnerFrames(SpinnerLine)
+	if strings.Join(frames, "") != "-\\|/" {
+		t.Errorf("unexpected line frames %q", frames)
+	}
+}
+
 func TestOutputFunctions(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -192,7 +255,7 @@ func TestOutputFunctions(t *testing.T) {
 }
 
 func TestSpinnerFrames(t *testing.T) {
-	frames := getSpinnerFrames()
+	frames := spinnerFrames(SpinnerDots)
 
 	if len(frames) == 0 {
 		t.Error("Expected spinner frames to be non-empty")
@@ -323,6 +386,72 @@ func TestSpinnerNilStopChan(t *testing.T) {
 	spinner.Stop("Done")
 }
 
+// syncBuffer is a bytes.Buffer safe for the spinner goroutine to write to.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestSpinnerRestore(t *testing.T) {
+	out := &syncBuffer{}
+	spinner := NewSpinner("Searching...")
+	spinner.writer = out
+	spinner.isTTY = true
+	spinner.frameInterval = time.Millisecond
+
+	spinner.Start()
+	// Let the animation draw a few frames before interrupting it
+	time.Sleep(20 * time.Millisecond)
+	spinner.Restore()
+
+	got := out.String()
+	if !strings.HasPrefix(got, hideCursor) {
+		t.Errorf("Expected cursor to be hidden on start, got %q", got)
+	}
+	if !strings.Contains(got, "Searching...") {
+		t.Errorf("Expected spinner frames to be drawn, got %q", got)
+	}
+	if !strings.HasSuffix(got, clearLine+showCursor) {
+		t.Errorf("Expected restore sequence at end of output, got %q", got)
+	}
+	if spinner.active {
+		t.Error("Expected spinner to be inactive after Restore()")
+	}
+
+	// A second restore must not emit anything more
+	before := len(out.String())
+	spinner.Restore()
+	if len(out.String()) != before {
+		t.Error("Expected second Restore() to be a no-op")
+	}
+}
+
+func TestSpinnerRestoreNonTTY(t *testing.T) {
+	var out bytes.Buffer
+	spinner := NewSpinner("Searching")
+	spinner.writer = &out
+	spinner.isTTY = false
+
+	spinner.Start()
+	spinner.Restore()
+
+	if strings.Contains(out.String(), "\033[") {
+		t.Errorf("Expected no control sequences for non-TTY output, got %q", out.String())
+	}
+}
+
 func BenchmarkSanitizeInput(b *testing.B) {
 	input := "This is a test string with some characters and numbers 12345"
 	for i := 0; i < b.N; i++ {
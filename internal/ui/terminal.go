@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// UseColor reports whether to color output for a --color mode: always
+// and never are unconditional, while auto (or empty) colors only when
+// the output is a terminal and the NO_COLOR environment variable is unset.
+//
+// Example:
+//
+//	color := ui.UseColor("auto", ui.IsInteractive(os.Stdout))
+func UseColor(mode string, terminal bool) bool {
+	switch strings.ToLower(mode) {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	return terminal && os.Getenv("NO_COLOR") == ""
+}
+
+// TerminalWidth returns the width in columns of the terminal f is
+// connected to, or 0 when f is not a terminal or its size is unknown.
+//
+// Example:
+//
+//	width := ui.TerminalWidth(os.Stdout)
+//	if width == 0 {
+//	    width = 80
+//	}
+func TerminalWidth(f *os.File) int {
+	if !term.IsTerminal(int(f.Fd())) {
+		return 0
+	}
+	width, _, err := term.GetSize(int(f.Fd()))
+	if err != nil || width <= 0 {
+		return 0
+	}
+	return width
+}
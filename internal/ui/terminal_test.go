@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"os"
+	"testing"
+)
+
+func TestTerminalWidthNotTerminal(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	if got := TerminalWidth(w); got != 0 {
+		t.Errorf("TerminalWidth(pipe) = %d, want 0", got)
+	}
+}
+
+func TestUseColor(t *testing.T) {
+	tests := []struct {
+		mode     string
+		terminal bool
+		noColor  string
+		want     bool
+	}{
+		{"auto", true, "", true},
+		{"auto", false, "", false},
+		{"auto", true, "1", false},
+		{"", true, "", true},
+		{"always", true, "", true},
+		{"always", false, "", true},
+		{"ALWAYS", false, "1", true},
+		{"never", true, "", false},
+		{"never", false, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("NO_COLOR", tt.noColor)
+		if got := UseColor(tt.mode, tt.terminal); got != tt.want {
+			t.Errorf("UseColor(%q, terminal=%v) with NO_COLOR=%q = %v, want %v", tt.mode, tt.terminal, tt.noColor, got, tt.want)
+		}
+	}
+}
@@ -7,14 +7,19 @@ package validation
 import (
 	"fmt"
 	"net/url"
+	"regexp"
 	"strings"
+	"time"
+	"unicode"
 
+	"github.com/mule-ai/search/internal/config"
 	"github.com/mule-ai/search/internal/errors"
+	"github.com/mule-ai/search/internal/langdetect"
 	"github.com/mule-ai/search/internal/searxng"
 )
 
 // ValidFormats is the list of supported output formats.
-var ValidFormats = []string{"text", "json", "markdown"}
+var ValidFormats = []string{"text", "json", "ndjson", "jsonl-pretty", "json-lines-with-meta", "markdown", "table"}
 
 // ValidSafeSearchLevels is the list of valid safe search levels.
 var ValidSafeSearchLevels = []int{0, 1, 2}
@@ -38,6 +43,11 @@ func (e ValidationError) Error() string {
 	return msg
 }
 
+// ExitCode reports validation failures as invalid input.
+func (e ValidationError) ExitCode() int {
+	return errors.ExitInvalidInput
+}
+
 // ValidateQuery checks if the search query is valid.
 //
 // It ensures the query is not empty and does not exceed 1000 characters.
@@ -64,7 +74,8 @@ func ValidateQuery(query string) error {
 
 // ValidateResultCount checks if the result count is within valid range.
 //
-// The valid range is 1-100 results per page.
+// The valid range is 0-100 results, where 0 means everything on the
+// first page.
 //
 // Example:
 //
@@ -73,11 +84,11 @@ func ValidateQuery(query string) error {
 //	    log.Fatal(err)
 //	}
 func ValidateResultCount(count int) error {
-	if count < 1 {
-		return errors.InvalidRange("results", 1, 100, count)
+	if count < 0 {
+		return errors.InvalidRange("results", 0, 100, count)
 	}
 	if count > 100 {
-		return errors.InvalidRange("results", 1, 100, count)
+		return errors.InvalidRange("results", 0, 100, count)
 	}
 	return nil
 }
@@ -176,7 +187,7 @@ func ValidateInstanceURL(instanceURL string) error {
 
 // ValidateFormat checks if the format is supported.
 //
-// Valid formats are: text, json, markdown.
+// Valid formats are: text, json, ndjson, jsonl-pretty, json-lines-with-meta, markdown, table.
 //
 // Example:
 //
@@ -221,14 +232,19 @@ func ValidateSafeSearch(safeSearch int) error {
 	}
 }
 
+// languagePattern matches a bare ISO 639-1 code ("en") or one qualified
+// with an ISO 3166-1 region ("en-US").
+var languagePattern = regexp.MustCompile(`^[a-z]{2}(-[a-z]{2})?$`)
+
 // ValidateLanguage checks if the language code is valid.
 //
-// It performs a basic ISO 639-1 check (2-letter codes) or ISO 639-1 with
-// region code (5-letter codes like en_US).
+// It accepts a bare ISO 639-1 code (2 letters, e.g. en) or one qualified
+// with a region code (e.g. en-US, zh-CN), matched case-insensitively.
+// The value is only checked here; it is sent to SearXNG unchanged.
 //
 // Example:
 //
-//	err := validation.ValidateLanguage("en")
+//	err := validation.ValidateLanguage("en-US")
 //	if err != nil {
 //	    log.Fatal(err)
 //	}
@@ -241,11 +257,38 @@ func ValidateLanguage(language string) error {
 			Message: "language code cannot be empty",
 		}
 	}
-	if len(language) != 2 && len(language) != 5 {
+	if !languagePattern.MatchString(language) {
 		return ValidationError{
-			Field:   "language",
-			Value:   language,
-			Message: "language code must be 2-letter (ISO 639-1) or 5-letter (with region, e.g., en_US)",
+			Field:      "language",
+			Value:      language,
+			Message:    "language code must be 2-letter (ISO 639-1) or region-qualified (e.g., en-US)",
+			Suggestion: "Use a code like en, de, en-US or zh-CN",
+		}
+	}
+	return nil
+}
+
+// acceptLanguagePattern matches one element of an Accept-Language header
+// (RFC 9110): a language range such as "de", "en-US" or "*", optionally
+// weighted with a quality value such as ";q=0.8".
+var acceptLanguagePattern = regexp.MustCompile(`^(\*|[A-Za-z]{1,8}(-[A-Za-z0-9]{1,8})*)(\s*;\s*[qQ]=(0(\.[0-9]{0,3})?|1(\.0{0,3})?))?$`)
+
+// ValidateAcceptLanguage checks that value is a well-formed Accept-Language
+// header: a comma-separated list of language ranges with optional quality
+// values, such as "de-DE,de;q=0.9,en;q=0.5". Empty means the search
+// language is sent and is valid.
+func ValidateAcceptLanguage(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	for _, part := range strings.Split(value, ",") {
+		if !acceptLanguagePattern.MatchString(strings.TrimSpace(part)) {
+			return ValidationError{
+				Field:      "accept-language",
+				Value:      value,
+				Message:    fmt.Sprintf("invalid language range %q", strings.TrimSpace(part)),
+				Suggestion: "Use a list like de-DE,de;q=0.9,en;q=0.5",
+			}
 		}
 	}
 	return nil
@@ -309,6 +352,490 @@ func ValidateTimeRange(timeRange string) error {
 	}
 }
 
+// ValidateMaxResponseSize checks if the response size limit is valid.
+//
+// The limit must be at least 1 byte.
+//
+// Example:
+//
+//	err := validation.ValidateMaxResponseSize(5 << 20)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func ValidateMaxResponseSize(size int64) error {
+	if size < 1 {
+		return ValidationError{
+			Field:   "maxResponseSize",
+			Value:   size,
+			Message: "max response size must be at least 1 byte",
+		}
+	}
+	return nil
+}
+
+// ValidateContentMaxLines checks if the content line limit is valid.
+//
+// The limit must not be negative; 0 means unlimited.
+//
+// Example:
+//
+//	err := validation.ValidateContentMaxLines(3)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func ValidateContentMaxLines(lines int) error {
+	if lines < 0 {
+		return ValidationError{
+			Field:   "contentMaxLines",
+			Value:   lines,
+			Message: "content max lines cannot be negative (use 0 for unlimited)",
+		}
+	}
+	return nil
+}
+
+// MinWidth is the narrowest output width --width accepts.
+const MinWidth = 20
+
+// ValidateWidth checks an output width from --width. 0 means detect the
+// terminal width.
+//
+// Example:
+//
+//	err := validation.ValidateWidth(120)
+func ValidateWidth(width int) error {
+	if width != 0 && width < MinWidth {
+		return ValidationError{
+			Field:      "width",
+			Value:      width,
+			Message:    fmt.Sprintf("width must be at least %d columns (use 0 to detect the terminal width)", MinWidth),
+			Suggestion: "Use e.g. --width 100",
+		}
+	}
+	return nil
+}
+
+// ValidateCompactFormat checks that compact output was requested for a
+// format that supports it.
+//
+// Only json and ndjson can be compacted.
+//
+// Example:
+//
+//	err := validation.ValidateCompactFormat("json")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func ValidateCompactFormat(format string) error {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "json" || format == "ndjson" {
+		return nil
+	}
+	return ValidationError{
+		Field:      "compact",
+		Value:      format,
+		Message:    "compact output is only supported for json and ndjson formats",
+		Suggestion: "Use --format json or --format ndjson with --compact",
+	}
+}
+
+// ValidFieldSeparatorNames is the list of named separators for text output.
+var ValidFieldSeparatorNames = []string{"tab", "pipe", "comma", "semicolon", "space"}
+
+// ValidateFieldSeparator checks if a text output field separator is valid.
+//
+// The separator must be a single printable character or one of the
+// named tokens in ValidFieldSeparatorNames. Empty string is allowed
+// (normal text layout).
+//
+// Example:
+//
+//	err := validation.ValidateFieldSeparator("pipe")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func ValidateFieldSeparator(sep string) error {
+	if sep == "" {
+		return nil // Optional field
+	}
+
+	for _, name := range ValidFieldSeparatorNames {
+		if strings.ToLower(sep) == name {
+			return nil
+		}
+	}
+
+	runes := []rune(sep)
+	if len(runes) == 1 && unicode.IsPrint(runes[0]) {
+		return nil
+	}
+
+	return ValidationError{
+		Field:      "fieldSeparator",
+		Value:      sep,
+		Message:    "field separator must be a single printable character or a named separator",
+		Suggestion: fmt.Sprintf("Use one character like '|' or one of: %s", strings.Join(ValidFieldSeparatorNames, ", ")),
+	}
+}
+
+// ValidGroupByValues is the list of supported --group-by keys.
+var ValidGroupByValues = []string{"category"}
+
+// ValidateGroupBy checks if a result grouping key is valid.
+//
+// Empty string is allowed (no grouping).
+func ValidateGroupBy(groupBy string) error {
+	if groupBy == "" {
+		return nil // Optional field
+	}
+
+	for _, valid := range ValidGroupByValues {
+		if strings.ToLower(groupBy) == valid {
+			return nil
+		}
+	}
+
+	return ValidationError{
+		Field:      "groupBy",
+		Value:      groupBy,
+		Message:    "unsupported grouping",
+		Suggestion: fmt.Sprintf("Valid values are: %s", strings.Join(ValidGroupByValues, ", ")),
+	}
+}
+
+// ValidColorModes is the list of supported --color modes.
+var ValidColorModes = []string{"auto", "always", "never"}
+
+// ValidateColor checks if a --color mode is valid.
+//
+// Empty string is allowed (auto).
+func ValidateColor(mode string) error {
+	if mode == "" {
+		return nil // Optional field
+	}
+
+	for _, valid := range ValidColorModes {
+		if strings.ToLower(mode) == valid {
+			return nil
+		}
+	}
+
+	return ValidationError{
+		Field:      "color",
+		Value:      mode,
+		Message:    "unsupported color mode",
+		Suggestion: fmt.Sprintf("Valid values are: %s", strings.Join(ValidColorModes, ", ")),
+	}
+}
+
+// ValidSpinnerStyles is the list of supported --spinner styles.
+var ValidSpinnerStyles = []string{"dots", "line", "none"}
+
+// ValidateSpinner checks if a spinner style is valid.
+//
+// Empty string is allowed (dots).
+func ValidateSpinner(style string) error {
+	if style == "" {
+		return nil // Optional field
+	}
+
+	for _, valid := range ValidSpinnerStyles {
+		if strings.ToLower(style) == valid {
+			return nil
+		}
+	}
+
+	return ValidationError{
+		Field:      "spinner",
+		Value:      style,
+		Message:    "unsupported spinner style",
+		Suggestion: fmt.Sprintf("Valid values are: %s", strings.Join(ValidSpinnerStyles, ", ")),
+	}
+}
+
+// ValidAuthTypes is the list of supported auth_type values.
+var ValidAuthTypes = []string{config.AuthTypeBearer, config.AuthTypeHMAC}
+
+// ValidateAuth checks the auth_type setting and that hmac comes with a
+// secret to sign with.
+//
+// Empty type is allowed (bearer).
+func ValidateAuth(authType, secret string) error {
+	switch authType {
+	case "", config.AuthTypeBearer:
+		return nil
+	case config.AuthTypeHMAC:
+		if secret == "" {
+			return ValidationError{
+				Field:      "authSecret",
+				Value:      "",
+				Message:    "auth_type hmac needs a secret to sign requests with",
+				Suggestion: "Set auth_secret in the config file or SEARCH_AUTH_SECRET",
+			}
+		}
+		return nil
+	}
+	return ValidationError{
+		Field:      "authType",
+		Value:      authType,
+		Message:    "unsupported auth type",
+		Suggestion: fmt.Sprintf("Valid values are: %s", strings.Join(ValidAuthTypes, ", ")),
+	}
+}
+
+// ValidInstanceSelectModes is the list of supported --instance-select modes.
+var ValidInstanceSelectModes = []string{"roundrobin", "random"}
+
+// ValidateInstanceSelect checks if an instance selection mode is valid.
+//
+// Empty string is allowed (round-robin).
+func ValidateInstanceSelect(mode string) error {
+	if mode == "" {
+		return nil // Optional field
+	}
+
+	for _, valid := range ValidInstanceSelectModes {
+		if strings.ToLower(mode) == valid {
+			return nil
+		}
+	}
+
+	return ValidationError{
+		Field:      "instanceSelect",
+		Value:      mode,
+		Message:    "unsupported instance selection mode",
+		Suggestion: fmt.Sprintf("Valid values are: %s", strings.Join(ValidInstanceSelectModes, ", ")),
+	}
+}
+
+// ValidateDate checks that a --since or --until value is a date in
+// YYYY-MM-DD or RFC3339 form.
+//
+// Empty string is allowed (no date limit).
+func ValidateDate(field, value string) error {
+	if value == "" {
+		return nil // Optional field
+	}
+
+	if _, err := searxng.ParseFilterDate(value, false); err != nil {
+		return ValidationError{
+			Field:      field,
+			Value:      value,
+			Message:    "invalid date",
+			Suggestion: "Use YYYY-MM-DD (e.g. 2024-01-01) or RFC3339 (e.g. 2024-01-01T12:00:00Z)",
+		}
+	}
+	return nil
+}
+
+// ValidateDeadline checks that a --deadline value is an RFC3339 timestamp
+// later than now.
+//
+// Empty string is allowed (--timeout applies).
+func ValidateDeadline(value string, now time.Time) error {
+	if value == "" {
+		return nil
+	}
+
+	deadline, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return ValidationError{
+			Field:      "deadline",
+			Value:      value,
+			Message:    "invalid deadline",
+			Suggestion: "Use an RFC3339 timestamp with a time zone, e.g. 2024-01-01T12:00:00Z",
+		}
+	}
+	if !deadline.After(now) {
+		return ValidationError{
+			Field:      "deadline",
+			Value:      value,
+			Message:    fmt.Sprintf("deadline passed %s ago", now.Sub(deadline).Round(time.Second)),
+			Suggestion: "Pass a deadline in the future, or use --timeout",
+		}
+	}
+	return nil
+}
+
+// ValidateConcurrency checks that the number of parallel searches is at
+// least 1.
+func ValidateConcurrency(n int) error {
+	if n < 1 {
+		return ValidationError{
+			Field:      "concurrency",
+			Value:      n,
+			Message:    "concurrency must be at least 1",
+			Suggestion: "Use 1 to run searches one at a time",
+		}
+	}
+	return nil
+}
+
+// ValidateRetries checks the retry count is between 0 and
+// config.MaxRetries.
+func ValidateRetries(n int) error {
+	if n < 0 || n > config.MaxRetries {
+		return ValidationError{
+			Field:      "retries",
+			Value:      n,
+			Message:    fmt.Sprintf("retries must be between 0 and %d", config.MaxRetries),
+			Suggestion: "Use 1 or 2; all attempts share the --timeout budget",
+		}
+	}
+	return nil
+}
+
+// ValidateRateLimit checks the requests-per-second limit is not
+// negative; 0 turns the limit off.
+func ValidateRateLimit(perSecond float64) error {
+	if perSecond < 0 {
+		return ValidationError{
+			Field:      "rate-limit",
+			Value:      perSecond,
+			Message:    "rate limit cannot be negative",
+			Suggestion: "Use a value such as 1 or 0.5 requests per second, or 0 for no limit",
+		}
+	}
+	return nil
+}
+
+// ValidateMaxRedirects checks the redirect limit is not negative; 0
+// follows no redirects.
+func ValidateMaxRedirects(n int) error {
+	if n < 0 {
+		return ValidationError{
+			Field:      "max-redirects",
+			Value:      n,
+			Message:    "max redirects cannot be negative",
+			Suggestion: "Use 0 to follow no redirects",
+		}
+	}
+	return nil
+}
+
+// ValidateOnlyLanguage checks that --only-language names a language the
+// detector knows, such as "de" or "pt-BR".
+//
+// An empty value is allowed (no filter).
+func ValidateOnlyLanguage(lang string) error {
+	if lang == "" {
+		return nil
+	}
+	base := strings.ToLower(lang)
+	if i := strings.IndexAny(base, "-_"); i >= 0 {
+		base = base[:i]
+	}
+	if !langdetect.IsSupported(base) {
+		return ValidationError{
+			Field:      "onlyLanguage",
+			Value:      lang,
+			Message:    "language cannot be detected",
+			Suggestion: fmt.Sprintf("Use one of: %s", strings.Join(langdetect.Supported(), ", ")),
+		}
+	}
+	return nil
+}
+
+// ValidateResultsPerEngine checks the per-engine result cap.
+//
+// 0 is allowed (no cap).
+func ValidateResultsPerEngine(n int) error {
+	if n < 0 {
+		return ValidationError{
+			Field:      "resultsPerEngine",
+			Value:      n,
+			Message:    "results per engine cannot be negative",
+			Suggestion: "Use 0 to disable the cap",
+		}
+	}
+	return nil
+}
+
+// ValidateMinContentLength checks the --min-content-length filter.
+//
+// 0 is allowed (keep all results).
+func ValidateMinContentLength(n int) error {
+	if n < 0 {
+		return ValidationError{
+			Field:      "minContentLength",
+			Value:      n,
+			Message:    "minimum content length cannot be negative",
+			Suggestion: "Use 0 to keep results however short their content",
+		}
+	}
+	return nil
+}
+
+// ValidateMinResults checks the --min-results floor, which is bounded
+// like the result count.
+//
+// 0 is allowed (no floor).
+func ValidateMinResults(n int) error {
+	if n < 0 || n > 100 {
+		return errors.InvalidRange("minResults", 0, 100, n)
+	}
+	return nil
+}
+
+// ValidateOffset checks --offset, the number of results skipped before
+// the ones shown. It can't be combined with a --page past the first,
+// which sets the window in pages instead.
+//
+// 0 is allowed (no offset).
+func ValidateOffset(offset, page int) error {
+	if offset < 0 {
+		return ValidationError{
+			Field:      "offset",
+			Value:      fmt.Sprintf("%d", offset),
+			Message:    "offset cannot be negative",
+			Suggestion: "Use e.g. --offset 10 -n 10 for results 11-20",
+		}
+	}
+	if offset > 0 && page > 1 {
+		return ValidationError{
+			Field:      "offset",
+			Value:      fmt.Sprintf("%d", offset),
+			Message:    "--offset cannot be combined with --page",
+			Suggestion: "Drop --page; --offset counts results from the first",
+		}
+	}
+	return nil
+}
+
+// paramKeyPattern matches a query parameter name such as "theme" or
+// "enabled_plugins".
+var paramKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_.\-\[\]]+$`)
+
+// ValidateParam checks that a --param value has the form key=value with a
+// parameter name before the "=". The value may be empty or contain "=".
+func ValidateParam(param string) error {
+	key, _, ok := strings.Cut(param, "=")
+	if !ok || !paramKeyPattern.MatchString(key) {
+		return ValidationError{
+			Field:      "param",
+			Value:      param,
+			Message:    "parameter must be key=value",
+			Suggestion: "Use e.g. --param theme=simple or --param enabled_plugins=Hash_plugin",
+		}
+	}
+	return nil
+}
+
+// ValidateBookmark checks the --bookmark result number.
+//
+// 0 is allowed (nothing is bookmarked).
+func ValidateBookmark(n int) error {
+	if n < 0 {
+		return ValidationError{
+			Field:      "bookmark",
+			Value:      n,
+			Message:    "bookmark must be a result number",
+			Suggestion: "Use the number shown next to the result, e.g. --bookmark 1",
+		}
+	}
+	return nil
+}
+
 // ValidateCategory checks if the category is valid.
 //
 // It normalizes category aliases and checks against known SearXNG categories.
@@ -322,11 +849,9 @@ func ValidateCategory(category string) error {
 		}
 	}
 	
-	// Normalize the category (resolve aliases)
-	normalized := searxng.NormalizeCategory(category)
-	
-	// Check if it's a known category
-	if !searxng.IsValidCategory(normalized) {
+	// Check if it's a known category or alias, from the same table the
+	// categories command lists
+	if !searxng.IsValidCategory(category) {
 		// Don't error for unknown categories as SearXNG may support more
 		// Just warn the user via verbose output if they wanted to know
 		return nil
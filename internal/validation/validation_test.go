@@ -1,7 +1,12 @@
 package validation
 
 import (
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/mule-ai/search/internal/errors"
+	"github.com/mule-ai/search/internal/searxng"
 )
 
 func TestValidateQuery(t *testing.T) {
@@ -37,7 +42,7 @@ func TestValidateResultCount(t *testing.T) {
 		{"minimum valid", 1, false},
 		{"valid count", 10, false},
 		{"maximum valid", 100, false},
-		{"zero", 0, true},
+		{"zero means the first page", 0, false},
 		{"negative", -1, true},
 		{"exceeds maximum", 101, true},
 		{"large number", 1000, true},
@@ -116,6 +121,8 @@ func TestValidateFormat(t *testing.T) {
 	}{
 		{"text format", "text", false},
 		{"json format", "json", false},
+		{"ndjson format", "ndjson", false},
+		{"table format", "table", false},
 		{"markdown format", "markdown", false},
 		{"uppercase JSON", "JSON", false},
 		{"mixed case TEXT", "TeXt", false},
@@ -167,8 +174,13 @@ func TestValidateLanguage(t *testing.T) {
 	}{
 		{"valid 2-letter code", "en", false},
 		{"valid 2-letter code", "de", false},
-		{"valid 5-letter code", "en_US", false},
-		{"valid 5-letter code", "de_DE", false},
+		{"region-qualified code", "en-US", false},
+		{"region-qualified code", "de-DE", false},
+		{"region-qualified code", "zh-CN", false},
+		{"lowercase region", "pt-br", false},
+		{"underscore separator", "en_US", true},
+		{"missing region", "en-", true},
+		{"region too long", "en-USA", true},
 		{"uppercase", "EN", false},
 		{"with spaces", " en ", false},
 		{"empty string", "", true},
@@ -240,6 +252,335 @@ func TestValidateTimeRange(t *testing.T) {
 	}
 }
 
+func TestValidateMaxResponseSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int64
+		wantErr bool
+	}{
+		{"one byte", 1, false},
+		{"default", 5 << 20, false},
+		{"zero", 0, true},
+		{"negative", -1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMaxResponseSize(tt.size)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateMaxResponseSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateContentMaxLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		lines   int
+		wantErr bool
+	}{
+		{"unlimited", 0, false},
+		{"positive", 3, false},
+		{"negative", -1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateContentMaxLines(tt.lines)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateContentMaxLines() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateCompactFormat(t *testing.T) {
+	tests := []struct {
+		format  string
+		wantErr bool
+	}{
+		{"json", false},
+		{"NDJSON", false},
+		{"text", true},
+		{"markdown", true},
+		{"table", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			err := ValidateCompactFormat(tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCompactFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateFieldSeparator(t *testing.T) {
+	tests := []struct {
+		name    string
+		sep     string
+		wantErr bool
+	}{
+		{"empty - optional", "", false},
+		{"named tab", "tab", false},
+		{"named pipe uppercase", "PIPE", false},
+		{"single character", "|", false},
+		{"single unicode character", "•", false},
+		{"multiple characters", "||", true},
+		{"unknown name", "colon", true},
+		{"control character", "\x01", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateFieldSeparator(tt.sep)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateFieldSeparator() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateGroupBy(t *testing.T) {
+	tests := []struct {
+		name    string
+		groupBy string
+		wantErr bool
+	}{
+		{"empty - optional", "", false},
+		{"category", "category", false},
+		{"category uppercase", "CATEGORY", false},
+		{"unknown key", "engine", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGroupBy(tt.groupBy)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateGroupBy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateDate(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"empty - optional", "", false},
+		{"date", "2024-01-01", false},
+		{"RFC3339", "2024-01-01T12:00:00Z", false},
+		{"RFC3339 with offset", "2024-01-01T12:00:00+02:00", false},
+		{"slashes", "2024/01/01", true},
+		{"invalid month", "2024-13-01", true},
+		{"relative", "last week", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDate("since", tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateDate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateOnlyLanguage(t *testing.T) {
+	for _, lang := range []string{"", "de", "pt-BR", "EN", "ja"} {
+		if err := ValidateOnlyLanguage(lang); err != nil {
+			t.Errorf("ValidateOnlyLanguage(%q) error = %v", lang, err)
+		}
+	}
+	for _, lang := range []string{"xx", "english"} {
+		if err := ValidateOnlyLanguage(lang); err == nil {
+			t.Errorf("ValidateOnlyLanguage(%q) expected error", lang)
+		}
+	}
+}
+
+func TestValidateColor(t *testing.T) {
+	for _, mode := range []string{"", "auto", "always", "never", "Always"} {
+		if err := ValidateColor(mode); err != nil {
+			t.Errorf("ValidateColor(%q) error = %v", mode, err)
+		}
+	}
+	if err := ValidateColor("sometimes"); err == nil {
+		t.Error("ValidateColor(\"sometimes\") expected error")
+	}
+}
+
+func TestValidateSpinner(t *testing.T) {
+	for _, style := range []string{"", "dots", "line", "none", "Line"} {
+		if err := ValidateSpinner(style); err != nil {
+			t.Errorf("ValidateSpinner(%q) error = %v", style, err)
+		}
+	}
+	if err := ValidateSpinner("bounce"); err == nil {
+		t.Error("ValidateSpinner(\"bounce\") expected error")
+	}
+}
+
+func TestValidateAuth(t *testing.T) {
+	tests := []struct {
+		authType, secret string
+		wantErr          bool
+	}{
+		{"", "", false},
+		{"bearer", "", false},
+		{"hmac", "s3cret", false},
+		{"hmac", "", true},
+		{"digest", "s3cret", true},
+	}
+	for _, tt := range tests {
+		if err := ValidateAuth(tt.authType, tt.secret); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateAuth(%q, %q) error = %v, wantErr %v", tt.authType, tt.secret, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateInstanceSelect(t *testing.T) {
+	for _, mode := range []string{"", "roundrobin", "random", "Random"} {
+		if err := ValidateInstanceSelect(mode); err != nil {
+			t.Errorf("ValidateInstanceSelect(%q) error = %v", mode, err)
+		}
+	}
+	if err := ValidateInstanceSelect("weighted"); err == nil {
+		t.Error("ValidateInstanceSelect(\"weighted\") expected error")
+	}
+}
+
+func TestValidateRetries(t *testing.T) {
+	for _, n := range []int{0, 2, 10} {
+		if err := ValidateRetries(n); err != nil {
+			t.Errorf("ValidateRetries(%d) error = %v", n, err)
+		}
+	}
+	for _, n := range []int{-1, 11} {
+		if err := ValidateRetries(n); err == nil {
+			t.Errorf("ValidateRetries(%d) expected error", n)
+		}
+	}
+}
+
+func TestValidateAcceptLanguage(t *testing.T) {
+	for _, v := range []string{"", "de", "en-US", "*", "de-DE,de;q=0.9,en;q=0.5", "en-GB, en ; q=1.0", "zh-Hant-TW"} {
+		if err := ValidateAcceptLanguage(v); err != nil {
+			t.Errorf("ValidateAcceptLanguage(%q) error = %v", v, err)
+		}
+	}
+	for _, v := range []string{"en_US", "de;q=2", "en,,de", "english language", "de;q=0.1234", "de\r\nX-Test: 1"} {
+		if err := ValidateAcceptLanguage(v); err == nil {
+			t.Errorf("ValidateAcceptLanguage(%q) expected error", v)
+		}
+	}
+}
+
+func TestValidateMaxRedirects(t *testing.T) {
+	for _, n := range []int{0, 3, 10} {
+		if err := ValidateMaxRedirects(n); err != nil {
+			t.Errorf("ValidateMaxRedirects(%d) error = %v", n, err)
+		}
+	}
+	if err := ValidateMaxRedirects(-1); err == nil {
+		t.Error("ValidateMaxRedirects(-1) expected error")
+	}
+}
+
+func TestValidateRateLimit(t *testing.T) {
+	for _, n := range []float64{0, 0.5, 1, 20} {
+		if err := ValidateRateLimit(n); err != nil {
+			t.Errorf("ValidateRateLimit(%v) error = %v", n, err)
+		}
+	}
+	if err := ValidateRateLimit(-1); err == nil {
+		t.Error("ValidateRateLimit(-1) expected error")
+	}
+}
+
+func TestValidateDeadline(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	for _, v := range []string{"", "2024-01-01T12:00:30Z", "2024-01-01T13:00:01+01:00"} {
+		if err := ValidateDeadline(v, now); err != nil {
+			t.Errorf("ValidateDeadline(%q) error = %v", v, err)
+		}
+	}
+	for _, v := range []string{"2024-01-01", "12:00:30", "2024-01-01T12:00:30", "2024-01-01T11:59:59Z", "2024-01-01T12:00:00Z"} {
+		if err := ValidateDeadline(v, now); err == nil {
+			t.Errorf("ValidateDeadline(%q) expected error", v)
+		}
+	}
+	err := ValidateDeadline("2024-01-01T11:59:00Z", now)
+	if err == nil || !strings.Contains(err.Error(), "deadline passed 1m0s ago") {
+		t.Errorf("expected a clear message for a past deadline, got %v", err)
+	}
+}
+
+func TestValidateConcurrency(t *testing.T) {
+	for _, n := range []int{1, 4, 32} {
+		if err := ValidateConcurrency(n); err != nil {
+			t.Errorf("ValidateConcurrency(%d) error = %v", n, err)
+		}
+	}
+	for _, n := range []int{0, -1} {
+		if err := ValidateConcurrency(n); err == nil {
+			t.Errorf("ValidateConcurrency(%d) expected error", n)
+		}
+	}
+}
+
+func TestValidateResultsPerEngine(t *testing.T) {
+	for _, n := range []int{0, 1, 5} {
+		if err := ValidateResultsPerEngine(n); err != nil {
+			t.Errorf("ValidateResultsPerEngine(%d) error = %v", n, err)
+		}
+	}
+	if err := ValidateResultsPerEngine(-1); err == nil {
+		t.Error("ValidateResultsPerEngine(-1) expected error")
+	}
+}
+
+func TestValidateMinContentLength(t *testing.T) {
+	for _, n := range []int{0, 1, 200} {
+		if err := ValidateMinContentLength(n); err != nil {
+			t.Errorf("ValidateMinContentLength(%d) error = %v", n, err)
+		}
+	}
+	if err := ValidateMinContentLength(-1); err == nil {
+		t.Error("ValidateMinContentLength(-1) expected error")
+	}
+}
+
+func TestValidateOffset(t *testing.T) {
+	for _, tt := range []struct{ offset, page int }{{0, 1}, {10, 1}, {10, 0}, {0, 3}} {
+		if err := ValidateOffset(tt.offset, tt.page); err != nil {
+			t.Errorf("ValidateOffset(%d, %d) error = %v", tt.offset, tt.page, err)
+		}
+	}
+	for _, tt := range []struct{ offset, page int }{{-1, 1}, {10, 2}} {
+		if err := ValidateOffset(tt.offset, tt.page); err == nil {
+			t.Errorf("ValidateOffset(%d, %d) expected error", tt.offset, tt.page)
+		}
+	}
+}
+
+func TestValidateMinResults(t *testing.T) {
+	for _, n := range []int{0, 1, 100} {
+		if err := ValidateMinResults(n); err != nil {
+			t.Errorf("ValidateMinResults(%d) error = %v", n, err)
+		}
+	}
+	for _, n := range []int{-1, 101} {
+		if err := ValidateMinResults(n); err == nil {
+			t.Errorf("ValidateMinResults(%d) expected error", n)
+		}
+	}
+}
+
 func TestValidateCategory(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -266,6 +607,19 @@ func TestValidateCategory(t *testing.T) {
 	}
 }
 
+// TestValidateCategoryNames tests that every category the categories
+// command lists is known to the validator
+func TestValidateCategoryNames(t *testing.T) {
+	for _, name := range searxng.GetCategoryNames() {
+		if err := ValidateCategory(name); err != nil {
+			t.Errorf("ValidateCategory(%q) error = %v", name, err)
+		}
+		if !searxng.IsValidCategory(name) {
+			t.Errorf("IsValidCategory(%q) = false", name)
+		}
+	}
+}
+
 func TestValidationError(t *testing.T) {
 	err := ValidationError{
 		Field:   "test_field",
@@ -277,4 +631,36 @@ func TestValidationError(t *testing.T) {
 	if err.Error() != expected {
 		t.Errorf("ValidationError.Error() = %v, want %v", err.Error(), expected)
 	}
-}
\ No newline at end of file
+}
+func TestValidationErrorExitCode(t *testing.T) {
+	err := ValidateSafeSearch(5)
+	if got := errors.ExitCode(err); got != errors.ExitInvalidInput {
+		t.Errorf("ExitCode() for a validation error = %d, want %d", got, errors.ExitInvalidInput)
+	}
+}
+
+func TestValidateParam(t *testing.T) {
+	for _, p := range []string{"theme=simple", "enabled_plugins=Hash_plugin,Tracker_URL_remover", "image_proxy=", "token=a=b"} {
+		if err := ValidateParam(p); err != nil {
+			t.Errorf("ValidateParam(%q) error = %v", p, err)
+		}
+	}
+	for _, p := range []string{"theme", "=simple", "bad key=1", ""} {
+		if err := ValidateParam(p); err == nil {
+			t.Errorf("ValidateParam(%q) expected error", p)
+		}
+	}
+}
+
+func TestValidateWidth(t *testing.T) {
+	for _, w := range []int{0, MinWidth, 80, 300} {
+		if err := ValidateWidth(w); err != nil {
+			t.Errorf("ValidateWidth(%d) error = %v", w, err)
+		}
+	}
+	for _, w := range []int{-1, 1, MinWidth - 1} {
+		if err := ValidateWidth(w); err == nil {
+			t.Errorf("ValidateWidth(%d) expected error", w)
+		}
+	}
+}
@@ -30,3 +30,26 @@ var BuildDate = "unknown"
 // Set at build time using ldflags.
 // Default: "unknown"
 var GoVersion = "unknown"
+
+// Info is the build information in machine-readable form.
+type Info struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"git_commit"`
+	BuildDate string `json:"build_date"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get returns the current build information.
+//
+// Example:
+//
+//	data, _ := json.Marshal(version.Get())
+//	// {"version":"1.0.0","git_commit":"unknown",...}
+func Get() Info {
+	return Info{
+		Version:   Version,
+		GitCommit: GitCommit,
+		BuildDate: BuildDate,
+		GoVersion: GoVersion,
+	}
+}